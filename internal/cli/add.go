@@ -4,8 +4,11 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/pavanprakash21/totp-manager-go/internal/otpauth"
+	"github.com/pavanprakash21/totp-manager-go/internal/qr"
 	"github.com/pavanprakash21/totp-manager-go/internal/storage"
 	"github.com/pavanprakash21/totp-manager-go/internal/totp"
 )
@@ -17,13 +20,91 @@ func AddCommand(args []string) int {
 	fs := flag.NewFlagSet("add", flag.ExitOnError)
 	name := fs.String("name", "", "Service name (required)")
 	identifier := fs.String("identifier", "", "Optional identifier (e.g., email, username)")
+	loginURL := fs.String("url", "", "Optional login page URL (http or https)")
 	secret := fs.String("secret", "", "Base32 TOTP secret (required)")
+	secretFromQR := fs.String("secret-from-qr", "", "Read the secret from a QR code image (PNG/JPEG) instead of --secret")
+	fs.StringVar(secretFromQR, "qr", "", "Shorthand for --secret-from-qr")
+	uri := fs.String("uri", "", "Read the secret, label and parameters from an otpauth://totp/... URI instead of --secret")
+	promptForSecret := fs.Bool("prompt-secret", false, "Enter the secret interactively (hidden, asked twice) instead of --secret")
+	algorithm := fs.String("algorithm", "", "HMAC algorithm: SHA1, SHA256 or SHA512 (default SHA1)")
+	digits := fs.Int("digits", 0, "Code length, 6-8 (default 6)")
+	period := fs.Int("period", 0, "Code period in seconds (default 30)")
+	codeType := fs.String("type", storage.TypeTOTP, "Code type: totp, or steam for 5-character Steam Guard codes (the secret may be Steam's Base64 shared_secret)")
+	noAtomic := fs.Bool("no-atomic", false, noAtomicUsage)
+	allowWeak := fs.Bool("allow-weak-secret", false, "Accept valid Base32 secrets shorter than 16 characters (with a warning)")
+	strictNames := fs.Bool("strict-names", false, "Only allow letters, digits, spaces and -_.@+() in the name")
+	var recoveryCodes stringList
+	fs.Var(&recoveryCodes, "recovery-code", "Backup/recovery code to store with the service (repeatable)")
 
 	if err := fs.Parse(args); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
 		return 1 // T065: Exit code 1 for errors
 	}
 
+	// Only one secret source may be used
+	sources := 0
+	for _, set := range []bool{*secret != "", *secretFromQR != "", *uri != "", *promptForSecret} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		fmt.Fprintln(os.Stderr, "Error: use only one of --secret, --secret-from-qr, --uri and --prompt-secret")
+		return 1
+	}
+
+	if err := storage.ValidateType(*codeType); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid --type: %v\n", err)
+		return 1
+	}
+	steam := *codeType == storage.TypeSteam
+	if steam && (*algorithm != "" || *digits != 0 || *period != 0) {
+		fmt.Fprintln(os.Stderr, "Error: --algorithm, --digits and --period cannot be used with --type steam")
+		return 1
+	}
+
+	// Pull the secret (and label defaults) from a QR image or otpauth URI;
+	// explicit flags win
+	var imported *storage.Service
+	switch {
+	case *secretFromQR != "":
+		key, err := readQRKey(*secretFromQR)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		service := key.Service(time.Now())
+		imported = &service
+	case *uri != "":
+		service, err := otpauth.ImportFromURI(*uri, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid --uri: %v\n", err)
+			return 1
+		}
+		imported = &service
+	}
+	if imported != nil {
+		*secret = imported.Secret
+		if *name == "" {
+			*name = imported.Name
+		}
+		if *identifier == "" {
+			*identifier = imported.Identifier
+		}
+		// Steam codes have fixed parameters
+		if !steam {
+			if *algorithm == "" {
+				*algorithm = imported.Algorithm
+			}
+			if *digits == 0 {
+				*digits = imported.Digits
+			}
+			if *period == 0 {
+				*period = imported.Period
+			}
+		}
+	}
+
 	// Validate required flags
 	if *name == "" {
 		fmt.Fprintln(os.Stderr, "Error: --name is required")
@@ -31,16 +112,75 @@ func AddCommand(args []string) int {
 		return 1
 	}
 
+	if *strictNames {
+		if err := storage.ValidateStrictServiceName(*name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	if *promptForSecret {
+		entered, err := promptSecret()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		*secret = entered
+	}
+
 	if *secret == "" {
 		fmt.Fprintln(os.Stderr, "Error: --secret is required")
 		fmt.Fprintln(os.Stderr, "Usage: totp add --name SERVICE_NAME --secret BASE32_SECRET")
 		return 1
 	}
 
+	if err := storage.ValidateURL(*loginURL); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid --url: %v\n", err)
+		return 1
+	}
+
+	if err := storage.ValidateRecoveryCodes(recoveryCodes); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid recovery codes: %v\n", err)
+		return 1
+	}
+
+	*secret = inputSecret(*secret, steam)
+
 	// T062: Validate Base32 secret
+	weakSecret := false
 	if err := totp.ValidateSecret(*secret); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Invalid TOTP secret: %v\n", err)
-		fmt.Fprintln(os.Stderr, "Secret must be valid Base32 (A-Z, 2-7) and at least 16 characters")
+		if !*allowWeak || totp.ValidateSecretEncoding(*secret) != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid TOTP secret: %v\n", err)
+			fmt.Fprintln(os.Stderr, "Secret must be valid Base32 (A-Z, 2-7) and at least 16 characters")
+			return 1
+		}
+		weakSecret = true
+		fmt.Fprintf(os.Stderr, "Warning: %v; accepting it because of --allow-weak-secret\n", err)
+	}
+
+	// Create new service
+	service := storage.Service{
+		Name:            *name,
+		Identifier:      *identifier,
+		URL:             *loginURL,
+		Secret:          *secret,
+		CreatedAt:       time.Now(),
+		Recovery:        recoveryCodes,
+		Algorithm:       strings.ToUpper(*algorithm),
+		Digits:          *digits,
+		Period:          *period,
+		AllowWeakSecret: weakSecret,
+	}
+	if steam {
+		service.Type = storage.TypeSteam
+	}
+	if imported != nil {
+		service.Issuer = imported.Issuer
+		service.Label = imported.Label
+	}
+
+	if err := totp.ValidateParams(service.EffectiveAlgorithm(), service.EffectiveDigits(), service.EffectivePeriod()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return 1
 	}
 
@@ -50,6 +190,7 @@ func AddCommand(args []string) int {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return 1
 	}
+	app.noAtomic = *noAtomic
 
 	// T060: Load storage (prompts for passphrase if exists, creates if not)
 	if err := app.Initialize(); err != nil {
@@ -58,20 +199,12 @@ func AddCommand(args []string) int {
 	}
 
 	// T061: Check for duplicate name
-	if _, err := app.store.GetService(*name); err == nil {
+	if _, err := app.store.GetServiceCopy(*name); err == nil {
 		fmt.Fprintf(os.Stderr, "Error: Service '%s' already exists\n", *name)
 		fmt.Fprintln(os.Stderr, "Use a different name or remove the existing service first")
 		return 1
 	}
 
-	// Create new service
-	service := storage.Service{
-		Name:       *name,
-		Identifier: *identifier,
-		Secret:     *secret,
-		CreatedAt:  time.Now(),
-	}
-
 	// Add service to storage
 	if err := app.store.AddService(service); err != nil {
 		fmt.Fprintf(os.Stderr, "Error adding service: %v\n", err)
@@ -85,8 +218,82 @@ func AddCommand(args []string) int {
 	}
 
 	// T064: Success message to stdout
-	fmt.Printf("✓ Service '%s' added successfully\n", *name)
+	fmt.Printf("✓ Service '%s' added successfully (%s)\n", *name, describeParams(&service))
 	fmt.Println("✓ Storage updated and encrypted")
 
 	return 0 // T065: Exit code 0 for success
 }
+
+// describeParams summarizes a service's effective code parameters,
+// e.g. "SHA1, 6 digits, 30s" or "Steam Guard, 5 characters, 30s"
+func describeParams(service *storage.Service) string {
+	if service.Type == storage.TypeSteam {
+		return fmt.Sprintf("Steam Guard, %d characters, %ds", service.CodeLength(), service.EffectivePeriod())
+	}
+	return fmt.Sprintf("%s, %d digits, %ds", service.EffectiveAlgorithm(), service.EffectiveDigits(), service.EffectivePeriod())
+}
+
+// inputSecret returns an entered secret in canonical form, so padded and
+// unpadded input match. Steam services also accept the Base64 shared_secret
+// that Steam tools export.
+func inputSecret(secret string, steam bool) string {
+	if steam && totp.ValidateSecretEncoding(secret) != nil {
+		if converted, err := totp.SteamSecretFromBase64(secret); err == nil {
+			secret = converted
+		}
+	}
+	return totp.NormalizeSecret(secret)
+}
+
+// promptSecret reads a secret twice without echo, normalizes both entries
+// and returns the secret once they match and it is valid Base32
+func promptSecret() (string, error) {
+	fmt.Print("Enter secret: ")
+	first, err := readPassword()
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret: %w", err)
+	}
+	fmt.Println()
+
+	fmt.Print("Confirm secret: ")
+	second, err := readPassword()
+	if err != nil {
+		return "", fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	fmt.Println()
+
+	// Validation is left to AddCommand, which applies --allow-weak-secret
+	secret := totp.NormalizeSecret(first)
+	if secret != totp.NormalizeSecret(second) {
+		return "", fmt.Errorf("secrets do not match")
+	}
+
+	return secret, nil
+}
+
+// readQRKey decodes a QR code image and parses the otpauth URI it contains
+func readQRKey(path string) (*otpauth.Key, error) {
+	text, err := qr.Decode(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read QR code: %w", err)
+	}
+
+	key, err := otpauth.Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("QR code does not contain a valid otpauth URI: %w", err)
+	}
+
+	return key, nil
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
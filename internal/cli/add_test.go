@@ -1,9 +1,15 @@
 package cli
 
 import (
+	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
 )
 
 func TestAddCommand_MissingName(t *testing.T) {
@@ -38,6 +44,45 @@ func TestAddCommand_ShortSecret(t *testing.T) {
 	}
 }
 
+// TestAddCommand_AllowWeakSecret tests short valid Base32 secrets need --allow-weak-secret
+func TestAddCommand_AllowWeakSecret(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	// Rejected by default
+	if code := AddCommand([]string{"--name", "Legacy", "--secret", "JBSWY3DPEH"}); code != 1 {
+		t.Errorf("AddCommand() without flag = %d, want 1", code)
+	}
+
+	// Invalid Base32 is rejected even with the flag
+	if code := AddCommand([]string{"--name", "Legacy", "--secret", "JBSWY3DP1!", "--allow-weak-secret"}); code != 1 {
+		t.Errorf("AddCommand() with invalid Base32 = %d, want 1", code)
+	}
+
+	var code int
+	captureStdout(t, func() {
+		code = AddCommand([]string{"--name", "Legacy", "--secret", "JBSWY3DPEH", "--allow-weak-secret"})
+	})
+	if code != 0 {
+		t.Fatalf("AddCommand() with flag = %d, want 0", code)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	service, err := store.GetServiceCopy("Legacy")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if !service.AllowWeakSecret {
+		t.Error("AllowWeakSecret should be recorded on the service")
+	}
+	if errs := store.Validate(); len(errs) != 0 {
+		t.Errorf("Storage with an accepted weak secret should validate, got %v", errs)
+	}
+}
+
 func TestAddCommand_WithIdentifier(t *testing.T) {
 	// Create a temporary directory for test storage
 	tempDir := t.TempDir()
@@ -107,3 +152,534 @@ func TestAddCommand_FlagParsing(t *testing.T) {
 		})
 	}
 }
+
+// writeQRImage encodes content as a QR code PNG file
+func writeQRImage(t *testing.T, path, content string) {
+	t.Helper()
+
+	matrix, err := qrcode.NewQRCodeWriter().Encode(content, gozxing.BarcodeFormat_QR_CODE, 256, 256, nil)
+	if err != nil {
+		t.Fatalf("Failed to encode QR: %v", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create image file: %v", err)
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, matrix); err != nil {
+		t.Fatalf("Failed to write image: %v", err)
+	}
+}
+
+// TestAddCommand_SecretFromQR tests the secret comes from the QR and --name overrides the label
+func TestAddCommand_SecretFromQR(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	qrPath := filepath.Join(t.TempDir(), "screenshot.png")
+	writeQRImage(t, qrPath, "otpauth://totp/GitHub:octocat?issuer=GitHub&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+
+	var code int
+	captureStdout(t, func() {
+		code = AddCommand([]string{"--name", "Work GitHub", "--secret-from-qr", qrPath})
+	})
+	if code != 0 {
+		t.Fatalf("AddCommand() = %d, want 0", code)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	service, err := store.GetServiceCopy("Work GitHub")
+	if err != nil {
+		t.Fatalf("Explicit --name should override the QR label: %v", err)
+	}
+	if service.Secret != "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" {
+		t.Errorf("Secret = %q, want the secret from the QR code", service.Secret)
+	}
+	if service.Identifier != "octocat" {
+		t.Errorf("Identifier = %q, want %q from the QR label", service.Identifier, "octocat")
+	}
+	if _, err := store.GetServiceCopy("GitHub"); err == nil {
+		t.Error("QR issuer should not be used as the name when --name is given")
+	}
+}
+
+// TestAddCommand_QRAlias tests --qr reads the whole service from a QR image
+func TestAddCommand_QRAlias(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	qrPath := filepath.Join(t.TempDir(), "screenshot.png")
+	writeQRImage(t, qrPath, "otpauth://totp/GitHub:octocat?issuer=GitHub&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&digits=8")
+
+	var code int
+	captureStdout(t, func() {
+		code = AddCommand([]string{"--qr", qrPath})
+	})
+	if code != 0 {
+		t.Fatalf("AddCommand() = %d, want 0", code)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	service, err := store.GetServiceCopy("GitHub")
+	if err != nil {
+		t.Fatalf("The QR issuer should name the service: %v", err)
+	}
+	if service.Identifier != "octocat" || service.Digits != 8 || service.Secret != "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ" {
+		t.Errorf("Service = %q, %d digits, secret %q; want octocat, 8 and the QR secret", service.Identifier, service.Digits, service.Secret)
+	}
+
+	if code := AddCommand([]string{"--qr", qrPath, "--uri", "otpauth://totp/X?secret=JBSWY3DPEHPK3PXP"}); code != 1 {
+		t.Errorf("AddCommand(--qr with --uri) = %d, want 1", code)
+	}
+}
+
+// TestAddCommand_SecretFromQR_Conflicts tests invalid --secret-from-qr usage
+func TestAddCommand_SecretFromQR_Conflicts(t *testing.T) {
+	dir := t.TempDir()
+	notQR := filepath.Join(dir, "blank.png")
+	if err := os.WriteFile(notQR, []byte("not an image"), 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"With --secret", []string{"--name", "GitHub", "--secret", "JBSWY3DPEHPK3PXP", "--secret-from-qr", notQR}},
+		{"Unreadable image", []string{"--name", "GitHub", "--secret-from-qr", notQR}},
+		{"Missing image", []string{"--name", "GitHub", "--secret-from-qr", filepath.Join(dir, "missing.png")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := AddCommand(tt.args); code != 1 {
+				t.Errorf("AddCommand() = %d, want 1", code)
+			}
+		})
+	}
+}
+
+// TestAddCommand_URI tests --uri fills the name, identifier and parameters
+// from the otpauth URI and keeps its issuer and label
+func TestAddCommand_URI(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = AddCommand([]string{"--uri", "otpauth://totp/ACME:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME&algorithm=SHA256&digits=8&period=60"})
+	})
+	if code != 0 {
+		t.Fatalf("AddCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "(SHA256, 8 digits, 60s)") {
+		t.Errorf("Output = %q, want the URI's parameters", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	service, err := store.GetServiceCopy("ACME")
+	if err != nil {
+		t.Fatalf("The issuer should name the service: %v", err)
+	}
+	if service.Identifier != "alice@example.com" || service.Secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Identifier %q secret %q, want alice@example.com and the URI secret", service.Identifier, service.Secret)
+	}
+	if service.Issuer != "ACME" || service.Label != "ACME:alice@example.com" {
+		t.Errorf("Issuer %q label %q, want the URI's provenance", service.Issuer, service.Label)
+	}
+}
+
+// TestAddCommand_URI_Invalid tests malformed URIs and conflicting sources
+func TestAddCommand_URI_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Wrong scheme", []string{"--uri", "https://example.com/?secret=JBSWY3DPEHPK3PXP"}},
+		{"HOTP", []string{"--uri", "otpauth://hotp/GitHub?secret=JBSWY3DPEHPK3PXP&counter=1"}},
+		{"No secret", []string{"--uri", "otpauth://totp/GitHub"}},
+		{"Bad digits", []string{"--uri", "otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP&digits=12"}},
+		{"With --secret", []string{"--uri", "otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP", "--secret", "JBSWY3DPEHPK3PXP"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := AddCommand(tt.args); code != 1 {
+				t.Errorf("AddCommand() = %d, want 1", code)
+			}
+		})
+	}
+}
+
+// TestAddCommand_Steam tests adding a Steam Guard service from a Base64
+// shared_secret
+func TestAddCommand_Steam(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = AddCommand([]string{"--name", "Steam", "--type", "steam", "--secret", "SGVsbG8h3q2+7w=="})
+	})
+	if code != 0 {
+		t.Fatalf("AddCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "(Steam Guard, 5 characters, 30s)") {
+		t.Errorf("Output = %q, want the Steam parameters", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	service, err := store.GetServiceCopy("Steam")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if service.Type != storage.TypeSteam || service.Secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Type %q secret %q, want steam and the Base32 form of the shared secret", service.Type, service.Secret)
+	}
+}
+
+// TestAddCommand_Type_Invalid tests unknown types and Steam with custom parameters
+func TestAddCommand_Type_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Unknown type", []string{"--type", "hotp"}},
+		{"Steam with digits", []string{"--type", "steam", "--digits", "8"}},
+		{"Steam with algorithm", []string{"--type", "steam", "--algorithm", "SHA256"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--name", "Steam", "--secret", "JBSWY3DPEHPK3PXP"}, tt.args...)
+			if code := AddCommand(args); code != 1 {
+				t.Errorf("AddCommand(%v) = %d, want 1", args, code)
+			}
+		})
+	}
+}
+
+// TestAddCommand_RecoveryCodes tests repeatable --recovery-code flags are stored
+func TestAddCommand_RecoveryCodes(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	captureStdout(t, func() {
+		code = AddCommand([]string{
+			"--name", "GitHub",
+			"--secret", "JBSWY3DPEHPK3PXP",
+			"--recovery-code", "aaaa-bbbb",
+			"--recovery-code", "cccc-dddd",
+		})
+	})
+	if code != 0 {
+		t.Fatalf("AddCommand() = %d, want 0", code)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	service, err := store.GetServiceCopy("GitHub")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if len(service.Recovery) != 2 || service.Recovery[0] != "aaaa-bbbb" || service.Recovery[1] != "cccc-dddd" {
+		t.Errorf("Recovery = %v, want [aaaa-bbbb cccc-dddd]", service.Recovery)
+	}
+}
+
+// TestAddCommand_SuccessParams tests the success message echoes the effective parameters
+func TestAddCommand_SuccessParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantLine string
+	}{
+		{
+			name:     "Defaults applied",
+			args:     []string{"--name", "GitHub", "--secret", "JBSWY3DPEHPK3PXP"},
+			wantLine: "✓ Service 'GitHub' added successfully (SHA1, 6 digits, 30s)",
+		},
+		{
+			name:     "Explicit flags",
+			args:     []string{"--name", "Okta", "--secret", "JBSWY3DPEHPK3PXP", "--algorithm", "sha256", "--digits", "8", "--period", "60"},
+			wantLine: "✓ Service 'Okta' added successfully (SHA256, 8 digits, 60s)",
+		},
+		{
+			name:     "Partial flags",
+			args:     []string{"--name", "AWS", "--secret", "JBSWY3DPEHPK3PXP", "--digits", "7"},
+			wantLine: "✓ Service 'AWS' added successfully (SHA1, 7 digits, 30s)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestStorage(t, "test-passphrase")
+			t.Setenv(passphraseEnvVar, "test-passphrase")
+
+			var code int
+			out := captureStdout(t, func() {
+				code = AddCommand(tt.args)
+			})
+			if code != 0 {
+				t.Fatalf("AddCommand() = %d, want 0", code)
+			}
+			if !strings.Contains(out, tt.wantLine) {
+				t.Errorf("Output = %q, want line %q", out, tt.wantLine)
+			}
+		})
+	}
+}
+
+// TestAddCommand_StoresParams tests explicit parameters are persisted
+func TestAddCommand_StoresParams(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	captureStdout(t, func() {
+		code = AddCommand([]string{"--name", "Okta", "--secret", "JBSWY3DPEHPK3PXP", "--algorithm", "SHA512", "--digits", "8"})
+	})
+	if code != 0 {
+		t.Fatalf("AddCommand() = %d, want 0", code)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	service, err := store.GetServiceCopy("Okta")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if service.Algorithm != "SHA512" || service.Digits != 8 || service.Period != 0 {
+		t.Errorf("Stored params = %s/%d/%d, want SHA512/8/0", service.Algorithm, service.Digits, service.Period)
+	}
+}
+
+// TestAddCommand_StrictNames tests --strict-names rejects a comma while the
+// default still accepts it
+func TestAddCommand_StrictNames(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	if code := AddCommand([]string{"--name", "Acme, Inc", "--secret", "JBSWY3DPEHPK3PXP", "--strict-names"}); code != 1 {
+		t.Errorf("AddCommand(--strict-names) = %d, want 1", code)
+	}
+
+	var code int
+	captureStdout(t, func() {
+		code = AddCommand([]string{"--name", "Acme, Inc", "--secret", "JBSWY3DPEHPK3PXP"})
+	})
+	if code != 0 {
+		t.Fatalf("AddCommand() = %d, want 0 without --strict-names", code)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(store.Services) != 1 {
+		t.Errorf("Services = %d, want only the non-strict add", len(store.Services))
+	}
+}
+
+// TestAddCommand_InvalidParams tests out-of-range parameters are rejected
+func TestAddCommand_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Unknown algorithm", []string{"--algorithm", "MD5"}},
+		{"Too many digits", []string{"--digits", "9"}},
+		{"Negative period", []string{"--period", "-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--name", "GitHub", "--secret", "JBSWY3DPEHPK3PXP"}, tt.args...)
+			if code := AddCommand(args); code != 1 {
+				t.Errorf("AddCommand() = %d, want 1", code)
+			}
+		})
+	}
+}
+
+// TestAddCommand_InvalidRecoveryCode tests invalid recovery codes are rejected
+func TestAddCommand_InvalidRecoveryCode(t *testing.T) {
+	code := AddCommand([]string{"--name", "GitHub", "--secret", "JBSWY3DPEHPK3PXP", "--recovery-code", " "})
+	if code != 1 {
+		t.Errorf("AddCommand() = %d, want 1", code)
+	}
+}
+
+// TestAddCommand_URL tests that --url is stored with the service
+func TestAddCommand_URL(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	captureStdout(t, func() {
+		code = AddCommand([]string{"--name", "GitHub", "--secret", "JBSWY3DPEHPK3PXP", "--url", "https://github.com/login"})
+	})
+	if code != 0 {
+		t.Fatalf("AddCommand() = %d, want 0", code)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	service, err := store.GetServiceCopy("GitHub")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if service.URL != "https://github.com/login" {
+		t.Errorf("URL = %q, want https://github.com/login", service.URL)
+	}
+}
+
+// TestAddCommand_InvalidURL tests that non-http(s) URLs are rejected
+func TestAddCommand_InvalidURL(t *testing.T) {
+	code := AddCommand([]string{"--name", "GitHub", "--secret", "JBSWY3DPEHPK3PXP", "--url", "javascript:alert(1)"})
+	if code != 1 {
+		t.Errorf("AddCommand() = %d, want 1", code)
+	}
+}
+
+// withStdin replaces os.Stdin with a pipe containing input for the test
+func withStdin(t *testing.T, input string) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	if _, err := w.WriteString(input); err != nil {
+		t.Fatalf("Failed to write stdin: %v", err)
+	}
+	w.Close()
+
+	oldStdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = oldStdin
+		r.Close()
+	})
+}
+
+// TestPromptSecret tests the non-TTY secret prompt path
+func TestPromptSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"Matching normalized entries", "jbsw y3dp ehpk 3pxp\nJBSWY3DPEHPK3PXP\n", "JBSWY3DPEHPK3PXP", false},
+		{"Mismatched entries", "JBSWY3DPEHPK3PXP\nGEZDGNBVGY3TQOJQ\n", "", true},
+		{"Matching but short (validated by AddCommand)", "JBSWY3DPEH\njbswy3dpeh\n", "JBSWY3DPEH", false},
+		{"Missing confirmation", "JBSWY3DPEHPK3PXP\n", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, tt.input)
+
+			var got string
+			var err error
+			captureStdout(t, func() {
+				got, err = promptSecret()
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("promptSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("promptSecret() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAddCommand_PromptSecret tests --prompt-secret stores the normalized secret
+func TestAddCommand_PromptSecret(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	withStdin(t, "jbsw y3dp ehpk 3pxp\njbsw y3dp ehpk 3pxp\n")
+
+	var code int
+	captureStdout(t, func() {
+		code = AddCommand([]string{"--name", "GitHub", "--prompt-secret"})
+	})
+	if code != 0 {
+		t.Fatalf("AddCommand() = %d, want 0", code)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	service, err := store.GetServiceCopy("GitHub")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if service.Secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Secret = %q, want normalized JBSWY3DPEHPK3PXP", service.Secret)
+	}
+}
+
+// TestAddCommand_PromptSecretWeak tests a short prompted secret is rejected
+// unless --allow-weak-secret is given, like one passed with --secret
+func TestAddCommand_PromptSecretWeak(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	withStdin(t, "JBSWY3DPEH\nJBSWY3DPEH\n")
+	var code int
+	captureStdout(t, func() { code = AddCommand([]string{"--name", "Legacy", "--prompt-secret"}) })
+	if code != 1 {
+		t.Errorf("AddCommand(short prompted secret) = %d, want 1", code)
+	}
+
+	withStdin(t, "JBSWY3DPEH\nJBSWY3DPEH\n")
+	captureStdout(t, func() {
+		code = AddCommand([]string{"--name", "Legacy", "--prompt-secret", "--allow-weak-secret"})
+	})
+	if code != 0 {
+		t.Fatalf("AddCommand(--allow-weak-secret) = %d, want 0", code)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if service, err := store.GetServiceCopy("Legacy"); err != nil || service.Secret != "JBSWY3DPEH" || !service.AllowWeakSecret {
+		t.Errorf("Legacy = %+v, %v; want the short secret kept as weak", service, err)
+	}
+}
+
+// TestAddCommand_PromptSecretWithSecret tests --prompt-secret conflicts with --secret
+func TestAddCommand_PromptSecretWithSecret(t *testing.T) {
+	code := AddCommand([]string{"--name", "GitHub", "--secret", "JBSWY3DPEHPK3PXP", "--prompt-secret"})
+	if code != 1 {
+		t.Errorf("AddCommand() = %d, want 1", code)
+	}
+}
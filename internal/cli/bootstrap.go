@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+	"github.com/pavanprakash21/totp-manager-go/internal/totp"
+)
+
+// bootstrapEnvVar holds a JSON array of services used to seed a new store
+const bootstrapEnvVar = "TOTP_BOOTSTRAP_JSON"
+
+// MigrateFromEnvCommand seeds a new store from TOTP_BOOTSTRAP_JSON so
+// ephemeral containers can be provisioned without interactive add. It is a
+// no-op when a store already exists, so it is safe to run on every start.
+func MigrateFromEnvCommand(args []string) int {
+	fs := flag.NewFlagSet("migrate-from-env", flag.ExitOnError)
+	noAtomic := fs.Bool("no-atomic", false, noAtomicUsage)
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// First run only: never touch an existing store
+	if _, err := os.Stat(app.storagePath); err == nil {
+		fmt.Println("✓ Storage already exists; bootstrap skipped")
+		return 0
+	}
+
+	raw := os.Getenv(bootstrapEnvVar)
+	if strings.TrimSpace(raw) == "" {
+		fmt.Fprintf(os.Stderr, "Error: %s is not set\n", bootstrapEnvVar)
+		return 1
+	}
+
+	// Validate everything before creating the file, so bad input leaves no store
+	services, err := parseBootstrap(raw)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid %s: %v\n", bootstrapEnvVar, err)
+		return 1
+	}
+
+	passphrase, ok := os.LookupEnv(passphraseEnvVar)
+	if !ok {
+		if passphrase, err = app.promptNewPassphrase(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: passphrase setup failed: %v\n", err)
+			return 1
+		}
+	}
+	if err := validateNewPassphrase(passphrase); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	store, err := storage.Create(app.storagePath, passphrase)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to create storage: %v\n", err)
+		return 1
+	}
+	store.SetNoAtomic(*noAtomic)
+
+	for _, service := range services {
+		if service.ClampCreatedAt(time.Now()) {
+			fmt.Fprintf(os.Stderr, "Warning: service '%s': created_at is in the future; using the current time\n", service.Name)
+		}
+		if err := store.AddService(service); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: service '%s': %v\n", service.Name, err)
+			return 1
+		}
+	}
+
+	if err := store.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving storage: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✓ Bootstrapped %d services from %s\n", len(services), bootstrapEnvVar)
+	fmt.Printf("✓ Storage location: %s\n", app.storagePath)
+	return 0
+}
+
+// parseBootstrap decodes a JSON array of services (the storage field names,
+// e.g. [{"name":"GitHub","secret":"..."}]), normalizing secrets and
+// validating each entry
+func parseBootstrap(raw string) ([]storage.Service, error) {
+	var services []storage.Service
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&services); err != nil {
+		return nil, fmt.Errorf("expected a JSON array of services: %w", err)
+	}
+
+	seen := make(map[string]bool, len(services))
+	for i := range services {
+		service := &services[i]
+		service.Secret = totp.NormalizeSecret(service.Secret)
+		service.Algorithm = strings.ToUpper(service.Algorithm)
+		if service.CreatedAt.IsZero() {
+			service.CreatedAt = time.Now()
+		}
+
+		if err := service.Validate(); err != nil {
+			return nil, fmt.Errorf("entry %d (%q): %w", i+1, service.Name, err)
+		}
+
+		key := storage.NameKey(service.Name)
+		if seen[key] {
+			return nil, fmt.Errorf("entry %d: duplicate service name %q", i+1, service.Name)
+		}
+		seen[key] = true
+	}
+
+	return services, nil
+}
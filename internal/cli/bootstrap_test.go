@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// bootstrapHome points storage at an empty temp home and returns the store path
+func bootstrapHome(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(storage.StorageDirEnvVar, "")
+	return filepath.Join(home, ".config", "totp-manager", "secrets.enc")
+}
+
+// TestMigrateFromEnvCommand_CreatesServices tests bootstrapping an empty store
+func TestMigrateFromEnvCommand_CreatesServices(t *testing.T) {
+	path := bootstrapHome(t)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	t.Setenv(bootstrapEnvVar, `[
+		{"name": "GitHub", "identifier": "alice@example.com", "secret": "jbsw y3dp ehpk 3pxp"},
+		{"name": "AWS", "secret": "GEZDGNBVGY3TQOJQ", "algorithm": "sha256", "digits": 8}
+	]`)
+
+	var code int
+	out := captureStdout(t, func() { code = MigrateFromEnvCommand(nil) })
+	if code != 0 {
+		t.Fatalf("MigrateFromEnvCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "Bootstrapped 2 services") {
+		t.Errorf("Output = %q, want bootstrap summary", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(store.Services) != 2 {
+		t.Fatalf("Services = %d, want 2", len(store.Services))
+	}
+
+	github, err := store.GetServiceCopy("GitHub")
+	if err != nil {
+		t.Fatalf("GetServiceCopy(GitHub) error = %v", err)
+	}
+	if github.Identifier != "alice@example.com" || github.Secret != "JBSWY3DPEHPK3PXP" || github.CreatedAt.IsZero() {
+		t.Errorf("GitHub = %+v, want identifier, normalized secret and a creation time", github)
+	}
+
+	aws, err := store.GetServiceCopy("AWS")
+	if err != nil {
+		t.Fatalf("GetServiceCopy(AWS) error = %v", err)
+	}
+	if aws.Algorithm != "SHA256" || aws.Digits != 8 {
+		t.Errorf("AWS params = %s/%d, want SHA256/8", aws.Algorithm, aws.Digits)
+	}
+}
+
+// TestMigrateFromEnvCommand_FutureCreatedAt tests a future created_at is
+// clamped to now while a past one is kept
+func TestMigrateFromEnvCommand_FutureCreatedAt(t *testing.T) {
+	path := bootstrapHome(t)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	t.Setenv(bootstrapEnvVar, `[
+		{"name": "Future", "secret": "JBSWY3DPEHPK3PXP", "created_at": "2999-01-01T00:00:00Z"},
+		{"name": "Past", "secret": "JBSWY3DPEHPK3PXP", "created_at": "2021-05-06T07:08:09Z"}
+	]`)
+
+	var code int
+	captureStdout(t, func() { code = MigrateFromEnvCommand(nil) })
+	if code != 0 {
+		t.Fatalf("MigrateFromEnvCommand() = %d, want 0", code)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	future, _ := store.GetServiceCopy("Future")
+	if time.Since(future.CreatedAt).Abs() > time.Minute {
+		t.Errorf("Future CreatedAt = %v, want about now", future.CreatedAt)
+	}
+	past, _ := store.GetServiceCopy("Past")
+	if want := time.Date(2021, 5, 6, 7, 8, 9, 0, time.UTC); !past.CreatedAt.Equal(want) {
+		t.Errorf("Past CreatedAt = %v, want %v", past.CreatedAt, want)
+	}
+}
+
+// TestMigrateFromEnvCommand_SkipsExistingStore tests an existing store is left alone
+func TestMigrateFromEnvCommand_SkipsExistingStore(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase", storage.Service{
+		Name: "Existing", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(),
+	})
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	t.Setenv(bootstrapEnvVar, `[{"name": "GitHub", "secret": "GEZDGNBVGY3TQOJQ"}]`)
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	var code int
+	out := captureStdout(t, func() { code = MigrateFromEnvCommand(nil) })
+	if code != 0 {
+		t.Fatalf("MigrateFromEnvCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "bootstrap skipped") {
+		t.Errorf("Output = %q, want skip notice", out)
+	}
+
+	after, _ := os.ReadFile(path)
+	if string(before) != string(after) {
+		t.Error("Existing store should not be rewritten")
+	}
+}
+
+// TestMigrateFromEnvCommand_InvalidInput tests bad input fails without creating a store
+func TestMigrateFromEnvCommand_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name       string
+		json       string
+		passphrase string
+	}{
+		{"Unset", "", "test-passphrase"},
+		{"Not an array", `{"name": "GitHub"}`, "test-passphrase"},
+		{"Unknown field", `[{"name": "GitHub", "secret": "JBSWY3DPEHPK3PXP", "sekret": "x"}]`, "test-passphrase"},
+		{"Invalid secret", `[{"name": "GitHub", "secret": "not-base32!"}]`, "test-passphrase"},
+		{"Duplicate names", `[{"name": "GitHub", "secret": "JBSWY3DPEHPK3PXP"}, {"name": "github", "secret": "GEZDGNBVGY3TQOJQ"}]`, "test-passphrase"},
+		{"Short passphrase", `[{"name": "GitHub", "secret": "JBSWY3DPEHPK3PXP"}]`, "short"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := bootstrapHome(t)
+			t.Setenv(passphraseEnvVar, tt.passphrase)
+			t.Setenv(bootstrapEnvVar, tt.json)
+
+			if code := MigrateFromEnvCommand(nil); code != 1 {
+				t.Errorf("MigrateFromEnvCommand() = %d, want 1", code)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("Store should not be created, stat error = %v", err)
+			}
+		})
+	}
+}
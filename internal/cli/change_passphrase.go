@@ -1,21 +1,49 @@
 package cli
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"syscall"
 
+	"github.com/pavanprakash21/totp-manager-go/internal/crypto"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
 	"golang.org/x/term"
 )
 
 // ChangePassphraseCommand handles changing the storage passphrase
 func ChangePassphraseCommand(args []string) int {
+	fs := flag.NewFlagSet("change-passphrase", flag.ExitOnError)
+	noAtomic := fs.Bool("no-atomic", false, noAtomicUsage)
+	dryRun := fs.Bool("dry-run", false, "Unlock and report what would change without asking for a new passphrase or writing anything")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
 	// Create app and initialize with current passphrase
 	app, err := NewApp()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return 1
 	}
+	app.noAtomic = *noAtomic
+
+	// A dry run only verifies the current passphrase and never creates a store
+	if *dryRun {
+		if err := app.InitializeExisting(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if err := writePassphraseChangePlan(os.Stdout, app.store); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
 
 	// Load existing storage (prompts for current passphrase)
 	fmt.Println("Changing storage passphrase...")
@@ -42,6 +70,32 @@ func ChangePassphraseCommand(args []string) int {
 	return 0
 }
 
+// writePassphraseChangePlan describes what changing the passphrase would do
+// to store, for --dry-run
+func writePassphraseChangePlan(w io.Writer, store *storage.Store) error {
+	c, err := crypto.ParseCipher(store.Settings.Cipher)
+	if err != nil {
+		return err
+	}
+
+	k, err := crypto.ParseKDF(store.Settings.KDF)
+	if err != nil {
+		return err
+	}
+
+	format := c.String()
+	if store.Settings.Compress {
+		format += ", compressed"
+	}
+
+	fmt.Fprintln(w, "Dry run: current passphrase verified; nothing was written.")
+	fmt.Fprintln(w, "Changing the passphrase would:")
+	fmt.Fprintf(w, "  - re-derive the encryption key from the new passphrase (%s)\n", k)
+	fmt.Fprintln(w, "  - rotate the salt (new random 16-byte salt)")
+	fmt.Fprintf(w, "  - re-encrypt %d services (%s) in %s\n", len(store.Services), format, store.Path())
+	return nil
+}
+
 // promptNewPassphrase prompts for a new passphrase with confirmation
 func promptNewPassphrase() (string, error) {
 	// Get new passphrase
@@ -55,6 +109,9 @@ func promptNewPassphrase() (string, error) {
 	if len(newPass) == 0 {
 		return "", fmt.Errorf("passphrase cannot be empty")
 	}
+	if strings.TrimSpace(string(newPass)) != string(newPass) {
+		return "", errPassphraseWhitespace
+	}
 
 	// Confirm new passphrase
 	fmt.Print("Confirm new passphrase: ")
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestChangePassphraseCommand_DryRun tests the dry run prints the plan and
+// leaves the file byte-for-byte unchanged
+func TestChangePassphraseCommand_DryRun(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		storage.Service{Name: "AWS", Secret: "GEZDGNBVGY3TQOJQ", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	var code int
+	out := captureStdout(t, func() { code = ChangePassphraseCommand([]string{"--dry-run"}) })
+	if code != 0 {
+		t.Fatalf("ChangePassphraseCommand() = %d, want 0", code)
+	}
+	for _, want := range []string{"re-derive", "rotate the salt", "re-encrypt 2 services", "aes-256-gcm", "nothing was written"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output missing %q:\n%s", want, out)
+		}
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("Dry run must not modify the storage file")
+	}
+}
+
+// TestChangePassphraseCommand_DryRunWrongPassphrase tests the dry run still
+// requires the current passphrase
+func TestChangePassphraseCommand_DryRunWrongPassphrase(t *testing.T) {
+	setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "wrong-passphrase")
+
+	var code int
+	captureStdout(t, func() { code = ChangePassphraseCommand([]string{"--dry-run"}) })
+	if code != 1 {
+		t.Errorf("ChangePassphraseCommand() = %d, want 1", code)
+	}
+}
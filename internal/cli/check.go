@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// CheckCommand verifies that the storage file can be unlocked and decrypted
+// without printing any service data. Intended for automated health checks:
+// exit code 0 means the store is readable, 1 means it is not.
+// With --deep, every service is also validated.
+func CheckCommand(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	deep := fs.Bool("deep", false, "Also validate every stored service")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Never create a new store from a health check
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "✗ Storage check failed: %v\n", err)
+		return 1
+	}
+
+	if *deep {
+		if errs := app.store.Validate(); len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+			}
+			fmt.Fprintf(os.Stderr, "✗ Storage check failed: %d problem(s) found\n", len(errs))
+			return 1
+		}
+	}
+
+	fmt.Println("✓ Storage OK")
+	return 0
+}
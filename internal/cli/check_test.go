@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// setupTestStorage creates a saved store at the default path under a temp HOME
+func setupTestStorage(t *testing.T, passphrase string, services ...storage.Service) string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(storage.StorageDirEnvVar, "")
+
+	storagePath := filepath.Join(tempDir, ".config", "totp-manager", "secrets.enc")
+	store, err := storage.Create(storagePath, passphrase)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, service := range services {
+		if err := store.AddService(service); err != nil {
+			t.Fatalf("AddService() error = %v", err)
+		}
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	return storagePath
+}
+
+// TestCheckCommand_Success tests check exits 0 with the correct passphrase
+func TestCheckCommand_Success(t *testing.T) {
+	setupTestStorage(t, "correct-passphrase")
+	t.Setenv(passphraseEnvVar, "correct-passphrase")
+
+	if code := CheckCommand([]string{}); code != 0 {
+		t.Errorf("CheckCommand() = %d, want 0", code)
+	}
+}
+
+// TestCheckCommand_WrongPassphrase tests check exits non-zero with a wrong passphrase
+func TestCheckCommand_WrongPassphrase(t *testing.T) {
+	setupTestStorage(t, "correct-passphrase")
+	t.Setenv(passphraseEnvVar, "wrong-passphrase")
+
+	if code := CheckCommand([]string{}); code == 0 {
+		t.Error("CheckCommand() = 0 with wrong passphrase, want non-zero")
+	}
+}
+
+// TestCheckCommand_MissingStorage tests check fails without creating storage
+func TestCheckCommand_MissingStorage(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(passphraseEnvVar, "any-passphrase")
+
+	if code := CheckCommand([]string{}); code == 0 {
+		t.Error("CheckCommand() = 0 without storage, want non-zero")
+	}
+}
+
+// TestCheckCommand_Deep tests --deep reports invalid services
+func TestCheckCommand_Deep(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase", storage.Service{
+		Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(),
+	})
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	if code := CheckCommand([]string{"--deep"}); code != 0 {
+		t.Fatalf("CheckCommand(--deep) = %d on a valid store, want 0", code)
+	}
+
+	// Inject a duplicate directly (bypassing AddService validation)
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	store.Services = append(store.Services, storage.Service{
+		Name: "github", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(),
+	})
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if code := CheckCommand([]string{}); code != 0 {
+		t.Errorf("CheckCommand() = %d without --deep, want 0", code)
+	}
+	if code := CheckCommand([]string{"--deep"}); code != 1 {
+		t.Errorf("CheckCommand(--deep) = %d with a duplicate, want 1", code)
+	}
+}
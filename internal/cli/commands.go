@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// defaultCommand runs when no subcommand is given (flags go to it)
+const defaultCommand = "open"
+
+// commands maps subcommand names to their handlers
+var commands = map[string]func(args []string) int{
+	"add":               AddCommand,
+	"change-passphrase": ChangePassphraseCommand,
+	"check":             CheckCommand,
+	"config":            ConfigCommand,
+	"copy":              CopyCommand,
+	"debug":             DebugCommand,
+	"edit":              EditCommand,
+	"ensure":            EnsureCommand,
+	"export":            ExportCommand,
+	"export-uris":       ExportURIsCommand,
+	"find":              FindCommand,
+	"generate":          GenerateCommand,
+	"import":            ImportCommand,
+	"import-dir":        ImportDirCommand,
+	"list":              ListCommand,
+	"migrate-from-env":  MigrateFromEnvCommand,
+	"open":              OpenCommand,
+	"qr":                QRCommand,
+	"reencrypt":         ReencryptCommand,
+	"remove":            RemoveCommand,
+	"rename":            RenameCommand,
+	"reset-stats":       ResetStatsCommand,
+	"serve":             ServeCommand,
+	"show-all":          ShowAllCommand,
+	"top":               TopCommand,
+	"vault":             VaultCommand,
+	"version":           VersionCommand,
+	"watch":             WatchCommand,
+}
+
+// Run dispatches command-line arguments (without the program name) to a
+// subcommand and returns the exit code
+func Run(args []string) int {
+	args, err := applyGlobalFlags(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	name, rest := route(args)
+
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q\n", name)
+		fmt.Fprintf(os.Stderr, "Commands: %s\n", strings.Join(commandNames(), ", "))
+		return 1
+	}
+
+	return command(rest)
+}
+
+// applyGlobalFlags consumes the flags that precede the subcommand and apply
+// to every command. --storage DIR sets the storage directory by exporting
+// TOTP_STORAGE_DIR, so every command resolves the same path.
+func applyGlobalFlags(args []string) ([]string, error) {
+	for len(args) > 0 {
+		var dir string
+		switch {
+		case args[0] == "--storage" || args[0] == "-storage":
+			if len(args) < 2 || args[1] == "" {
+				return nil, fmt.Errorf("--storage requires a directory")
+			}
+			dir, args = args[1], args[2:]
+		case strings.HasPrefix(args[0], "--storage=") || strings.HasPrefix(args[0], "-storage="):
+			dir = args[0][strings.Index(args[0], "=")+1:]
+			if dir == "" {
+				return nil, fmt.Errorf("--storage requires a directory")
+			}
+			args = args[1:]
+		default:
+			return args, nil
+		}
+
+		if err := os.Setenv(storage.StorageDirEnvVar, dir); err != nil {
+			return nil, fmt.Errorf("failed to set storage directory: %w", err)
+		}
+	}
+	return args, nil
+}
+
+// route picks the subcommand: the first argument, or the default command
+// when there are no arguments or they start with a flag (e.g. "totp --nerd-fonts")
+func route(args []string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return defaultCommand, args
+	}
+	return args[0], args[1:]
+}
+
+// parseWithName parses a command's flags, taking the service name from the
+// first argument when --name is not given. Flag parsing stops at the first
+// argument, so flags may also follow the name ("totp generate GitHub --copy").
+func parseWithName(fs *flag.FlagSet, args []string, name *string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 && *name == "" {
+		*name = fs.Arg(0)
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return err
+		}
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
+
+// commandNames returns the subcommand names in alphabetical order
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestRoute tests subcommand selection, including the default open command
+func TestRoute(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantName string
+		wantRest []string
+	}{
+		{"no args opens the TUI", nil, "open", nil},
+		{"flags only go to open", []string{"--nerd-fonts"}, "open", []string{"--nerd-fonts"}},
+		{"explicit subcommand", []string{"list", "--json"}, "list", []string{"--json"}},
+		{"explicit open", []string{"open"}, "open", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, rest := route(tt.args)
+			if name != tt.wantName {
+				t.Errorf("route() name = %q, want %q", name, tt.wantName)
+			}
+			if len(rest) != len(tt.wantRest) || (len(rest) > 0 && !reflect.DeepEqual(rest, tt.wantRest)) {
+				t.Errorf("route() rest = %v, want %v", rest, tt.wantRest)
+			}
+		})
+	}
+}
+
+// TestRun_DefaultDispatch tests Run with no arguments invokes the open command
+func TestRun_DefaultDispatch(t *testing.T) {
+	original := commands
+	t.Cleanup(func() { commands = original })
+
+	var called string
+	var gotArgs []string
+	commands = map[string]func([]string) int{
+		"open": func(args []string) int { called = "open"; gotArgs = args; return 0 },
+		"list": func(args []string) int { called = "list"; return 0 },
+	}
+
+	if code := Run(nil); code != 0 {
+		t.Fatalf("Run() = %d, want 0", code)
+	}
+	if called != "open" {
+		t.Errorf("Run() with no args called %q, want open", called)
+	}
+	if len(gotArgs) != 0 {
+		t.Errorf("open args = %v, want none", gotArgs)
+	}
+
+	if code := Run([]string{"list"}); code != 0 || called != "list" {
+		t.Errorf("Run(list) = %d calling %q, want 0 calling list", code, called)
+	}
+}
+
+// TestRun_UnknownCommand tests an unknown subcommand exits 1
+func TestRun_UnknownCommand(t *testing.T) {
+	if code := Run([]string{"frobnicate"}); code != 1 {
+		t.Errorf("Run(frobnicate) = %d, want 1", code)
+	}
+}
+
+// TestRun_StorageFlag tests --storage before the subcommand selects the
+// storage directory and is not passed on to the command
+func TestRun_StorageFlag(t *testing.T) {
+	original := commands
+	t.Cleanup(func() { commands = original })
+	t.Setenv(storage.StorageDirEnvVar, "")
+
+	var called string
+	var gotArgs []string
+	commands = map[string]func([]string) int{
+		"open": func(args []string) int { called = "open"; gotArgs = args; return 0 },
+		"list": func(args []string) int { called = "list"; gotArgs = args; return 0 },
+	}
+
+	dir := t.TempDir()
+	if code := Run([]string{"--storage", dir, "list", "--json"}); code != 0 || called != "list" {
+		t.Fatalf("Run(--storage DIR list) = %d calling %q, want 0 calling list", code, called)
+	}
+	if !reflect.DeepEqual(gotArgs, []string{"--json"}) {
+		t.Errorf("list args = %v, want [--json]", gotArgs)
+	}
+	if path, err := storage.GetDefaultStoragePath(); err != nil || path != filepath.Join(dir, "secrets.enc") {
+		t.Errorf("GetDefaultStoragePath() = %q, %v; want secrets.enc in %s", path, err, dir)
+	}
+
+	other := t.TempDir()
+	if code := Run([]string{"--storage=" + other, "--nerd-fonts"}); code != 0 || called != "open" {
+		t.Fatalf("Run(--storage=DIR --nerd-fonts) = %d calling %q, want 0 calling open", code, called)
+	}
+	if got := os.Getenv(storage.StorageDirEnvVar); got != other {
+		t.Errorf("%s = %q, want %q", storage.StorageDirEnvVar, got, other)
+	}
+
+	if code := Run([]string{"--storage"}); code != 1 {
+		t.Errorf("Run(--storage) without a directory = %d, want 1", code)
+	}
+}
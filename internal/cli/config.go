@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/clipboard"
+	"github.com/pavanprakash21/totp-manager-go/internal/crypto"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// ConfigCommand shows or updates settings stored in the encrypted storage
+func ConfigCommand(args []string) int {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	maxServices := fs.Int("max-services", 0, "Maximum number of stored services (0 = unlimited)")
+	barRefresh := fs.Int("bar-refresh-ms", 0, "TUI countdown bar refresh in milliseconds, 100-999 (0 = once per second)")
+	noAtomic := fs.Bool("no-atomic", false, noAtomicUsage)
+	sortRecent := fs.Bool("search-sort-recent", false, "Order TUI search results by last-used, most recent first")
+	verifyClipboard := fs.Bool("verify-clipboard", false, "Read the clipboard back after copying in the TUI and warn if it did not update")
+	compress := fs.Bool("compress", false, "Gzip-compress the storage before encryption (use --compress=false to disable)")
+	rekeyAfter := fs.Int("rekey-after-saves", 0, fmt.Sprintf("Rotate the encryption salt after this many saves (0 = default %d)", storage.DefaultRekeyAfterSaves))
+	cipherName := fs.String("cipher", "", "Storage cipher: aes-256-gcm (default) or xchacha20-poly1305")
+	kdfName := fs.String("kdf", "", fmt.Sprintf("Key derivation: argon2id or scrypt (default %s in this build)", crypto.DefaultKDF))
+	hideIdentifier := fs.Bool("hide-identifier", false, "Leave identifiers out of the TUI list (the details pane still shows them)")
+	showExpiresAt := fs.Bool("show-expires-at", false, "Show the clock time codes expire instead of the TUI countdown (use --show-expires-at=false to undo)")
+	revealIdentifier := fs.Bool("reveal-identifier", false, "Show identifiers in the TUI list (the default)")
+	clipboardBackend := fs.String("clipboard", "", "Clipboard backend: auto (OSC 52 over SSH, else the system clipboard), system or osc52")
+	errorPlaceholder := fs.String("error-placeholder", "", fmt.Sprintf("Text shown for codes that fail to generate, up to %d characters (empty = %s)", storage.MaxErrorPlaceholderLength, storage.DefaultErrorPlaceholder))
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	// Only apply flags the user explicitly set
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	delete(set, "no-atomic") // a save option, not a setting
+
+	if set["max-services"] && *maxServices < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --max-services must be 0 (unlimited) or greater")
+		return 1
+	}
+
+	if set["bar-refresh-ms"] && *barRefresh != 0 && (*barRefresh < 100 || *barRefresh > 999) {
+		fmt.Fprintln(os.Stderr, "Error: --bar-refresh-ms must be 0 or between 100 and 999")
+		return 1
+	}
+
+	if set["rekey-after-saves"] && *rekeyAfter < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --rekey-after-saves must be 0 (default) or greater")
+		return 1
+	}
+
+	if set["hide-identifier"] && set["reveal-identifier"] {
+		fmt.Fprintln(os.Stderr, "Error: use only one of --hide-identifier and --reveal-identifier")
+		return 1
+	}
+
+	if set["error-placeholder"] {
+		if err := validatePlaceholder(*errorPlaceholder); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --error-placeholder %v\n", err)
+			return 1
+		}
+	}
+
+	var backend clipboard.Backend
+	if set["clipboard"] {
+		b, err := clipboard.ParseBackend(*clipboardBackend)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		backend = b
+	}
+
+	var cipher crypto.Cipher
+	if set["cipher"] {
+		c, err := crypto.ParseCipher(*cipherName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		cipher = c
+	}
+
+	var kdf crypto.KDF
+	if set["kdf"] {
+		k, err := crypto.ParseKDF(*kdfName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		kdf = k
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	app.noAtomic = *noAtomic
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	settings := &app.store.Settings
+
+	if len(set) == 0 {
+		printSettings(app)
+		return 0
+	}
+
+	if set["max-services"] {
+		settings.MaxServices = *maxServices
+	}
+	if set["bar-refresh-ms"] {
+		settings.BarRefreshMillis = *barRefresh
+	}
+	if set["compress"] {
+		settings.Compress = *compress
+	}
+	if set["rekey-after-saves"] {
+		settings.RekeyAfterSaves = *rekeyAfter
+	}
+	if set["cipher"] {
+		settings.Cipher = cipher.String()
+	}
+	if set["kdf"] {
+		settings.KDF = kdf.String()
+	}
+	if set["search-sort-recent"] {
+		settings.SearchSortRecent = *sortRecent
+	}
+	if set["verify-clipboard"] {
+		settings.VerifyClipboard = *verifyClipboard
+	}
+	if set["hide-identifier"] {
+		settings.HideIdentifiers = *hideIdentifier
+	}
+	if set["reveal-identifier"] {
+		settings.HideIdentifiers = !*revealIdentifier
+	}
+	if set["show-expires-at"] {
+		settings.ShowExpiresAt = *showExpiresAt
+	}
+	if set["error-placeholder"] {
+		settings.ErrorPlaceholder = *errorPlaceholder
+	}
+	if set["clipboard"] {
+		settings.Clipboard = string(backend)
+	}
+
+	if err := app.store.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving storage: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("✓ Settings updated")
+	printSettings(app)
+	return 0
+}
+
+// printSettings prints the current settings
+func printSettings(app *App) {
+	settings := app.store.Settings
+
+	maxServices := "unlimited"
+	if settings.MaxServices > 0 {
+		maxServices = fmt.Sprintf("%d", settings.MaxServices)
+	}
+	fmt.Printf("max-services: %s\n", maxServices)
+
+	barRefresh := "1000 (default)"
+	if settings.BarRefreshMillis > 0 {
+		barRefresh = fmt.Sprintf("%d", settings.BarRefreshMillis)
+	}
+	fmt.Printf("bar-refresh-ms: %s\n", barRefresh)
+	fmt.Printf("compress: %t\n", settings.Compress)
+	rekeyAfter := fmt.Sprintf("%d (default)", storage.DefaultRekeyAfterSaves)
+	if settings.RekeyAfterSaves > 0 {
+		rekeyAfter = fmt.Sprintf("%d", settings.RekeyAfterSaves)
+	}
+	fmt.Printf("rekey-after-saves: %s\n", rekeyAfter)
+	cipher, err := crypto.ParseCipher(settings.Cipher)
+	if err != nil {
+		fmt.Printf("cipher: %s (invalid)\n", settings.Cipher)
+	} else {
+		fmt.Printf("cipher: %s\n", cipher)
+	}
+	kdf, err := crypto.ParseKDF(settings.KDF)
+	if err != nil {
+		fmt.Printf("kdf: %s (invalid)\n", settings.KDF)
+	} else {
+		fmt.Printf("kdf: %s\n", kdf)
+	}
+	fmt.Printf("search-sort-recent: %t\n", settings.SearchSortRecent)
+	fmt.Printf("verify-clipboard: %t\n", settings.VerifyClipboard)
+	fmt.Printf("hide-identifier: %t\n", settings.HideIdentifiers)
+	fmt.Printf("show-expires-at: %t\n", settings.ShowExpiresAt)
+	fmt.Printf("error-placeholder: %s\n", settings.CodeErrorPlaceholder())
+	if backend, err := clipboard.ParseBackend(settings.Clipboard); err != nil {
+		fmt.Printf("clipboard: %s (invalid)\n", settings.Clipboard)
+	} else {
+		fmt.Printf("clipboard: %s\n", backend)
+	}
+}
+
+// validatePlaceholder checks an error placeholder fits the code column and
+// has no control characters
+func validatePlaceholder(placeholder string) error {
+	if utf8.RuneCountInString(placeholder) > storage.MaxErrorPlaceholderLength {
+		return fmt.Errorf("must be at most %d characters", storage.MaxErrorPlaceholderLength)
+	}
+	for _, r := range placeholder {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("must not contain control characters")
+		}
+	}
+	return nil
+}
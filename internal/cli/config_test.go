@@ -0,0 +1,385 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/crypto"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestConfigCommand_MaxServices tests setting and persisting the service limit
+func TestConfigCommand_MaxServices(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	captureStdout(t, func() {
+		code = ConfigCommand([]string{"--max-services", "5"})
+	})
+	if code != 0 {
+		t.Fatalf("ConfigCommand() = %d, want 0", code)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if store.Settings.MaxServices != 5 {
+		t.Errorf("MaxServices = %d, want 5", store.Settings.MaxServices)
+	}
+
+	out := captureStdout(t, func() {
+		code = ConfigCommand([]string{})
+	})
+	if code != 0 {
+		t.Fatalf("ConfigCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "max-services: 5") {
+		t.Errorf("Expected settings output to contain 'max-services: 5', got %q", out)
+	}
+}
+
+// TestConfigCommand_Compress tests toggling storage compression
+func TestConfigCommand_Compress(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ConfigCommand([]string{"--compress"})
+	})
+	if code != 0 {
+		t.Fatalf("ConfigCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "compress: true") {
+		t.Errorf("Expected output to contain 'compress: true', got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !store.Settings.Compress || store.Version != 2 {
+		t.Errorf("Compress = %v, Version = %d, want true and 2", store.Settings.Compress, store.Version)
+	}
+
+	captureStdout(t, func() {
+		code = ConfigCommand([]string{"--compress=false"})
+	})
+	if code != 0 {
+		t.Fatalf("ConfigCommand() = %d, want 0", code)
+	}
+
+	store, err = storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if store.Settings.Compress || store.Version != 1 {
+		t.Errorf("Compress = %v, Version = %d, want false and 1", store.Settings.Compress, store.Version)
+	}
+}
+
+// TestConfigCommand_Cipher tests switching the storage cipher
+func TestConfigCommand_Cipher(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ConfigCommand([]string{"--cipher", "xchacha20-poly1305"})
+	})
+	if code != 0 {
+		t.Fatalf("ConfigCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "cipher: xchacha20-poly1305") {
+		t.Errorf("Expected output to contain 'cipher: xchacha20-poly1305', got %q", out)
+	}
+
+	header, err := storage.ReadHeader(path)
+	if err != nil {
+		t.Fatalf("ReadHeader() error = %v", err)
+	}
+	if header.Cipher != crypto.CipherXChaCha20Poly1305 {
+		t.Errorf("Header cipher = %s, want xchacha20-poly1305", header.Cipher)
+	}
+	if _, err := storage.Load(path, "test-passphrase"); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+}
+
+// TestConfigCommand_KDF tests switching the key derivation function
+func TestConfigCommand_KDF(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ConfigCommand([]string{"--kdf", "scrypt"})
+	})
+	if code != 0 {
+		t.Fatalf("ConfigCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "kdf: scrypt") {
+		t.Errorf("Expected output to contain 'kdf: scrypt', got %q", out)
+	}
+
+	header, err := storage.ReadHeader(path)
+	if err != nil {
+		t.Fatalf("ReadHeader() error = %v", err)
+	}
+	if header.KDF != crypto.KDFScrypt {
+		t.Errorf("Header kdf = %s, want scrypt", header.KDF)
+	}
+	if _, err := storage.Load(path, "test-passphrase"); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+}
+
+// TestConfigCommand_RekeyAfterSaves tests setting the salt rotation interval
+func TestConfigCommand_RekeyAfterSaves(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	out := captureStdout(t, func() {
+		if code := ConfigCommand([]string{"--rekey-after-saves", "50"}); code != 0 {
+			t.Errorf("ConfigCommand() = %d, want 0", code)
+		}
+	})
+	if !strings.Contains(out, "rekey-after-saves: 50") {
+		t.Errorf("Expected output to contain 'rekey-after-saves: 50', got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if store.Settings.RekeyAfterSaves != 50 {
+		t.Errorf("RekeyAfterSaves = %d, want 50", store.Settings.RekeyAfterSaves)
+	}
+}
+
+// TestConfigCommand_NoAtomic tests --no-atomic saves without being treated as a setting
+func TestConfigCommand_NoAtomic(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ConfigCommand([]string{"--no-atomic", "--max-services", "3"})
+	})
+	if code != 0 {
+		t.Fatalf("ConfigCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "Settings updated") {
+		t.Errorf("Expected settings to be updated, got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if store.Settings.MaxServices != 3 {
+		t.Errorf("MaxServices = %d, want 3", store.Settings.MaxServices)
+	}
+
+	// --no-atomic alone only shows settings
+	out = captureStdout(t, func() {
+		code = ConfigCommand([]string{"--no-atomic"})
+	})
+	if code != 0 || strings.Contains(out, "Settings updated") {
+		t.Errorf("ConfigCommand(--no-atomic) = %d, output %q; want 0 without an update", code, out)
+	}
+}
+
+// TestConfigCommand_SearchSortRecent tests enabling recency-ordered search results
+func TestConfigCommand_SearchSortRecent(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ConfigCommand([]string{"--search-sort-recent"})
+	})
+	if code != 0 {
+		t.Fatalf("ConfigCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "search-sort-recent: true") {
+		t.Errorf("Expected output to contain 'search-sort-recent: true', got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !store.Settings.SearchSortRecent {
+		t.Error("SearchSortRecent should be persisted")
+	}
+}
+
+// TestConfigCommand_VerifyClipboard tests opting in to clipboard verification
+func TestConfigCommand_VerifyClipboard(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ConfigCommand([]string{"--verify-clipboard"})
+	})
+	if code != 0 {
+		t.Fatalf("ConfigCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "verify-clipboard: true") {
+		t.Errorf("Expected output to contain 'verify-clipboard: true', got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !store.Settings.VerifyClipboard {
+		t.Error("VerifyClipboard should be persisted")
+	}
+}
+
+// TestConfigCommand_ShowExpiresAt tests persisting the expiry clock display
+func TestConfigCommand_ShowExpiresAt(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ConfigCommand([]string{"--show-expires-at"})
+	})
+	if code != 0 {
+		t.Fatalf("ConfigCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "show-expires-at: true") {
+		t.Errorf("Expected output to contain 'show-expires-at: true', got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !store.Settings.ShowExpiresAt {
+		t.Error("ShowExpiresAt should be persisted")
+	}
+}
+
+// TestConfigCommand_Clipboard tests selecting the clipboard backend
+func TestConfigCommand_Clipboard(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ConfigCommand([]string{"--clipboard", "OSC52"})
+	})
+	if code != 0 {
+		t.Fatalf("ConfigCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "clipboard: osc52") {
+		t.Errorf("Expected output to contain 'clipboard: osc52', got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if store.Settings.Clipboard != "osc52" {
+		t.Errorf("Clipboard = %q, want osc52 persisted", store.Settings.Clipboard)
+	}
+
+	if code := ConfigCommand([]string{"--clipboard", "xclip"}); code != 1 {
+		t.Errorf("ConfigCommand(--clipboard xclip) = %d, want 1", code)
+	}
+}
+
+// TestConfigCommand_HideIdentifier tests hiding and revealing identifiers
+func TestConfigCommand_HideIdentifier(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	for _, tt := range []struct {
+		flag string
+		want bool
+	}{
+		{"--hide-identifier", true},
+		{"--reveal-identifier", false},
+	} {
+		var code int
+		out := captureStdout(t, func() {
+			code = ConfigCommand([]string{tt.flag})
+		})
+		if code != 0 {
+			t.Fatalf("ConfigCommand(%s) = %d, want 0", tt.flag, code)
+		}
+		if want := fmt.Sprintf("hide-identifier: %t", tt.want); !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got %q", want, out)
+		}
+
+		store, err := storage.Load(path, "test-passphrase")
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if store.Settings.HideIdentifiers != tt.want {
+			t.Errorf("After %s HideIdentifiers = %v, want %v", tt.flag, store.Settings.HideIdentifiers, tt.want)
+		}
+	}
+}
+
+// TestConfigCommand_ErrorPlaceholder tests setting and resetting the placeholder
+func TestConfigCommand_ErrorPlaceholder(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	for _, tt := range []struct {
+		value string
+		want  string
+	}{
+		{"n/a", "n/a"},
+		{"", storage.DefaultErrorPlaceholder},
+	} {
+		var code int
+		out := captureStdout(t, func() {
+			code = ConfigCommand([]string{"--error-placeholder", tt.value})
+		})
+		if code != 0 {
+			t.Fatalf("ConfigCommand(--error-placeholder %q) = %d, want 0", tt.value, code)
+		}
+		if want := "error-placeholder: " + tt.want; !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got %q", want, out)
+		}
+
+		store, err := storage.Load(path, "test-passphrase")
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if got := store.Settings.CodeErrorPlaceholder(); got != tt.want {
+			t.Errorf("CodeErrorPlaceholder() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// TestConfigCommand_InvalidValues tests out-of-range settings are rejected
+func TestConfigCommand_InvalidValues(t *testing.T) {
+	tests := [][]string{
+		{"--max-services", "-1"},
+		{"--bar-refresh-ms", "50"},
+		{"--bar-refresh-ms", "1500"},
+		{"--cipher", "des"},
+		{"--kdf", "pbkdf2"},
+		{"--rekey-after-saves", "-1"},
+		{"--hide-identifier", "--reveal-identifier"},
+		{"--error-placeholder", "much-too-long-placeholder"},
+		{"--error-placeholder", "bad\tvalue"},
+	}
+
+	for _, args := range tests {
+		if code := ConfigCommand(args); code != 1 {
+			t.Errorf("ConfigCommand(%v) = %d, want 1", args, code)
+		}
+	}
+}
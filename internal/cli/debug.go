@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// DebugCommand holds hidden diagnostics for bug reports. Subcommands never
+// unlock the store or print secrets.
+func DebugCommand(args []string) int {
+	if len(args) == 0 || args[0] != "params" {
+		fmt.Fprintln(os.Stderr, "Usage: totp debug params [--file PATH]")
+		return 1
+	}
+
+	fs := flag.NewFlagSet("debug params", flag.ExitOnError)
+	file := fs.String("file", "", "Storage file to inspect (default: the configured storage path)")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	path := *file
+	if path == "" {
+		defaultPath, err := storage.GetDefaultStoragePath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to get storage path: %v\n", err)
+			return 1
+		}
+		path = defaultPath
+	}
+
+	header, err := storage.ReadHeader(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	writeHeaderParams(os.Stdout, path, header)
+	return 0
+}
+
+// writeHeaderParams prints the non-secret header fields
+func writeHeaderParams(w io.Writer, path string, header storage.Header) {
+	fmt.Fprintf(w, "file: %s\n", path)
+	fmt.Fprintf(w, "format version: %d\n", header.Version)
+	fmt.Fprintf(w, "cipher: %s\n", header.Cipher)
+	fmt.Fprintf(w, "kdf: %s\n", header.KDF)
+	fmt.Fprintf(w, "compressed: %t\n", header.Compressed())
+	fmt.Fprintf(w, "salt length: %d\n", header.SaltLength)
+	fmt.Fprintf(w, "nonce length: %d\n", header.NonceLength)
+	fmt.Fprintf(w, "payload length: %d\n", header.PayloadLength)
+	fmt.Fprintln(w, "kdf params: not stored in header (built-in defaults)")
+}
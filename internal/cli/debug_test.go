@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestDebugCommand_Params tests header info is reported without unlocking
+func TestDebugCommand_Params(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	store, err := storage.Create(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// No passphrase is available: the command must not need one
+	t.Setenv(passphraseEnvVar, "")
+	os.Unsetenv(passphraseEnvVar)
+
+	var code int
+	out := captureStdout(t, func() {
+		code = DebugCommand([]string{"params", "--file", path})
+	})
+	if code != 0 {
+		t.Fatalf("DebugCommand() = %d, want 0", code)
+	}
+
+	for _, want := range []string{"format version: 1", "cipher: aes-256-gcm", "salt length: 16", "compressed: false"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output %q missing %q", out, want)
+		}
+	}
+}
+
+// TestDebugCommand_DefaultPath tests the configured storage path is used by default
+func TestDebugCommand_DefaultPath(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+
+	out := captureStdout(t, func() {
+		if code := DebugCommand([]string{"params"}); code != 0 {
+			t.Errorf("DebugCommand() = %d, want 0", code)
+		}
+	})
+	if !strings.Contains(out, "file: "+path) {
+		t.Errorf("Output %q should name %s", out, path)
+	}
+}
+
+// TestDebugCommand_Errors tests usage and unreadable files
+func TestDebugCommand_Errors(t *testing.T) {
+	garbage := filepath.Join(t.TempDir(), "garbage.enc")
+	if err := os.WriteFile(garbage, []byte("short"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"No subcommand", []string{}},
+		{"Unknown subcommand", []string{"keys"}},
+		{"Missing file", []string{"params", "--file", filepath.Join(t.TempDir(), "missing.enc")}},
+		{"Truncated file", []string{"params", "--file", garbage}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := DebugCommand(tt.args); code != 1 {
+				t.Errorf("DebugCommand(%v) = %d, want 1", tt.args, code)
+			}
+		})
+	}
+}
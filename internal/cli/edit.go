@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+	"github.com/pavanprakash21/totp-manager-go/internal/totp"
+)
+
+// RenameCommand renames a service in place, keeping its secret, history
+// and import provenance
+func RenameCommand(args []string) int {
+	fs := flag.NewFlagSet("rename", flag.ExitOnError)
+	noAtomic := fs.Bool("no-atomic", false, noAtomicUsage)
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if fs.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: totp rename OLD_NAME NEW_NAME")
+		return 1
+	}
+	oldName, newName := fs.Arg(0), strings.TrimSpace(fs.Arg(1))
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	app.noAtomic = *noAtomic
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	service, err := app.store.GetServiceCopy(oldName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	oldName = service.Name
+
+	service.Name = newName
+	if err := app.store.UpdateService(oldName, service); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.store.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving storage: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✓ Service '%s' renamed to '%s'\n", oldName, newName)
+	return 0
+}
+
+// EditCommand changes a service's identifier, URL, secret or code
+// parameters in place; only the flags given are changed
+func EditCommand(args []string) int {
+	fs := flag.NewFlagSet("edit", flag.ExitOnError)
+	name := fs.String("name", "", "Service name (required; may also be given as the first argument)")
+	identifier := fs.String("identifier", "", "New identifier (empty clears it)")
+	loginURL := fs.String("url", "", "New login page URL, http or https (empty clears it)")
+	secret := fs.String("secret", "", "New Base32 TOTP secret")
+	promptForSecret := fs.Bool("prompt-secret", false, "Enter the new secret interactively (hidden, asked twice) instead of --secret")
+	algorithm := fs.String("algorithm", "", "HMAC algorithm: SHA1, SHA256 or SHA512 (empty = SHA1)")
+	digits := fs.Int("digits", 0, "Code length, 6-8 (0 = 6)")
+	period := fs.Int("period", 0, "Code period in seconds (0 = 30)")
+	noAtomic := fs.Bool("no-atomic", false, noAtomicUsage)
+
+	if err := parseWithName(fs, args, name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *name == "" {
+		fmt.Fprintln(os.Stderr, "Error: --name is required")
+		fmt.Fprintln(os.Stderr, "Usage: totp edit [--name] SERVICE_NAME [--identifier ID] [--url URL] [--secret SECRET | --prompt-secret] [--algorithm ALG] [--digits N] [--period SECONDS]")
+		return 1
+	}
+
+	// Only change what the user explicitly set
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	delete(set, "name")
+	delete(set, "no-atomic")
+
+	if len(set) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: nothing to change (use --identifier, --url, --secret, --prompt-secret, --algorithm, --digits or --period)")
+		return 1
+	}
+
+	if set["secret"] && set["prompt-secret"] {
+		fmt.Fprintln(os.Stderr, "Error: use only one of --secret and --prompt-secret")
+		return 1
+	}
+
+	if set["url"] {
+		if err := storage.ValidateURL(*loginURL); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid --url: %v\n", err)
+			return 1
+		}
+	}
+
+	if *promptForSecret {
+		entered, err := promptSecret()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		*secret = entered
+		set["secret"] = true
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	app.noAtomic = *noAtomic
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	service, err := app.store.GetServiceCopy(*name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	steam := service.Type == storage.TypeSteam
+	if steam && (set["algorithm"] || set["digits"] || set["period"]) {
+		fmt.Fprintln(os.Stderr, "Error: --algorithm, --digits and --period cannot be used with Steam services")
+		return 1
+	}
+
+	if set["identifier"] {
+		service.Identifier = *identifier
+	}
+	if set["url"] {
+		service.URL = *loginURL
+	}
+	if set["secret"] {
+		newSecret := inputSecret(*secret, steam)
+		if err := totp.ValidateSecret(newSecret); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid TOTP secret: %v\n", err)
+			fmt.Fprintln(os.Stderr, "Secret must be valid Base32 (A-Z, 2-7) and at least 16 characters")
+			return 1
+		}
+		service.Secret = newSecret
+		service.AllowWeakSecret = false
+	}
+	if set["algorithm"] {
+		service.Algorithm = strings.ToUpper(*algorithm)
+	}
+	if set["digits"] {
+		service.Digits = *digits
+	}
+	if set["period"] {
+		service.Period = *period
+	}
+
+	if err := app.store.UpdateService(service.Name, service); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.store.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving storage: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✓ Service '%s' updated (%s)\n", service.Name, describeParams(&service))
+	return 0
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// editTestCreated is the CreatedAt of the services in edit tests
+var editTestCreated = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// setupEditStorage creates storage holding GitHub and AWS
+func setupEditStorage(t *testing.T) string {
+	t.Helper()
+	path := setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Identifier: "octocat", Issuer: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: editTestCreated},
+		storage.Service{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: editTestCreated},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	return path
+}
+
+// loadEditedService loads the named service from the store at path
+func loadEditedService(t *testing.T, path, name string) storage.Service {
+	t.Helper()
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	service, err := store.GetServiceCopy(name)
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	return service
+}
+
+// TestRenameCommand tests renaming keeps the secret, provenance and CreatedAt
+func TestRenameCommand(t *testing.T) {
+	path := setupEditStorage(t)
+
+	var code int
+	out := captureStdout(t, func() {
+		code = RenameCommand([]string{"github", "GitHub Work"})
+	})
+	if code != 0 {
+		t.Fatalf("RenameCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "✓ Service 'GitHub' renamed to 'GitHub Work'") {
+		t.Errorf("Unexpected output: %q", out)
+	}
+
+	service := loadEditedService(t, path, "GitHub Work")
+	if service.Secret != "JBSWY3DPEHPK3PXP" || service.Identifier != "octocat" || service.Issuer != "GitHub" {
+		t.Errorf("Renamed service = %+v, want its other fields kept", service)
+	}
+	if !service.CreatedAt.Equal(editTestCreated) {
+		t.Errorf("CreatedAt = %v, want %v", service.CreatedAt, editTestCreated)
+	}
+}
+
+// TestRenameCommand_Invalid tests bad arguments and name collisions
+func TestRenameCommand_Invalid(t *testing.T) {
+	path := setupEditStorage(t)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Missing new name", []string{"GitHub"}},
+		{"Unknown service", []string{"Slack", "Chat"}},
+		{"Name taken", []string{"GitHub", "aws"}},
+		{"Empty name", []string{"GitHub", " "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := RenameCommand(tt.args); code != 1 {
+				t.Errorf("RenameCommand(%v) = %d, want 1", tt.args, code)
+			}
+		})
+	}
+
+	if names := storedNames(t, path); len(names) != 2 || names[0] != "GitHub" || names[1] != "AWS" {
+		t.Errorf("Services = %v, want GitHub and AWS unchanged", names)
+	}
+}
+
+// TestEditCommand tests only the given fields change
+func TestEditCommand(t *testing.T) {
+	path := setupEditStorage(t)
+
+	var code int
+	out := captureStdout(t, func() {
+		code = EditCommand([]string{"GitHub", "--secret", "gezd gnbv gy3t qojq", "--period", "60"})
+	})
+	if code != 0 {
+		t.Fatalf("EditCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "(SHA1, 6 digits, 60s)") {
+		t.Errorf("Output = %q, want the new parameters", out)
+	}
+
+	service := loadEditedService(t, path, "GitHub")
+	if service.Secret != "GEZDGNBVGY3TQOJQ" || service.Period != 60 {
+		t.Errorf("Secret %q period %d, want the normalized new secret and 60", service.Secret, service.Period)
+	}
+	if service.Identifier != "octocat" || !service.CreatedAt.Equal(editTestCreated) {
+		t.Errorf("Edited service = %+v, want identifier and CreatedAt kept", service)
+	}
+
+	// An explicitly empty identifier clears it
+	captureStdout(t, func() {
+		code = EditCommand([]string{"--name", "GitHub", "--identifier", ""})
+	})
+	if code != 0 {
+		t.Fatalf("EditCommand() = %d, want 0", code)
+	}
+	if service := loadEditedService(t, path, "GitHub"); service.Identifier != "" {
+		t.Errorf("Identifier = %q, want it cleared", service.Identifier)
+	}
+}
+
+// TestEditCommand_Invalid tests invalid edits fail and change nothing
+func TestEditCommand_Invalid(t *testing.T) {
+	path := setupEditStorage(t)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Missing name", []string{"--period", "60"}},
+		{"Nothing to change", []string{"GitHub"}},
+		{"Unknown service", []string{"Slack", "--period", "60"}},
+		{"Invalid secret", []string{"GitHub", "--secret", "not base32!"}},
+		{"Invalid period", []string{"GitHub", "--period", "-5"}},
+		{"Invalid URL", []string{"GitHub", "--url", "ftp://example.com"}},
+		{"Both secret sources", []string{"GitHub", "--secret", "GEZDGNBVGY3TQOJQ", "--prompt-secret"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := EditCommand(tt.args); code != 1 {
+				t.Errorf("EditCommand(%v) = %d, want 1", tt.args, code)
+			}
+		})
+	}
+
+	service := loadEditedService(t, path, "GitHub")
+	if service.Secret != "JBSWY3DPEHPK3PXP" || service.Period != 0 || service.URL != "" {
+		t.Errorf("GitHub = %+v, want it unchanged", service)
+	}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+	"github.com/pavanprakash21/totp-manager-go/internal/totp"
+)
+
+// EnsureCommand idempotently provisions a service: it is added when missing,
+// a no-op when an identical one exists, and an error when the existing one
+// has a different secret or parameters
+func EnsureCommand(args []string) int {
+	fs := flag.NewFlagSet("ensure", flag.ExitOnError)
+	name := fs.String("name", "", "Service name (required)")
+	identifier := fs.String("identifier", "", "Optional identifier (e.g., email, username)")
+	secret := fs.String("secret", "", "Base32 TOTP secret (required)")
+	algorithm := fs.String("algorithm", "", "HMAC algorithm: SHA1, SHA256 or SHA512 (default SHA1)")
+	digits := fs.Int("digits", 0, "Code length, 6-8 (default 6)")
+	period := fs.Int("period", 0, "Code period in seconds (default 30)")
+	noAtomic := fs.Bool("no-atomic", false, noAtomicUsage)
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if *name == "" || *secret == "" {
+		fmt.Fprintln(os.Stderr, "Error: --name and --secret are required")
+		fmt.Fprintln(os.Stderr, "Usage: totp ensure --name SERVICE_NAME --secret BASE32_SECRET")
+		return 1
+	}
+
+	wanted := storage.Service{
+		Name:       *name,
+		Identifier: *identifier,
+		Secret:     totp.NormalizeSecret(*secret),
+		CreatedAt:  time.Now(),
+		Algorithm:  strings.ToUpper(*algorithm),
+		Digits:     *digits,
+		Period:     *period,
+	}
+
+	if err := totp.ValidateSecret(wanted.Secret); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Invalid TOTP secret: %v\n", err)
+		return 1
+	}
+	if err := totp.ValidateParams(wanted.EffectiveAlgorithm(), wanted.EffectiveDigits(), wanted.EffectivePeriod()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	app.noAtomic = *noAtomic
+
+	if err := app.Initialize(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if existing, err := app.store.GetServiceCopy(*name); err == nil {
+		if err := sameService(&existing, &wanted); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Service '%s' already exists with a different %v\n", existing.Name, err)
+			return 1
+		}
+		fmt.Printf("✓ Service '%s' already present; nothing to do\n", existing.Name)
+		return 0
+	}
+
+	if err := app.store.AddService(wanted); err != nil {
+		fmt.Fprintf(os.Stderr, "Error adding service: %v\n", err)
+		return 1
+	}
+
+	if err := app.store.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving storage: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✓ Service '%s' added (%s)\n", wanted.Name, describeParams(&wanted))
+	return 0
+}
+
+// sameService reports which code-relevant field differs, or nil when the
+// existing service generates the same codes as the wanted one
+func sameService(existing, wanted *storage.Service) error {
+	switch {
+	case totp.NormalizeSecret(existing.Secret) != wanted.Secret:
+		return fmt.Errorf("secret")
+	case existing.EffectiveAlgorithm() != wanted.EffectiveAlgorithm():
+		return fmt.Errorf("algorithm (%s)", existing.EffectiveAlgorithm())
+	case existing.EffectiveDigits() != wanted.EffectiveDigits():
+		return fmt.Errorf("digit count (%d)", existing.EffectiveDigits())
+	case existing.EffectivePeriod() != wanted.EffectivePeriod():
+		return fmt.Errorf("period (%ds)", existing.EffectivePeriod())
+	}
+	return nil
+}
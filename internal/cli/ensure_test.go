@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestEnsureCommand_Added tests a missing service is added
+func TestEnsureCommand_Added(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = EnsureCommand([]string{"--name", "GitHub", "--secret", "JBSWY3DPEHPK3PXP"})
+	})
+	if code != 0 {
+		t.Fatalf("EnsureCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "✓ Service 'GitHub' added") {
+		t.Errorf("Unexpected output: %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(store.Services) != 1 {
+		t.Errorf("Stored %d services, want 1", len(store.Services))
+	}
+}
+
+// TestEnsureCommand_NoOp tests an identical existing service is left alone
+func TestEnsureCommand_NoOp(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		// Case and grouping differences in the secret don't matter
+		code = EnsureCommand([]string{"--name", "github", "--secret", "jbsw y3dp ehpk 3pxp"})
+	})
+	if code != 0 {
+		t.Fatalf("EnsureCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "already present") {
+		t.Errorf("Unexpected output: %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(store.Services) != 1 {
+		t.Errorf("Stored %d services, want 1", len(store.Services))
+	}
+}
+
+// TestEnsureCommand_Conflict tests an existing service with different codes is an error
+func TestEnsureCommand_Conflict(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Different secret", []string{"--secret", "GEZDGNBVGY3TQOJQ"}},
+		{"Different digits", []string{"--secret", "JBSWY3DPEHPK3PXP", "--digits", "8"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := setupTestStorage(t, "test-passphrase",
+				storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			)
+			t.Setenv(passphraseEnvVar, "test-passphrase")
+
+			if code := EnsureCommand(append([]string{"--name", "GitHub"}, tt.args...)); code != 1 {
+				t.Errorf("EnsureCommand() = %d, want 1", code)
+			}
+
+			store, err := storage.Load(path, "test-passphrase")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if store.Services[0].Secret != "JBSWY3DPEHPK3PXP" || store.Services[0].Digits != 0 {
+				t.Error("A conflicting ensure must not modify the existing service")
+			}
+		})
+	}
+}
+
+// TestEnsureCommand_InvalidArgs tests required flags and validation
+func TestEnsureCommand_InvalidArgs(t *testing.T) {
+	tests := [][]string{
+		{"--secret", "JBSWY3DPEHPK3PXP"},
+		{"--name", "GitHub"},
+		{"--name", "GitHub", "--secret", "bad!"},
+		{"--name", "GitHub", "--secret", "JBSWY3DPEHPK3PXP", "--algorithm", "MD5"},
+	}
+
+	for _, args := range tests {
+		if code := EnsureCommand(args); code != 1 {
+			t.Errorf("EnsureCommand(%v) = %d, want 1", args, code)
+		}
+	}
+}
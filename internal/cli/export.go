@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// ExportCommand writes an encrypted backup of the vault, with its own salt
+// and nonce, for moving it to another machine. The backup is a regular
+// storage file: restore it by placing it as secrets.enc in a storage directory.
+func ExportCommand(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	out := fs.String("out", "", "Backup file to write (created with 0600 permissions) (required)")
+	samePassphrase := fs.Bool("same-passphrase", false, "Encrypt the backup with the vault passphrase instead of asking for a new one")
+	force := fs.Bool("force", false, "Overwrite an existing backup file")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if *out == "" {
+		fmt.Fprintln(os.Stderr, "Error: --out is required")
+		fmt.Fprintln(os.Stderr, "Usage: totp export --out BACKUP_FILE [--same-passphrase] [--force]")
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Fail before asking for a backup passphrase; writeFileExclusive still
+	// makes the final check when writing
+	if _, err := os.Lstat(*out); err == nil && !*force {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists (use --force to overwrite it)\n", *out)
+		return 1
+	}
+
+	// An empty passphrase makes EncryptedBackup reuse the vault's
+	var passphrase string
+	if !*samePassphrase {
+		fmt.Println("Choose a passphrase for the backup.")
+		passphrase, err = app.promptNewPassphrase()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	// Encrypt before touching the file, and replace an existing backup only
+	// once the new one is written
+	data, err := app.store.EncryptedBackup(passphrase)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := writeFileExclusive(*out, data, *force); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✓ Exported %d services to %s\n", len(app.store.Services), *out)
+	return 0
+}
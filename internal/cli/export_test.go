@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestExportCommand tests the backup is encrypted with the passphrase read
+// from stdin and opens with it
+func TestExportCommand(t *testing.T) {
+	setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	withStdin(t, "backup-passphrase\nbackup-passphrase\n")
+
+	out := filepath.Join(t.TempDir(), "backup.enc")
+	var code int
+	output := captureStdout(t, func() { code = ExportCommand([]string{"--out", out}) })
+	if code != 0 {
+		t.Fatalf("ExportCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(output, "✓ Exported 1 services") {
+		t.Errorf("Output = %q, want the success line", output)
+	}
+
+	backup, err := storage.Load(out, "backup-passphrase")
+	if err != nil {
+		t.Fatalf("Load(backup) error = %v", err)
+	}
+	if _, err := backup.GetServiceCopy("GitHub"); err != nil {
+		t.Errorf("Backup is missing GitHub: %v", err)
+	}
+}
+
+// TestExportCommand_SamePassphraseAndForce tests --same-passphrase and that
+// existing files are only replaced with --force, and only once the new
+// backup is written
+func TestExportCommand_SamePassphraseAndForce(t *testing.T) {
+	setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	out := filepath.Join(t.TempDir(), "backup.enc")
+	if err := os.WriteFile(out, []byte("keep me"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if code := ExportCommand([]string{"--out", out, "--same-passphrase"}); code != 1 {
+		t.Errorf("ExportCommand(existing) = %d, want 1", code)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "keep me" {
+		t.Error("Existing file must not be touched without --force")
+	}
+
+	// A forced export that fails to write keeps the old backup
+	oldRename := renameFile
+	renameFile = func(string, string) error { return errors.New("disk full") }
+	if code := ExportCommand([]string{"--out", out, "--same-passphrase", "--force"}); code != 1 {
+		t.Errorf("ExportCommand(--force, failed write) = %d, want 1", code)
+	}
+	renameFile = oldRename
+	if data, _ := os.ReadFile(out); string(data) != "keep me" {
+		t.Error("A failed --force export must keep the existing file")
+	}
+
+	var code int
+	captureStdout(t, func() { code = ExportCommand([]string{"--out", out, "--same-passphrase", "--force"}) })
+	if code != 0 {
+		t.Fatalf("ExportCommand(--force) = %d, want 0", code)
+	}
+	if _, err := storage.Load(out, "test-passphrase"); err != nil {
+		t.Errorf("Load(backup) with the vault passphrase error = %v", err)
+	}
+}
+
+// TestExportCommand_MissingOut tests --out is required
+func TestExportCommand_MissingOut(t *testing.T) {
+	if code := ExportCommand(nil); code != 1 {
+		t.Errorf("ExportCommand() = %d, want 1", code)
+	}
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/qr"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// ExportURIsCommand prints services as otpauth:// URIs, one per line, for
+// enrolling them in another authenticator. The output contains secrets.
+func ExportURIsCommand(args []string) int {
+	fs := flag.NewFlagSet("export-uris", flag.ExitOnError)
+	name := fs.String("name", "", "Export only this service (default: all)")
+	out := fs.String("out", "", "Write the URIs to a file (created with 0600 permissions) instead of stdout")
+	stripIssuerPrefix := fs.Bool("strip-issuer-prefix", false, "Label URIs with just the account (the issuer= parameter is kept)")
+	qrDir := fs.String("qr-dir", "", "Write one QR code PNG per service (named issuer-name.png) to this directory instead")
+	force := fs.Bool("force", false, "Overwrite existing output files")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if *qrDir != "" && *out != "" {
+		fmt.Fprintln(os.Stderr, "Error: use only one of --out and --qr-dir")
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	services := app.store.Services
+	if *name != "" {
+		service, err := app.store.GetServiceCopy(*name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		services = []storage.Service{service}
+	}
+
+	// A single Steam service fails like `totp qr`; in a full export Steam
+	// services are skipped with a warning
+	if *name != "" && services[0].Type == storage.TypeSteam {
+		fmt.Fprintf(os.Stderr, "Error: service '%s' is a Steam Guard service; otpauth URIs cannot represent Steam codes\n", services[0].Name)
+		return 1
+	}
+
+	if *qrDir != "" {
+		count, err := writeQRFiles(*qrDir, services, *stripIssuerPrefix, *force)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "✓ Exported %d QR codes to %s\n", count, *qrDir)
+		return 0
+	}
+
+	var buf bytes.Buffer
+	count := writeURIs(&buf, services, *stripIssuerPrefix)
+
+	if *out == "" {
+		fmt.Print(buf.String())
+		return 0
+	}
+
+	if err := writeFileExclusive(*out, buf.Bytes(), *force); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "✓ Exported %d services to %s\n", count, *out)
+	return 0
+}
+
+// skipSteam reports whether service is a Steam Guard service, warning on
+// stderr that it is left out: authenticator apps would read its secret as
+// a standard 6-digit TOTP key and show codes Steam rejects
+func skipSteam(service *storage.Service) bool {
+	if service.Type != storage.TypeSteam {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "⚠ Skipped '%s': otpauth URIs cannot represent Steam Guard codes\n", service.Name)
+	return true
+}
+
+// writeURIs writes one otpauth URI per service, optionally without the
+// "Issuer:" label prefix, and returns how many it wrote. Steam services are
+// skipped.
+func writeURIs(w io.Writer, services []storage.Service, stripIssuerPrefix bool) int {
+	count := 0
+	for i := range services {
+		if skipSteam(&services[i]) {
+			continue
+		}
+		key := keyFromService(&services[i])
+		if stripIssuerPrefix {
+			key = key.WithoutIssuerPrefix()
+		}
+		fmt.Fprintln(w, key.URI())
+		count++
+	}
+	return count
+}
+
+// writeQRFiles writes a QR code PNG for each service into dir (created with
+// 0700 permissions), each file 0600; existing files are only replaced with
+// force. Steam services are skipped. It returns how many files it wrote.
+func writeQRFiles(dir string, services []storage.Service, stripIssuerPrefix, force bool) (int, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	count := 0
+	for i, filename := range qrFilenames(services) {
+		if skipSteam(&services[i]) {
+			continue
+		}
+		key := keyFromService(&services[i])
+		if stripIssuerPrefix {
+			key = key.WithoutIssuerPrefix()
+		}
+
+		data, err := qr.EncodePNG(key.URI(), qr.DefaultSize)
+		if err != nil {
+			return count, fmt.Errorf("service '%s': %w", services[i].Name, err)
+		}
+		if err := writeFileExclusive(filepath.Join(dir, filename), data, force); err != nil {
+			return count, fmt.Errorf("service '%s': %w", services[i].Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// qrFilenames names each service's QR file "issuer-name.png" (just
+// "name.png" without an issuer), numbering any that still collide after
+// sanitizing ("name-2.png")
+func qrFilenames(services []storage.Service) []string {
+	names := make([]string, len(services))
+	used := make(map[string]bool, len(services))
+
+	for i := range services {
+		base := services[i].Name
+		if issuer := keyFromService(&services[i]).Issuer; issuer != "" && !storage.SameName(issuer, base) {
+			base = issuer + "-" + base
+		}
+		base = sanitizeFilename(base)
+
+		name := base + ".png"
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s-%d.png", base, n)
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+// sanitizeFilename makes s safe as a single file name: path separators
+// become "_", control characters are dropped, and leading dots and
+// surrounding spaces are trimmed so the result is never hidden or empty
+func sanitizeFilename(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '/' || r == '\\':
+			b.WriteRune('_')
+		case unicode.IsControl(r):
+			continue
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	name := strings.TrimLeft(strings.TrimSpace(b.String()), ".")
+	if name == "" {
+		return "service"
+	}
+	return name
+}
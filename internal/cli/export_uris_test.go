@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/qr"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestWriteURIs_StripIssuerPrefix tests the label prefix is dropped only with the flag
+func TestWriteURIs_StripIssuerPrefix(t *testing.T) {
+	services := []storage.Service{
+		{Name: "GitHub", Identifier: "alice@example.com", Secret: "JBSWY3DPEHPK3PXP"},
+		{Name: "Imported", Issuer: "ACME", Label: "ACME:bob", Secret: "GEZDGNBVGY3TQOJQ"},
+	}
+
+	var buf bytes.Buffer
+	writeURIs(&buf, services, false)
+	want := "otpauth://totp/GitHub:alice@example.com?issuer=GitHub&secret=JBSWY3DPEHPK3PXP\n" +
+		"otpauth://totp/ACME:bob?issuer=ACME&secret=GEZDGNBVGY3TQOJQ\n"
+	if buf.String() != want {
+		t.Errorf("writeURIs() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	writeURIs(&buf, services, true)
+	want = "otpauth://totp/alice@example.com?issuer=GitHub&secret=JBSWY3DPEHPK3PXP\n" +
+		"otpauth://totp/bob?issuer=ACME&secret=GEZDGNBVGY3TQOJQ\n"
+	if buf.String() != want {
+		t.Errorf("writeURIs(strip) = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestWriteURIs_SkipsSteam tests Steam services are left out of the URIs
+func TestWriteURIs_SkipsSteam(t *testing.T) {
+	services := []storage.Service{
+		{Name: "Steam", Type: storage.TypeSteam, Secret: "JBSWY3DPEHPK3PXP"},
+		{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP"},
+	}
+
+	var buf bytes.Buffer
+	if count := writeURIs(&buf, services, false); count != 1 {
+		t.Errorf("writeURIs() = %d, want 1", count)
+	}
+	if buf.String() != "otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP\n" {
+		t.Errorf("writeURIs() wrote %q, want only GitHub", buf.String())
+	}
+}
+
+// TestWriteQRFiles_SkipsSteam tests no QR code is written for a Steam service
+func TestWriteQRFiles_SkipsSteam(t *testing.T) {
+	services := []storage.Service{
+		{Name: "Steam", Type: storage.TypeSteam, Secret: "JBSWY3DPEHPK3PXP"},
+		{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP"},
+	}
+	dir := t.TempDir()
+
+	count, err := writeQRFiles(dir, services, false, false)
+	if err != nil || count != 1 {
+		t.Fatalf("writeQRFiles() = %d, %v; want 1, nil", count, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Steam.png")); !os.IsNotExist(err) {
+		t.Error("A Steam service should not get a QR code")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "GitHub.png")); err != nil {
+		t.Errorf("GitHub.png error = %v", err)
+	}
+}
+
+// TestExportURIsCommand_NamedSteam tests asking for a Steam service fails
+func TestExportURIsCommand_NamedSteam(t *testing.T) {
+	setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "Steam", Type: storage.TypeSteam, Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() { code = ExportURIsCommand([]string{"--name", "Steam"}) })
+	if code != 1 || out != "" {
+		t.Errorf("ExportURIsCommand(steam) = %d with output %q, want 1 and nothing", code, out)
+	}
+}
+
+// TestExportURIsCommand_Name tests exporting a single service
+func TestExportURIsCommand_Name(t *testing.T) {
+	setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Identifier: "alice", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		storage.Service{Name: "AWS", Secret: "GEZDGNBVGY3TQOJQ", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() { code = ExportURIsCommand([]string{"--name", "github", "--strip-issuer-prefix"}) })
+	if code != 0 {
+		t.Fatalf("ExportURIsCommand() = %d, want 0", code)
+	}
+	if strings.TrimSpace(out) != "otpauth://totp/alice?issuer=GitHub&secret=JBSWY3DPEHPK3PXP" {
+		t.Errorf("Output = %q, want only GitHub without the issuer prefix", out)
+	}
+
+	if code := ExportURIsCommand([]string{"--name", "Missing"}); code != 1 {
+		t.Errorf("ExportURIsCommand(missing) = %d, want 1", code)
+	}
+}
+
+// TestSanitizeFilename tests path separators and control characters are removed
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"GitHub", "GitHub"},
+		{"../../etc/passwd", "_.._etc_passwd"},
+		{`ACME\admin`, "ACME_admin"},
+		{"tab\there\x00", "tabhere"},
+		{"  .hidden ", "hidden"},
+		{"...", "service"},
+		{"Straße", "Straße"},
+	}
+
+	for _, tt := range tests {
+		if got := sanitizeFilename(tt.in); got != tt.want {
+			t.Errorf("sanitizeFilename(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestQRFilenames tests the issuer keeps same-named services apart and
+// remaining collisions are numbered
+func TestQRFilenames(t *testing.T) {
+	services := []storage.Service{
+		{Name: "Admin", Issuer: "ACME", Label: "ACME:admin"},
+		{Name: "Admin", Issuer: "Initech", Label: "Initech:admin"},
+		{Name: "GitHub", Identifier: "alice"},
+		{Name: "a/b"},
+		{Name: "a_b"},
+	}
+
+	got := qrFilenames(services)
+	want := []string{"ACME-Admin.png", "Initech-Admin.png", "GitHub.png", "a_b.png", "a_b-2.png"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("qrFilenames()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// TestExportURIsCommand_QRDir tests one decodable PNG is written per service
+func TestExportURIsCommand_QRDir(t *testing.T) {
+	setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Identifier: "alice", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		storage.Service{Name: "AWS", Secret: "GEZDGNBVGY3TQOJQ", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	dir := filepath.Join(t.TempDir(), "qr")
+
+	if code := ExportURIsCommand([]string{"--qr-dir", dir}); code != 0 {
+		t.Fatalf("ExportURIsCommand() = %d, want 0", code)
+	}
+
+	text, err := qr.Decode(filepath.Join(dir, "GitHub.png"))
+	if err != nil {
+		t.Fatalf("qr.Decode() error = %v", err)
+	}
+	if text != "otpauth://totp/GitHub:alice?issuer=GitHub&secret=JBSWY3DPEHPK3PXP" {
+		t.Errorf("QR text = %q, want the GitHub URI", text)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "AWS.png")); err != nil {
+		t.Errorf("AWS.png missing: %v", err)
+	}
+
+	if code := ExportURIsCommand([]string{"--qr-dir", dir, "--out", "uris.txt"}); code != 1 {
+		t.Errorf("ExportURIsCommand(--qr-dir --out) = %d, want 1", code)
+	}
+}
+
+// TestExportURIsCommand_RefusesOverwrite tests --out keeps an existing file
+// unless --force is given
+func TestExportURIsCommand_RefusesOverwrite(t *testing.T) {
+	setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	out := filepath.Join(t.TempDir(), "uris.txt")
+	if err := os.WriteFile(out, []byte("keep me"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if code := ExportURIsCommand([]string{"--out", out}); code != 1 {
+		t.Errorf("ExportURIsCommand() = %d, want 1 for an existing file", code)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "keep me" {
+		t.Errorf("File = %q, want it untouched", data)
+	}
+
+	if code := ExportURIsCommand([]string{"--out", out, "--force"}); code != 0 {
+		t.Fatalf("ExportURIsCommand(--force) = %d, want 0", code)
+	}
+	if data, _ := os.ReadFile(out); !strings.HasPrefix(string(data), "otpauth://") {
+		t.Errorf("File = %q, want the exported URIs", data)
+	}
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// FindCommand lists the services whose login URL or issuer matches a
+// domain (never secrets), for integrations that look accounts up by site
+func FindCommand(args []string) int {
+	fs := flag.NewFlagSet("find", flag.ExitOnError)
+	domain := fs.String("domain", "", "Domain or URL to match (e.g. github.com) (required)")
+	format := fs.String("format", "table", "Output format: table, json or csv")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if storage.NormalizeDomain(*domain) == "" {
+		fmt.Fprintln(os.Stderr, "Error: --domain is required")
+		fmt.Fprintln(os.Stderr, "Usage: totp find --domain DOMAIN")
+		return 1
+	}
+
+	if *format != "table" && *format != "json" && *format != "csv" {
+		fmt.Fprintf(os.Stderr, "Error: Unknown format %q (use table, json or csv)\n", *format)
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	matches := app.store.FindByDomain(*domain)
+	if len(matches) == 0 {
+		fmt.Fprintf(os.Stderr, "No services match %s\n", storage.NormalizeDomain(*domain))
+		return 1
+	}
+
+	if err := writeList(os.Stdout, matches, *format, false); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestFindCommand tests services are found by URL domain and misses exit 1
+func TestFindCommand(t *testing.T) {
+	setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(), URL: "https://github.com/login"},
+		storage.Service{Name: "GitLab", Secret: "GEZDGNBVGY3TQOJQ", CreatedAt: time.Now(), URL: "https://gitlab.com/users/sign_in"},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() { code = FindCommand([]string{"--domain", "github.com", "--format", "json"}) })
+	if code != 0 {
+		t.Fatalf("FindCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, `"name": "GitHub"`) || strings.Contains(out, "GitLab") {
+		t.Errorf("Output = %q, want only GitHub", out)
+	}
+	if strings.Contains(out, "JBSWY3DPEHPK3PXP") {
+		t.Error("find must never print secrets")
+	}
+
+	out = captureStdout(t, func() { code = FindCommand([]string{"--domain", "example.com"}) })
+	if code != 1 || out != "" {
+		t.Errorf("FindCommand(no match) = %d, output %q; want 1 and no output", code, out)
+	}
+}
+
+// TestFindCommand_MissingDomain tests --domain is required
+func TestFindCommand_MissingDomain(t *testing.T) {
+	if code := FindCommand(nil); code != 1 {
+		t.Errorf("FindCommand() = %d, want 1", code)
+	}
+}
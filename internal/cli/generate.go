@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/clipboard"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// now is the clock used for code generation (overridable in tests)
+var now = time.Now
+
+// boundaryWindow is how close to the end of its period a code counts as
+// about to expire; boundary-strict servers may reject it by the time it arrives
+const boundaryWindow = time.Second
+
+// Clipboard backends (overridable in tests)
+var (
+	copyToClipboard = clipboard.Copy
+	clearClipboard  = clipboard.ClearAfter
+)
+
+// GenerateCommand prints the current TOTP code for a single service, named
+// by --name or as the first argument ("totp generate GitHub --copy")
+func GenerateCommand(args []string) int {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	name := fs.String("name", "", "Service name (required; may also be given as the first argument)")
+	at := fs.String("at", "", "Generate the code for a specific time (RFC3339, e.g. 2024-01-01T00:00:30Z)")
+	copyCode := fs.Bool("copy", false, "Copy the code to the clipboard instead of printing it")
+	clipClear := fs.Int("clip-clear-seconds", 0, clipClearUsage)
+	waitBoundary := fs.Bool("wait-boundary", false, "In the last second of a period, wait for the next period and emit its code")
+	remaining := fs.Bool("remaining", false, "Also print the seconds until the code expires (\"CODE SECONDS\" on one line)")
+	noLastUsedSave := fs.Bool("no-last-used-save", false, "Don't record the copy's last-used time and usage count, so copying never rewrites the store")
+
+	if err := parseWithName(fs, args, name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *name == "" {
+		fmt.Fprintln(os.Stderr, "Error: --name is required")
+		fmt.Fprintln(os.Stderr, "Usage: totp generate [--name] SERVICE_NAME [--at RFC3339_TIME] [--remaining] [--copy]")
+		return 1
+	}
+
+	if *clipClear < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --clip-clear-seconds must be 0 or more")
+		return 1
+	}
+
+	if *waitBoundary && *at != "" {
+		fmt.Fprintln(os.Stderr, "Error: --wait-boundary cannot be combined with --at")
+		return 1
+	}
+
+	// Resolve the time before unlocking so bad input fails fast
+	var atTime time.Time
+	if *at != "" {
+		parsed, err := time.Parse(time.RFC3339, *at)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid --at time %q: expected RFC3339 (e.g. 2024-01-01T00:00:30Z)\n", *at)
+			return 1
+		}
+		atTime = parsed
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	service, err := app.store.GetServiceCopy(*name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Read the clock after unlocking, which can take a noticeable moment
+	t := atTime
+	if *at == "" {
+		t = alignToBoundary(now(), service.EffectivePeriod(), *waitBoundary)
+	}
+
+	code, err := service.Code(t)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating code: %v\n", err)
+		return 1
+	}
+
+	left := secondsRemaining(t, service.EffectivePeriod())
+	if *copyCode {
+		useClipboardSetting(app.store.Settings)
+		if *remaining {
+			fmt.Printf("Expires in %ds\n", left)
+		}
+		return copyAndClear(code, *clipClear, func() {
+			if !*noLastUsedSave {
+				recordCopy(app.store, service.Name)
+			}
+		})
+	}
+
+	if *remaining {
+		fmt.Printf("%s %d\n", code, left)
+		return 0
+	}
+	fmt.Println(code)
+	return 0
+}
+
+// secondsRemaining returns the whole seconds until the period containing t ends
+func secondsRemaining(t time.Time, period int) int {
+	return period - int(t.Unix()%int64(period))
+}
+
+// alignToBoundary checks whether t falls in the last boundaryWindow of its
+// period. If so it either waits for the next period and returns its start,
+// or warns that the code is about to expire and returns t unchanged.
+func alignToBoundary(t time.Time, period int, wait bool) time.Time {
+	length := time.Duration(period) * time.Second
+	left := length - time.Duration(t.UnixNano()%int64(length))
+	if left > boundaryWindow {
+		return t
+	}
+
+	if !wait {
+		fmt.Fprintf(os.Stderr, "Warning: code expires in %s and may be rejected (use --wait-boundary to wait for the next one)\n", left.Round(time.Millisecond))
+		return t
+	}
+
+	fmt.Fprintf(os.Stderr, "Waiting %s for the next period...\n", left.Round(time.Millisecond))
+	sleep(left)
+	return t.Add(left)
+}
+
+// useClipboardSetting selects the clipboard backend saved with
+// config --clipboard; an invalid saved value keeps automatic detection
+func useClipboardSetting(settings storage.Settings) {
+	backend, err := clipboard.ParseBackend(settings.Clipboard)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; using %s\n", err, clipboard.BackendAuto)
+		backend = clipboard.BackendAuto
+	}
+	clipboard.SetBackend(backend)
+}
+
+// CopyCommand copies the current TOTP code for a service to the clipboard
+// (shorthand for generate --copy)
+func CopyCommand(args []string) int {
+	return GenerateCommand(append([]string{"--copy"}, args...))
+}
+
+// recordCopy records a copy in the service's last-used time and usage count
+// (as the TUI does) and saves. The code was already copied, so a failed save
+// is only a warning.
+func recordCopy(store *storage.Store, name string) {
+	if err := store.RecordUse(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: usage not recorded: %v\n", err)
+		return
+	}
+	if err := store.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: usage not recorded: %v\n", err)
+	}
+}
+
+// copyAndClear copies code, calls copied once the copy succeeded and, when
+// clearSeconds > 0, waits to clear the clipboard so the code does not
+// outlive the command
+func copyAndClear(code string, clearSeconds int, copied func()) int {
+	if err := copyToClipboard(code); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: clipboard unavailable: %v\n", err)
+		return 1
+	}
+	copied()
+
+	if clearSeconds == 0 {
+		fmt.Println("✓ Copied to clipboard")
+		return 0
+	}
+
+	fmt.Printf("✓ Copied to clipboard (clears in %ds)\n", clearSeconds)
+	clearClipboard(code, clearSeconds).Wait()
+	return 0
+}
@@ -0,0 +1,317 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/clipboard"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// rfc6238Secret is the RFC 6238 SHA1 test key "12345678901234567890" in Base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// captureStdout runs fn and returns everything it wrote to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	oldStdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = oldStdout }()
+
+	fn()
+
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read captured output: %v", err)
+	}
+	return string(out)
+}
+
+// TestGenerateCommand_At tests --at against RFC 6238 test vectors (6-digit truncation)
+func TestGenerateCommand_At(t *testing.T) {
+	setupTestStorage(t, "test-passphrase", storage.Service{
+		Name: "RFC", Secret: rfc6238Secret, CreatedAt: time.Now(),
+	})
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	tests := []struct {
+		at   string
+		want string
+	}{
+		{"1970-01-01T00:00:59Z", "287082"},
+		{"2005-03-18T01:58:29Z", "081804"},
+		{"2009-02-13T23:31:30Z", "005924"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.at, func(t *testing.T) {
+			var code int
+			out := captureStdout(t, func() {
+				code = GenerateCommand([]string{"--name", "RFC", "--at", tt.at})
+			})
+			if code != 0 {
+				t.Fatalf("GenerateCommand() = %d, want 0", code)
+			}
+			if got := strings.TrimSpace(out); got != tt.want {
+				t.Errorf("Code at %s = %q, want %q", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGenerateCommand_PositionalRemaining tests the name as an argument,
+// with flags after it, and --remaining output
+func TestGenerateCommand_PositionalRemaining(t *testing.T) {
+	setupTestStorage(t, "test-passphrase", storage.Service{
+		Name: "RFC", Secret: rfc6238Secret, CreatedAt: time.Now(),
+	})
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = GenerateCommand([]string{"RFC", "--at", "1970-01-01T00:00:50Z", "--remaining"})
+	})
+	if code != 0 {
+		t.Fatalf("GenerateCommand() = %d, want 0", code)
+	}
+	if got := strings.TrimSpace(out); got != "287082 10" {
+		t.Errorf("Output = %q, want %q", got, "287082 10")
+	}
+}
+
+// TestGenerateCommand_Clock tests the injectable clock is used without --at
+func TestGenerateCommand_Clock(t *testing.T) {
+	setupTestStorage(t, "test-passphrase", storage.Service{
+		Name: "RFC", Secret: rfc6238Secret, CreatedAt: time.Now(),
+	})
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	oldNow := now
+	now = func() time.Time { return time.Unix(59, 0) }
+	defer func() { now = oldNow }()
+
+	var code int
+	out := captureStdout(t, func() {
+		code = GenerateCommand([]string{"--name", "rfc"})
+	})
+	if code != 0 {
+		t.Fatalf("GenerateCommand() = %d, want 0", code)
+	}
+	if got := strings.TrimSpace(out); got != "287082" {
+		t.Errorf("Code = %q, want %q", got, "287082")
+	}
+}
+
+// TestGenerateCommand_WaitBoundary tests that in the last second of a period
+// --wait-boundary returns the next window's code, and without it the current
+// code is printed right away
+func TestGenerateCommand_WaitBoundary(t *testing.T) {
+	service := storage.Service{Name: "RFC", Secret: rfc6238Secret, CreatedAt: time.Now()}
+	setupTestStorage(t, "test-passphrase", service)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	current, _ := service.Code(time.Unix(59, 0))
+	next, _ := service.Code(time.Unix(60, 0))
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       string
+		wantSleeps int
+	}{
+		{"warns only", []string{"--name", "RFC"}, current, 0},
+		{"waits for the next period", []string{"--name", "RFC", "--wait-boundary"}, next, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sleeps := fakeClock(t, time.Unix(59, 200_000_000))
+
+			var code int
+			out := captureStdout(t, func() { code = GenerateCommand(tt.args) })
+			if code != 0 {
+				t.Fatalf("GenerateCommand() = %d, want 0", code)
+			}
+			if got := strings.TrimSpace(out); got != tt.want {
+				t.Errorf("Code = %q, want %q", got, tt.want)
+			}
+			if *sleeps != tt.wantSleeps {
+				t.Errorf("sleeps = %d, want %d", *sleeps, tt.wantSleeps)
+			}
+		})
+	}
+}
+
+// TestAlignToBoundary tests only times in the last second of a period wait
+func TestAlignToBoundary(t *testing.T) {
+	fakeClock(t, time.Unix(0, 0))
+
+	mid := time.Unix(45, 0)
+	if got := alignToBoundary(mid, 30, true); !got.Equal(mid) {
+		t.Errorf("alignToBoundary(mid-period) = %v, want unchanged", got)
+	}
+
+	late := time.Unix(89, 250_000_000)
+	if got := alignToBoundary(late, 30, true); !got.Equal(time.Unix(90, 0)) {
+		t.Errorf("alignToBoundary(last second) = %v, want the next boundary", got)
+	}
+	if got := alignToBoundary(late, 60, true); !got.Equal(late) {
+		t.Errorf("alignToBoundary(60s period) = %v, want unchanged", got)
+	}
+}
+
+// TestGenerateCommand_InvalidArgs tests argument validation
+func TestGenerateCommand_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Missing name", []string{}},
+		{"Invalid --at", []string{"--name", "RFC", "--at", "yesterday"}},
+		{"--wait-boundary with --at", []string{"--name", "RFC", "--wait-boundary", "--at", "2024-01-01T00:00:30Z"}},
+		{"Two names", []string{"RFC", "AWS"}},
+		{"--name and an argument", []string{"--name", "RFC", "AWS"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := GenerateCommand(tt.args); code != 1 {
+				t.Errorf("GenerateCommand() = %d, want 1", code)
+			}
+		})
+	}
+}
+
+// TestGenerateCommand_UnknownService tests a missing service fails
+func TestGenerateCommand_UnknownService(t *testing.T) {
+	setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	if code := GenerateCommand([]string{"--name", "Missing"}); code != 1 {
+		t.Errorf("GenerateCommand() = %d, want 1", code)
+	}
+}
+
+// stubClipboard records copies and scheduled clears; the returned clear
+// handles complete immediately
+func stubClipboard(t *testing.T) (copied *string, clearSeconds *int) {
+	t.Helper()
+
+	var text string
+	seconds := -1
+	oldCopy, oldClear := copyToClipboard, clearClipboard
+	t.Cleanup(func() { copyToClipboard, clearClipboard = oldCopy, oldClear })
+
+	copyToClipboard = func(s string) error {
+		text = s
+		return nil
+	}
+	clearClipboard = func(s string, n int) *clipboard.ScheduledClear {
+		seconds = n
+		return clipboard.ClearAfter(s, 0)
+	}
+	return &text, &seconds
+}
+
+// TestGenerateCommand_CopyClipClear tests --copy honors --clip-clear-seconds
+// and that 0 never schedules a clear
+func TestGenerateCommand_CopyClipClear(t *testing.T) {
+	setupTestStorage(t, "test-passphrase", storage.Service{
+		Name: "RFC", Secret: rfc6238Secret, CreatedAt: time.Now(),
+	})
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	oldNow := now
+	now = func() time.Time { return time.Unix(59, 0) }
+	defer func() { now = oldNow }()
+
+	tests := []struct {
+		name        string
+		run         func() int
+		wantSeconds int
+	}{
+		{"generate --copy with clear", func() int {
+			return GenerateCommand([]string{"--name", "RFC", "--copy", "--clip-clear-seconds", "20"})
+		}, 20},
+		{"copy with clear", func() int {
+			return CopyCommand([]string{"--name", "RFC", "--clip-clear-seconds", "45"})
+		}, 45},
+		{"0 never clears", func() int {
+			return CopyCommand([]string{"--name", "RFC", "--clip-clear-seconds", "0"})
+		}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copied, seconds := stubClipboard(t)
+
+			var code int
+			out := captureStdout(t, func() { code = tt.run() })
+			if code != 0 {
+				t.Fatalf("Exit code = %d, want 0", code)
+			}
+			if *copied != "287082" {
+				t.Errorf("Copied %q, want 287082", *copied)
+			}
+			if *seconds != tt.wantSeconds {
+				t.Errorf("Clear scheduled after %d, want %d", *seconds, tt.wantSeconds)
+			}
+			if strings.Contains(out, "287082") {
+				t.Errorf("Code should not be printed when copying: %q", out)
+			}
+		})
+	}
+}
+
+// TestGenerateCommand_CopyRecordsUse tests copying counts as a use like a TUI
+// copy, unless --no-last-used-save is given
+func TestGenerateCommand_CopyRecordsUse(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase", storage.Service{
+		Name: "RFC", Secret: rfc6238Secret, CreatedAt: time.Now(),
+	})
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	stubClipboard(t)
+
+	useCount := func() (int, bool) {
+		t.Helper()
+		store, err := storage.Load(path, "test-passphrase")
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		service, _ := store.GetServiceCopy("RFC")
+		return service.UseCount, service.LastUsed != nil
+	}
+
+	captureStdout(t, func() { GenerateCommand([]string{"--name", "RFC", "--copy"}) })
+	captureStdout(t, func() { CopyCommand([]string{"--name", "RFC"}) })
+	if count, used := useCount(); count != 2 || !used {
+		t.Errorf("UseCount = %d (last used set: %v), want 2 after two copies", count, used)
+	}
+
+	captureStdout(t, func() { GenerateCommand([]string{"--name", "RFC", "--copy", "--no-last-used-save"}) })
+	if count, _ := useCount(); count != 2 {
+		t.Errorf("UseCount = %d, --no-last-used-save should not record the copy", count)
+	}
+
+	// Printing a code is not a copy
+	captureStdout(t, func() { GenerateCommand([]string{"--name", "RFC"}) })
+	if count, _ := useCount(); count != 2 {
+		t.Errorf("UseCount = %d, printing should not count as a use", count)
+	}
+}
+
+// TestGenerateCommand_NegativeClipClear tests a negative value is rejected
+func TestGenerateCommand_NegativeClipClear(t *testing.T) {
+	if code := GenerateCommand([]string{"--name", "RFC", "--copy", "--clip-clear-seconds", "-5"}); code != 1 {
+		t.Errorf("GenerateCommand() = %d, want 1", code)
+	}
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/otpauth"
+	"github.com/pavanprakash21/totp-manager-go/internal/qr"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+	"github.com/pavanprakash21/totp-manager-go/internal/totp"
+)
+
+// importSource is one URI to import and where it came from (for messages)
+type importSource struct {
+	name string
+	uri  string
+}
+
+// ImportCommand bulk-imports accounts from Google Authenticator "Transfer
+// accounts" QR codes (otpauth-migration://), given as URIs or QR images.
+// QR images holding a plain otpauth:// URI are imported too.
+func ImportCommand(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	var migrationURIs, qrImages stringList
+	fs.Var(&migrationURIs, "migration-uri", "otpauth-migration://offline?data=... URI to import (repeatable, one per exported QR code)")
+	fs.Var(&qrImages, "qr", "QR code image (PNG/JPEG) to import (repeatable)")
+	onConflict := fs.String("on-conflict", "skip", "What to do when a service name exists: skip, replace or rename")
+	dryRun := fs.Bool("dry-run", false, "Report what would be imported without saving")
+	assumedAlgorithm := fs.String("assume-algorithm", otpauth.DefaultAlgorithm, "Algorithm for accounts that omit one: SHA1, SHA256 or SHA512")
+	checkOnly := fs.Bool("check-only", false, "Validate every account without unlocking or touching storage")
+	noAtomic := fs.Bool("no-atomic", false, noAtomicUsage)
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if len(migrationURIs) == 0 && len(qrImages) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: --migration-uri or --qr is required")
+		fmt.Fprintln(os.Stderr, "Usage: totp import [--on-conflict skip|replace|rename] [--dry-run] [--check-only] [--assume-algorithm ALG] (--migration-uri URI | --qr IMAGE)...")
+		return 1
+	}
+
+	strategy, err := storage.ParseConflictStrategy(*onConflict)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	assumed := strings.ToUpper(*assumedAlgorithm)
+	if err := totp.ValidateParams(assumed, totp.DefaultDigits, totp.DefaultPeriod); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --assume-algorithm: %v\n", err)
+		return 1
+	}
+
+	// Decode every QR image before asking for the passphrase
+	var sources []importSource
+	for i, uri := range migrationURIs {
+		sources = append(sources, importSource{name: fmt.Sprintf("migration URI %d", i+1), uri: uri})
+	}
+	for _, path := range qrImages {
+		text, err := qr.Decode(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to read QR code %s: %v\n", path, err)
+			return 1
+		}
+		sources = append(sources, importSource{name: path, uri: text})
+	}
+
+	// Validation only: the store is never unlocked
+	if *checkOnly {
+		return checkImportSources(sources, assumed)
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	app.noAtomic = *noAtomic
+
+	// A dry run must not create a store either
+	initialize := app.Initialize
+	if *dryRun {
+		initialize = app.InitializeExisting
+	}
+	if err := initialize(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	summary := importSummary{}
+	assumedCount := 0
+	for _, source := range sources {
+		keys, skipped, err := parseImportURI(source.uri)
+		if err != nil {
+			fmt.Printf("✗ %s: %v\n", source.name, err)
+			summary[storage.ImportFailed]++
+			continue
+		}
+		for _, reason := range skipped {
+			fmt.Printf("⚠ %s: skipped %s\n", source.name, reason)
+			summary[storage.ImportSkipped]++
+		}
+
+		for _, key := range keys {
+			if assumeAlgorithm(key, assumed) {
+				assumedCount++
+			}
+			result := app.store.Import(key.Service(time.Now()), strategy)
+			summary[result.Outcome]++
+			printImportResult(source.name, result)
+		}
+	}
+
+	if *dryRun {
+		fmt.Printf("Dry run: %s (nothing saved)\n", summary)
+	} else {
+		if summary[storage.ImportAdded]+summary[storage.ImportReplaced] > 0 {
+			if err := app.store.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving storage: %v\n", err)
+				return 1
+			}
+		}
+		fmt.Printf("Imported: %s\n", summary)
+	}
+	printAssumedAlgorithm(assumedCount, assumed)
+
+	if summary[storage.ImportFailed] > 0 {
+		return 1
+	}
+	return 0
+}
+
+// checkImportSources validates every account in sources, reporting each
+// problem. Accounts the parser skips are reported but not counted as invalid.
+// Returns 1 if any account is invalid.
+func checkImportSources(sources []importSource, assumed string) int {
+	valid, invalid, assumedCount := 0, 0, 0
+	for _, source := range sources {
+		keys, skipped, err := parseImportURI(source.uri)
+		if err != nil {
+			fmt.Printf("✗ %s: %v\n", source.name, err)
+			invalid++
+			continue
+		}
+		for _, reason := range skipped {
+			fmt.Printf("⚠ %s: skipped %s\n", source.name, reason)
+		}
+
+		for _, key := range keys {
+			if assumeAlgorithm(key, assumed) {
+				assumedCount++
+			}
+			if checkKey(source.name, key) {
+				valid++
+			} else {
+				invalid++
+			}
+		}
+	}
+
+	return printCheckSummary(valid, invalid, assumedCount, assumed)
+}
+
+// parseImportURI parses a migration URI into its accounts, or a plain
+// otpauth URI into a single key
+func parseImportURI(uri string) ([]*otpauth.Key, []string, error) {
+	if otpauth.IsMigrationURI(uri) {
+		return otpauth.ParseMigration(uri)
+	}
+
+	key, err := otpauth.Parse(uri)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid otpauth URI: %w", err)
+	}
+	return []*otpauth.Key{key}, nil, nil
+}
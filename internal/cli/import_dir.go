@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/otpauth"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+	"github.com/pavanprakash21/totp-manager-go/internal/totp"
+)
+
+// importDirExtensions are the file types scanned by import-dir
+var importDirExtensions = map[string]bool{".txt": true, ".uri": true}
+
+// importSummary counts import outcomes
+type importSummary map[storage.ImportOutcome]int
+
+// ImportDirCommand imports every otpauth URI found in .txt/.uri files under a directory
+func ImportDirCommand(args []string) int {
+	flags := flag.NewFlagSet("import-dir", flag.ExitOnError)
+	onConflict := flags.String("on-conflict", "skip", "What to do when a service name exists: skip, replace or rename")
+	dryRun := flags.Bool("dry-run", false, "Report what would be imported without saving")
+	assumedAlgorithm := flags.String("assume-algorithm", otpauth.DefaultAlgorithm, "Algorithm for URIs that omit one: SHA1, SHA256 or SHA512")
+	checkOnly := flags.Bool("check-only", false, "Validate every entry without unlocking or touching storage")
+	noAtomic := flags.Bool("no-atomic", false, noAtomicUsage)
+
+	if err := flags.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Error: exactly one directory is required")
+		fmt.Fprintln(os.Stderr, "Usage: totp import-dir [--on-conflict skip|replace|rename] [--dry-run] [--check-only] [--assume-algorithm ALG] DIR")
+		return 1
+	}
+	dir := flags.Arg(0)
+
+	strategy, err := storage.ParseConflictStrategy(*onConflict)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	assumed := strings.ToUpper(*assumedAlgorithm)
+	if err := totp.ValidateParams(assumed, totp.DefaultDigits, totp.DefaultPeriod); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --assume-algorithm: %v\n", err)
+		return 1
+	}
+
+	files, err := findURIFiles(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Validation only: the store is never unlocked
+	if *checkOnly {
+		return checkURIFiles(dir, files, assumed)
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	app.noAtomic = *noAtomic
+
+	// A dry run must not create a store either
+	initialize := app.Initialize
+	if *dryRun {
+		initialize = app.InitializeExisting
+	}
+	if err := initialize(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	summary := importSummary{}
+	assumedCount := 0
+	for _, path := range files {
+		rel, _ := filepath.Rel(dir, path)
+		uris, err := readURILines(path)
+		if err != nil {
+			fmt.Printf("✗ %s: %v\n", rel, err)
+			summary[storage.ImportFailed]++
+			continue
+		}
+
+		for _, uri := range uris {
+			key, err := otpauth.Parse(uri)
+			if err != nil {
+				fmt.Printf("✗ %s: invalid otpauth URI: %v\n", rel, err)
+				summary[storage.ImportFailed]++
+				continue
+			}
+			if assumeAlgorithm(key, assumed) {
+				assumedCount++
+			}
+
+			result := app.store.Import(key.Service(time.Now()), strategy)
+			summary[result.Outcome]++
+			printImportResult(rel, result)
+		}
+	}
+
+	if *dryRun {
+		fmt.Printf("Dry run: %s (nothing saved)\n", summary)
+	} else {
+		if summary[storage.ImportAdded]+summary[storage.ImportReplaced] > 0 {
+			if err := app.store.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving storage: %v\n", err)
+				return 1
+			}
+		}
+		fmt.Printf("Imported: %s\n", summary)
+	}
+	printAssumedAlgorithm(assumedCount, assumed)
+
+	if summary[storage.ImportFailed] > 0 {
+		return 1
+	}
+	return 0
+}
+
+// printImportResult reports one imported service, prefixed with its source
+func printImportResult(source string, result storage.ImportResult) {
+	if result.Err != nil {
+		fmt.Printf("✗ %s: '%s': %v\n", source, result.Name, result.Err)
+		return
+	}
+	if result.Warning != "" {
+		fmt.Printf("⚠ %s: '%s': %s\n", source, result.Name, result.Warning)
+	}
+	if result.RenamedFrom != "" {
+		fmt.Printf("✓ %s: %s '%s' (renamed from '%s')\n", source, result.Outcome, result.Name, result.RenamedFrom)
+		return
+	}
+	fmt.Printf("✓ %s: %s '%s'\n", source, result.Outcome, result.Name)
+}
+
+// String renders the counts, e.g. "2 added, 0 replaced, 1 skipped, 0 failed"
+func (s importSummary) String() string {
+	return fmt.Sprintf("%d added, %d replaced, %d skipped, %d failed",
+		s[storage.ImportAdded], s[storage.ImportReplaced], s[storage.ImportSkipped], s[storage.ImportFailed])
+}
+
+// checkURIFiles parses every URI in files and validates the resulting
+// services, reporting each problem. Returns 1 if any entry is invalid.
+func checkURIFiles(dir string, files []string, assumed string) int {
+	valid, invalid, assumedCount := 0, 0, 0
+	for _, path := range files {
+		rel, _ := filepath.Rel(dir, path)
+		uris, err := readURILines(path)
+		if err != nil {
+			fmt.Printf("✗ %s: %v\n", rel, err)
+			invalid++
+			continue
+		}
+
+		for _, uri := range uris {
+			key, err := otpauth.Parse(uri)
+			if err != nil {
+				fmt.Printf("✗ %s: invalid otpauth URI: %v\n", rel, err)
+				invalid++
+				continue
+			}
+			if assumeAlgorithm(key, assumed) {
+				assumedCount++
+			}
+
+			if checkKey(rel, key) {
+				valid++
+			} else {
+				invalid++
+			}
+		}
+	}
+
+	return printCheckSummary(valid, invalid, assumedCount, assumed)
+}
+
+// checkKey validates the service a key would import as, reporting the
+// result prefixed with its source
+func checkKey(source string, key *otpauth.Key) bool {
+	service := key.Service(time.Now())
+	if err := service.Validate(); err != nil {
+		fmt.Printf("✗ %s: '%s': %v\n", source, service.Name, err)
+		return false
+	}
+	fmt.Printf("✓ %s: '%s' is valid\n", source, service.Name)
+	return true
+}
+
+// printCheckSummary reports a check-only run. Returns 1 if any entry is invalid.
+func printCheckSummary(valid, invalid, assumedCount int, assumed string) int {
+	fmt.Printf("Checked: %d valid, %d invalid (storage not opened)\n", valid, invalid)
+	printAssumedAlgorithm(assumedCount, assumed)
+	if invalid > 0 {
+		return 1
+	}
+	return 0
+}
+
+// assumeAlgorithm gives a key whose URI omitted the algorithm the batch's
+// assumed one, reporting whether it did
+func assumeAlgorithm(key *otpauth.Key, algorithm string) bool {
+	if !key.AlgorithmDefaulted {
+		return false
+	}
+	key.Algorithm = algorithm
+	return true
+}
+
+// printAssumedAlgorithm reports how many entries relied on the assumed algorithm
+func printAssumedAlgorithm(count int, algorithm string) {
+	switch {
+	case count == 1:
+		fmt.Printf("1 entry had no algorithm and used %s\n", algorithm)
+	case count > 1:
+		fmt.Printf("%d entries had no algorithm and used %s\n", count, algorithm)
+	}
+}
+
+// findURIFiles returns the .txt/.uri files under dir in lexical order
+func findURIFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && importDirExtensions[strings.ToLower(filepath.Ext(path))] {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	return files, nil
+}
+
+// readURILines returns the non-empty lines of a URI file
+func readURILines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	var uris []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			uris = append(uris, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(uris) == 0 {
+		return nil, fmt.Errorf("no otpauth URI found")
+	}
+	return uris, nil
+}
+
+// keyFromService converts a service back into an otpauth key. Services that
+// were imported keep their original issuer and label regardless of later
+// renames; others are labelled from the name and identifier.
+func keyFromService(service *storage.Service) *otpauth.Key {
+	key := &otpauth.Key{
+		Issuer:    service.Issuer,
+		Account:   service.Identifier,
+		Label:     service.Label,
+		Secret:    service.Secret,
+		Algorithm: service.EffectiveAlgorithm(),
+		Digits:    service.EffectiveDigits(),
+		Period:    service.EffectivePeriod(),
+	}
+
+	if key.Issuer == "" && key.Label == "" {
+		if key.Account == "" {
+			key.Account = service.Name
+		} else {
+			key.Issuer = service.Name
+		}
+	}
+
+	return key
+}
@@ -0,0 +1,373 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/otpauth"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// writeURIDir creates a directory of URI files: two valid, one invalid and
+// one ignored by extension
+func writeURIDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"github.uri":      "otpauth://totp/GitHub:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=GitHub\n",
+		"nested/okta.txt": "otpauth://totp/Okta:bob?secret=GEZDGNBVGY3TQOJQ&algorithm=SHA256&digits=8\n",
+		"broken.txt":      "not a uri\n",
+		"notes.md":        "otpauth://totp/Ignored?secret=JBSWY3DPEHPK3PXP\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	return dir
+}
+
+// TestImportDirCommand tests valid files are imported and invalid ones counted
+func TestImportDirCommand(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	dir := writeURIDir(t)
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ImportDirCommand([]string{dir})
+	})
+	if code != 1 {
+		t.Errorf("ImportDirCommand() = %d, want 1 (one invalid file)", code)
+	}
+	if !strings.Contains(out, "Imported: 2 added, 0 replaced, 0 skipped, 1 failed") {
+		t.Errorf("Unexpected summary: %q", out)
+	}
+	if !strings.Contains(out, "✗ broken.txt") {
+		t.Errorf("Expected the invalid file to be reported, got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(store.Services) != 2 {
+		t.Fatalf("Stored %d services, want 2", len(store.Services))
+	}
+	okta, err := store.GetServiceCopy("Okta")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if okta.Identifier != "bob" || okta.Algorithm != "SHA256" || okta.Digits != 8 {
+		t.Errorf("Okta = %+v, want identifier bob, SHA256, 8 digits", okta)
+	}
+}
+
+// TestImportDirCommand_DryRun tests a dry run reports counts without saving
+func TestImportDirCommand_DryRun(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	dir := writeURIDir(t)
+
+	out := captureStdout(t, func() {
+		ImportDirCommand([]string{"--dry-run", dir})
+	})
+	if !strings.Contains(out, "Dry run: 2 added, 0 replaced, 0 skipped, 1 failed (nothing saved)") {
+		t.Errorf("Unexpected summary: %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(store.Services) != 0 {
+		t.Errorf("Dry run stored %d services, want 0", len(store.Services))
+	}
+}
+
+// TestImportDirCommand_DryRunWithoutStore tests a dry run never creates a store
+func TestImportDirCommand_DryRunWithoutStore(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	if code := ImportDirCommand([]string{"--dry-run", writeURIDir(t)}); code != 1 {
+		t.Errorf("ImportDirCommand() = %d, want 1 without a store", code)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Dry run created the store (Stat error = %v)", err)
+	}
+}
+
+// TestImportDirCommand_Conflicts tests skip and replace strategies
+func TestImportDirCommand_Conflicts(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		wantSummary    string
+		wantIdentifier string
+	}{
+		{"Skip by default", nil, "0 added, 0 replaced, 1 skipped, 0 failed", "existing"},
+		{"Replace", []string{"--on-conflict", "replace"}, "0 added, 1 replaced, 0 skipped, 0 failed", "alice@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := setupTestStorage(t, "test-passphrase",
+				storage.Service{Name: "GitHub", Identifier: "existing", Secret: "GEZDGNBVGY3TQOJQ", CreatedAt: time.Now()},
+			)
+			t.Setenv(passphraseEnvVar, "test-passphrase")
+
+			dir := t.TempDir()
+			uri := "otpauth://totp/GitHub:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=GitHub"
+			if err := os.WriteFile(filepath.Join(dir, "github.uri"), []byte(uri), 0600); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+
+			var code int
+			out := captureStdout(t, func() {
+				code = ImportDirCommand(append(tt.args, dir))
+			})
+			if code != 0 {
+				t.Errorf("ImportDirCommand() = %d, want 0", code)
+			}
+			if !strings.Contains(out, tt.wantSummary) {
+				t.Errorf("Output %q missing %q", out, tt.wantSummary)
+			}
+
+			store, err := storage.Load(path, "test-passphrase")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if store.Services[0].Identifier != tt.wantIdentifier {
+				t.Errorf("Identifier = %q, want %q", store.Services[0].Identifier, tt.wantIdentifier)
+			}
+		})
+	}
+}
+
+// TestImportDirCommand_Rename tests two imports colliding with an existing
+// service both land under distinct names
+func TestImportDirCommand_Rename(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Identifier: "existing", Secret: "GEZDGNBVGY3TQOJQ", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	dir := t.TempDir()
+	content := "otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP&issuer=GitHub\n" +
+		"otpauth://totp/GitHub:alice?secret=MFRGGZDFMZTWQ2LK&issuer=GitHub\n"
+	if err := os.WriteFile(filepath.Join(dir, "github.txt"), []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ImportDirCommand([]string{"--on-conflict", "rename", dir})
+	})
+	if code != 0 {
+		t.Fatalf("ImportDirCommand() = %d, want 0\n%s", code, out)
+	}
+	if !strings.Contains(out, "added 'GitHub (alice)' (renamed from 'GitHub')") {
+		t.Errorf("Expected the rename to be reported, got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for _, name := range []string{"GitHub", "GitHub (alice)", "GitHub 2"} {
+		if _, err := store.GetServiceCopy(name); err != nil {
+			t.Errorf("GetServiceCopy(%q) error = %v", name, err)
+		}
+	}
+}
+
+// TestImportDirCommand_InvalidArgs tests usage errors
+func TestImportDirCommand_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"No directory", []string{}},
+		{"Unknown strategy", []string{"--on-conflict", "merge", t.TempDir()}},
+		{"Missing directory", []string{filepath.Join(t.TempDir(), "missing")}},
+		{"Unknown assumed algorithm", []string{"--assume-algorithm", "MD5", t.TempDir()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := ImportDirCommand(tt.args); code != 1 {
+				t.Errorf("ImportDirCommand() = %d, want 1", code)
+			}
+		})
+	}
+}
+
+// TestKeyFromService_PreservesProvenance tests that import→export keeps the
+// original issuer and label after the service is renamed
+func TestKeyFromService_PreservesProvenance(t *testing.T) {
+	original := "otpauth://totp/ACME%20Inc.:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME&digits=8"
+	key, err := otpauth.Parse(original)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	service := key.Service(time.Now())
+	if service.Issuer != "ACME" || service.Label != "ACME Inc.:alice@example.com" {
+		t.Fatalf("Provenance not stored: issuer %q label %q", service.Issuer, service.Label)
+	}
+	service.Name = "Work VPN"
+
+	exported, err := otpauth.Parse(keyFromService(&service).URI())
+	if err != nil {
+		t.Fatalf("Parse(export) error = %v", err)
+	}
+	if *exported != *key {
+		t.Errorf("Export = %+v, want original %+v", *exported, *key)
+	}
+}
+
+// TestKeyFromService_WithoutProvenance tests labelling of manually added services
+func TestKeyFromService_WithoutProvenance(t *testing.T) {
+	tests := []struct {
+		name        string
+		service     storage.Service
+		wantIssuer  string
+		wantAccount string
+	}{
+		{"Name only", storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP"}, "", "GitHub"},
+		{"Name and identifier", storage.Service{Name: "GitHub", Identifier: "octocat", Secret: "JBSWY3DPEHPK3PXP"}, "GitHub", "octocat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := keyFromService(&tt.service)
+			if key.Issuer != tt.wantIssuer || key.Account != tt.wantAccount {
+				t.Errorf("keyFromService() issuer %q account %q, want %q and %q", key.Issuer, key.Account, tt.wantIssuer, tt.wantAccount)
+			}
+			if key.Algorithm != "SHA1" || key.Digits != 6 || key.Period != 30 {
+				t.Errorf("Params = %s/%d/%d, want defaults", key.Algorithm, key.Digits, key.Period)
+			}
+		})
+	}
+}
+
+// TestImportDirCommand_CheckOnly tests that check-only reports an invalid
+// entry and exits non-zero without unlocking or creating storage
+func TestImportDirCommand_CheckOnly(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(passphraseEnvVar, "")
+
+	dir := t.TempDir()
+	content := "otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP&issuer=GitHub\n" +
+		"otpauth://totp/Short?secret=ABCD\n"
+	if err := os.WriteFile(filepath.Join(dir, "codes.txt"), []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ImportDirCommand([]string{"--check-only", dir})
+	})
+	if code != 1 {
+		t.Errorf("ImportDirCommand() = %d, want 1", code)
+	}
+	if !strings.Contains(out, "✗ codes.txt: 'Short'") {
+		t.Errorf("Expected the invalid entry to be reported, got %q", out)
+	}
+	if !strings.Contains(out, "Checked: 1 valid, 1 invalid") {
+		t.Errorf("Unexpected summary: %q", out)
+	}
+
+	if _, err := os.Stat(filepath.Join(home, ".config", "totp-manager")); !os.IsNotExist(err) {
+		t.Errorf("Check-only should not touch storage, stat error = %v", err)
+	}
+}
+
+// TestServiceFromKey_PaddedSecret tests a padded URI secret is stored like
+// its unpadded form and generates the same code
+func TestServiceFromKey_PaddedSecret(t *testing.T) {
+	padded, err := otpauth.Parse("otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXPJBSW====")
+	if err != nil {
+		t.Fatalf("Parse(padded) error = %v", err)
+	}
+	unpadded, err := otpauth.Parse("otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXPJBSW")
+	if err != nil {
+		t.Fatalf("Parse(unpadded) error = %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	a, b := padded.Service(now), unpadded.Service(now)
+	if a.Secret != b.Secret {
+		t.Errorf("Stored secrets differ: %q vs %q", a.Secret, b.Secret)
+	}
+	if err := a.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	codeA, err := a.Code(now)
+	if err != nil {
+		t.Fatalf("Code(padded) error = %v", err)
+	}
+	codeB, err := b.Code(now)
+	if err != nil {
+		t.Fatalf("Code(unpadded) error = %v", err)
+	}
+	if codeA != codeB {
+		t.Errorf("Codes differ: %s vs %s", codeA, codeB)
+	}
+}
+
+// TestImportDirCommand_AssumeAlgorithm tests URIs without an algorithm adopt
+// the assumed one while explicit algorithms are kept
+func TestImportDirCommand_AssumeAlgorithm(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	dir := t.TempDir()
+	content := "otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP&issuer=GitHub\n" +
+		"otpauth://totp/Okta:bob?secret=GEZDGNBVGY3TQOJQ&issuer=Okta\n" +
+		"otpauth://totp/AWS:root?secret=MFRGGZDFMZTWQ2LK&issuer=AWS&algorithm=SHA512\n"
+	if err := os.WriteFile(filepath.Join(dir, "codes.txt"), []byte(content), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ImportDirCommand([]string{"--assume-algorithm", "sha256", dir})
+	})
+	if code != 0 {
+		t.Fatalf("ImportDirCommand() = %d, want 0\n%s", code, out)
+	}
+	if !strings.Contains(out, "2 entries had no algorithm and used SHA256") {
+		t.Errorf("Expected the assumed count to be reported, got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]string{"GitHub": "SHA256", "Okta": "SHA256", "AWS": "SHA512"}
+	for name, algorithm := range want {
+		service, err := store.GetServiceCopy(name)
+		if err != nil {
+			t.Fatalf("GetServiceCopy(%q) error = %v", name, err)
+		}
+		if got := service.EffectiveAlgorithm(); got != algorithm {
+			t.Errorf("%s algorithm = %s, want %s", name, got, algorithm)
+		}
+	}
+}
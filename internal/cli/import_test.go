@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// testMigrationURI holds GitHub:alice@example.com (SHA1, 6 digits),
+// AWS:root (SHA512, 8 digits) and an HOTP account named Counter
+const testMigrationURI = "otpauth-migration://offline?data=Ci0KCkhlbGxvId6tvu8SEWFsaWNlQGV4YW1wbGUuY29tGgZHaXRIdWIgASgBMAIKHAoKSGVsbG8h3q2%2B7xIIQVdTOnJvb3QgAygCMAIKGwoKSGVsbG8h3q2%2B7xIHQ291bnRlciABKAEwARABGAEgAA%3D%3D"
+
+// TestImportCommand_MigrationURI tests TOTP accounts are imported and HOTP
+// ones reported as skipped
+func TestImportCommand_MigrationURI(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ImportCommand([]string{"--migration-uri", testMigrationURI})
+	})
+	if code != 0 {
+		t.Fatalf("ImportCommand() = %d, want 0; output %q", code, out)
+	}
+	if !strings.Contains(out, "Imported: 2 added, 0 replaced, 1 skipped, 0 failed") {
+		t.Errorf("Unexpected summary: %q", out)
+	}
+	if !strings.Contains(out, `skipped "Counter"`) {
+		t.Errorf("Expected the HOTP account to be reported, got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(store.Services) != 2 {
+		t.Fatalf("Stored %d services, want 2", len(store.Services))
+	}
+	github, err := store.GetServiceCopy("GitHub")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if github.Identifier != "alice@example.com" || github.Secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("GitHub = %+v, want identifier alice@example.com and secret JBSWY3DPEHPK3PXP", github)
+	}
+	aws, err := store.GetServiceCopy("AWS")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if aws.Identifier != "root" || aws.Algorithm != "SHA512" || aws.Digits != 8 {
+		t.Errorf("AWS = %+v, want identifier root, SHA512, 8 digits", aws)
+	}
+}
+
+// TestImportCommand_QR tests migration and plain otpauth QR images
+func TestImportCommand_QR(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	dir := t.TempDir()
+	migrationImage := filepath.Join(dir, "transfer.png")
+	writeQRImage(t, migrationImage, testMigrationURI)
+	plainImage := filepath.Join(dir, "okta.png")
+	writeQRImage(t, plainImage, "otpauth://totp/Okta:bob?secret=GEZDGNBVGY3TQOJQ&issuer=Okta")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ImportCommand([]string{"--qr", migrationImage, "--qr", plainImage})
+	})
+	if code != 0 {
+		t.Fatalf("ImportCommand() = %d, want 0; output %q", code, out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for _, name := range []string{"GitHub", "AWS", "Okta"} {
+		if _, err := store.GetServiceCopy(name); err != nil {
+			t.Errorf("GetServiceCopy(%q) error = %v", name, err)
+		}
+	}
+}
+
+// TestImportCommand_DryRun tests a dry run saves nothing
+func TestImportCommand_DryRun(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ImportCommand([]string{"--dry-run", "--migration-uri", testMigrationURI})
+	})
+	if code != 0 {
+		t.Fatalf("ImportCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "Dry run: 2 added") {
+		t.Errorf("Unexpected summary: %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(store.Services) != 0 {
+		t.Errorf("Dry run stored %d services, want 0", len(store.Services))
+	}
+}
+
+// TestImportCommand_InvalidArgs tests missing sources and bad URIs fail
+func TestImportCommand_InvalidArgs(t *testing.T) {
+	setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	if code := ImportCommand(nil); code != 1 {
+		t.Errorf("ImportCommand() without sources = %d, want 1", code)
+	}
+	if code := ImportCommand([]string{"--on-conflict", "merge", "--migration-uri", testMigrationURI}); code != 1 {
+		t.Errorf("ImportCommand() with bad --on-conflict = %d, want 1", code)
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ImportCommand([]string{"--migration-uri", "otpauth-migration://offline"})
+	})
+	if code != 1 {
+		t.Errorf("ImportCommand() with invalid URI = %d, want 1", code)
+	}
+	if !strings.Contains(out, "✗ migration URI 1") {
+		t.Errorf("Expected the invalid URI to be reported, got %q", out)
+	}
+}
+
+// TestImportCommand_CheckOnly tests check-only validates every account
+// without unlocking or creating storage
+func TestImportCommand_CheckOnly(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(passphraseEnvVar, "")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ImportCommand([]string{"--check-only", "--migration-uri", testMigrationURI,
+			"--migration-uri", "otpauth://totp/Short?secret=ABCD"})
+	})
+	if code != 1 {
+		t.Errorf("ImportCommand() = %d, want 1", code)
+	}
+	for _, want := range []string{"✓ migration URI 1: 'GitHub' is valid", `skipped "Counter"`, "✗ migration URI 2: 'Short'", "Checked: 2 valid, 1 invalid"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output, got %q", want, out)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(home, ".config", "totp-manager")); !os.IsNotExist(err) {
+		t.Errorf("Check-only should not touch storage, stat error = %v", err)
+	}
+}
+
+// TestImportCommand_AssumeAlgorithm tests accounts without an algorithm adopt
+// the assumed one while explicit algorithms are kept
+func TestImportCommand_AssumeAlgorithm(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ImportCommand([]string{"--assume-algorithm", "sha256", "--migration-uri", testMigrationURI,
+			"--migration-uri", "otpauth://totp/Okta:bob?secret=GEZDGNBVGY3TQOJQ&issuer=Okta"})
+	})
+	if code != 0 {
+		t.Fatalf("ImportCommand() = %d, want 0; output %q", code, out)
+	}
+	if !strings.Contains(out, "1 entry had no algorithm and used SHA256") {
+		t.Errorf("Expected the assumed count to be reported, got %q", out)
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]string{"GitHub": "SHA1", "AWS": "SHA512", "Okta": "SHA256"}
+	for name, algorithm := range want {
+		service, err := store.GetServiceCopy(name)
+		if err != nil {
+			t.Fatalf("GetServiceCopy(%q) error = %v", name, err)
+		}
+		if got := service.EffectiveAlgorithm(); got != algorithm {
+			t.Errorf("%s algorithm = %s, want %s", name, got, algorithm)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// listEntry is the secret-free view of a service used for list output
+type listEntry struct {
+	Name       string     `json:"name"`
+	Identifier string     `json:"identifier,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsed   *time.Time `json:"last_used,omitempty"`
+
+	// Effective code parameters, only set with --show-params
+	Algorithm string `json:"algorithm,omitempty"`
+	Digits    int    `json:"digits,omitempty"`
+	Period    int    `json:"period,omitempty"`
+}
+
+// ListCommand prints stored services (never secrets) for scripting
+func ListCommand(args []string) int {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	format := fs.String("format", "table", "Output format: table, json or csv")
+	out := fs.String("out", "", "Write the listing to a file (created with 0600 permissions) instead of stdout")
+	limit := fs.Int("limit", 0, "Maximum number of services to list (0 = all)")
+	offset := fs.Int("offset", 0, "Number of services to skip before listing")
+	sortOrder := fs.String("sort", "", "Sort by name, created or used (most recent first); default is stored order")
+	showParams := fs.Bool("show-params", false, "Include algorithm, digits and period (defaults applied)")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if *format != "table" && *format != "json" && *format != "csv" {
+		fmt.Fprintf(os.Stderr, "Error: Unknown format %q (use table, json or csv)\n", *format)
+		return 1
+	}
+
+	var less storage.LessFunc
+	if *sortOrder != "" {
+		var err error
+		if less, err = storage.ParseSortOrder(*sortOrder); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	if *limit < 0 || *offset < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --limit and --offset must not be negative")
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	var buf bytes.Buffer
+	services := storage.SortedServices(app.store.Services, less)
+	services = paginate(services, *offset, *limit)
+	if err := writeList(&buf, services, *format, *showParams); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *out == "" {
+		fmt.Print(buf.String())
+		return 0
+	}
+
+	if err := writePrivateFile(*out, buf.Bytes()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "✓ Listing written to %s\n", *out)
+	return 0
+}
+
+// paginate returns the window of services starting at offset with at most
+// limit entries (0 = no limit). Out-of-range offsets yield an empty slice.
+func paginate(services []storage.Service, offset, limit int) []storage.Service {
+	if offset >= len(services) {
+		return []storage.Service{}
+	}
+	services = services[offset:]
+	if limit > 0 && limit < len(services) {
+		services = services[:limit]
+	}
+	return services
+}
+
+// writeList renders services in the given format, optionally with the
+// effective code parameters
+func writeList(w io.Writer, services []storage.Service, format string, showParams bool) error {
+	entries := make([]listEntry, len(services))
+	for i, service := range services {
+		entries[i] = listEntry{
+			Name:       service.Name,
+			Identifier: service.Identifier,
+			URL:        service.URL,
+			CreatedAt:  service.CreatedAt,
+			LastUsed:   service.LastUsed,
+		}
+		if showParams {
+			entries[i].Algorithm = service.EffectiveAlgorithm()
+			entries[i].Digits = service.CodeLength()
+			entries[i].Period = service.EffectivePeriod()
+		}
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	case "csv":
+		return writeListCSV(w, entries, showParams)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := "NAME\tIDENTIFIER\tCREATED\tLAST USED"
+	if showParams {
+		header += "\tALGORITHM\tDIGITS\tPERIOD"
+	}
+	fmt.Fprintln(tw, header)
+	for _, entry := range entries {
+		identifier := entry.Identifier
+		if identifier == "" {
+			identifier = "-"
+		}
+		lastUsed := "never"
+		if entry.LastUsed != nil {
+			lastUsed = entry.LastUsed.Format(listTimeFormat)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s", entry.Name, identifier, entry.CreatedAt.Format(listTimeFormat), lastUsed)
+		if showParams {
+			fmt.Fprintf(tw, "\t%s\t%d\t%ds", entry.Algorithm, entry.Digits, entry.Period)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// writeListCSV renders entries as CSV with RFC 3339 timestamps. Fields are
+// quoted as needed, so names containing commas or quotes stay one field.
+func writeListCSV(w io.Writer, entries []listEntry, showParams bool) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"name", "identifier", "created_at", "last_used"}
+	if showParams {
+		header = append(header, "algorithm", "digits", "period")
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		lastUsed := ""
+		if entry.LastUsed != nil {
+			lastUsed = entry.LastUsed.Format(time.RFC3339)
+		}
+		record := []string{entry.Name, entry.Identifier, entry.CreatedAt.Format(time.RFC3339), lastUsed}
+		if showParams {
+			record = append(record, entry.Algorithm, strconv.Itoa(entry.Digits), strconv.Itoa(entry.Period))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// listTimeFormat is the timestamp layout used in table output
+const listTimeFormat = "2006-01-02 15:04"
+
+// writePrivateFile writes data to path with owner-only (0600) permissions,
+// tightening the mode of an existing file as well
+func writePrivateFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to set output file permissions: %w", err)
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+	return nil
+}
+
+// writeFileExclusive writes data like writePrivateFile but refuses to
+// replace an existing file unless force is set, so exports never clobber
+// a file by accident. Forced writes go through replacePrivateFile, so a
+// failure keeps the existing file.
+func writeFileExclusive(path string, data []byte, force bool) error {
+	if force {
+		return replacePrivateFile(path, data)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists (use --force to overwrite it)", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync output file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+	return nil
+}
+
+// renameFile is os.Rename, replaceable in tests to simulate a failed replace
+var renameFile = os.Rename
+
+// replacePrivateFile writes data to a 0600 temp file beside path and renames
+// it over path, so the old file stays intact until the new one is complete
+func replacePrivateFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	tmp := f.Name()
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to sync output file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+
+	if err := renameFile(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
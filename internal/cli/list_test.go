@@ -0,0 +1,431 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// listTestServices returns sample services for list tests
+func listTestServices() []storage.Service {
+	created := time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)
+	return []storage.Service{
+		{Name: "GitHub", Identifier: "octocat", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: created},
+		{Name: "AWS", Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", CreatedAt: created},
+	}
+}
+
+// TestListCommand_Stdout tests the table listing never includes secrets
+func TestListCommand_Stdout(t *testing.T) {
+	setupTestStorage(t, "test-passphrase", listTestServices()...)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ListCommand([]string{})
+	})
+	if code != 0 {
+		t.Fatalf("ListCommand() = %d, want 0", code)
+	}
+
+	for _, want := range []string{"NAME", "GitHub", "octocat", "AWS", "2024-01-02 03:04", "never"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output should contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "JBSWY3DPEHPK3PXP") {
+		t.Error("Output must not contain secrets")
+	}
+}
+
+// TestListCommand_FormatsOmitSecrets tests no output format leaks secrets or
+// recovery codes, with or without --show-params
+func TestListCommand_FormatsOmitSecrets(t *testing.T) {
+	services := listTestServices()
+	services[0].Recovery = []string{"abcd-efgh"}
+	setupTestStorage(t, "test-passphrase", services...)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	for _, format := range []string{"table", "json", "csv"} {
+		for _, showParams := range []bool{false, true} {
+			args := []string{"--format", format}
+			if showParams {
+				args = append(args, "--show-params")
+			}
+
+			var code int
+			out := captureStdout(t, func() {
+				code = ListCommand(args)
+			})
+			if code != 0 {
+				t.Fatalf("ListCommand(%v) = %d, want 0", args, code)
+			}
+			if !strings.Contains(out, "GitHub") {
+				t.Errorf("ListCommand(%v) output should list GitHub, got:\n%s", args, out)
+			}
+			for _, secret := range []string{"JBSWY3DPEHPK3PXP", "GEZDGNBVGY3TQOJQ", "abcd-efgh"} {
+				if strings.Contains(out, secret) {
+					t.Errorf("ListCommand(%v) output contains %q", args, secret)
+				}
+			}
+		}
+	}
+}
+
+// TestListCommand_ShowParams tests effective parameters render, defaults included
+func TestListCommand_ShowParams(t *testing.T) {
+	services := listTestServices()
+	services[1].Algorithm = "SHA256"
+	services[1].Digits = 8
+	services[1].Period = 60
+	setupTestStorage(t, "test-passphrase", services...)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ListCommand([]string{"--show-params"})
+	})
+	if code != 0 {
+		t.Fatalf("ListCommand() = %d, want 0", code)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected header and 2 rows, got:\n%s", out)
+	}
+	if fields := strings.Fields(lines[0]); fields[len(fields)-3] != "ALGORITHM" || fields[len(fields)-1] != "PERIOD" {
+		t.Errorf("Header = %q, want parameter columns", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "SHA1       6       30s") {
+		t.Errorf("GitHub row = %q, want defaults SHA1/6/30s", lines[1])
+	}
+	if !strings.HasSuffix(lines[2], "SHA256     8       60s") {
+		t.Errorf("AWS row = %q, want SHA256/8/60s", lines[2])
+	}
+
+	// Without the flag the columns are omitted
+	out = captureStdout(t, func() {
+		code = ListCommand([]string{})
+	})
+	if strings.Contains(out, "ALGORITHM") {
+		t.Error("Parameter columns should only appear with --show-params")
+	}
+
+	// JSON includes the effective values too
+	out = captureStdout(t, func() {
+		code = ListCommand([]string{"--format", "json", "--show-params"})
+	})
+	var entries []listEntry
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if entries[0].Algorithm != "SHA1" || entries[0].Digits != 6 || entries[0].Period != 30 {
+		t.Errorf("JSON defaults = %s/%d/%d, want SHA1/6/30", entries[0].Algorithm, entries[0].Digits, entries[0].Period)
+	}
+}
+
+// TestListCommand_Sort tests --sort orders output without changing stored order
+func TestListCommand_Sort(t *testing.T) {
+	lastUsed := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	path := setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "Slack", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		storage.Service{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), LastUsed: &lastUsed},
+		storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"name", []string{"AWS", "GitHub", "Slack"}},
+		{"created", []string{"GitHub", "AWS", "Slack"}},
+		{"used", []string{"AWS", "Slack", "GitHub"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			var code int
+			out := captureStdout(t, func() {
+				code = ListCommand([]string{"--sort", tt.order})
+			})
+			if code != 0 {
+				t.Fatalf("ListCommand() = %d, want 0", code)
+			}
+
+			lines := strings.Split(strings.TrimSpace(out), "\n")[1:]
+			for i, name := range tt.want {
+				if !strings.HasPrefix(lines[i], name) {
+					t.Errorf("Row %d = %q, want %s", i, lines[i], name)
+				}
+			}
+		})
+	}
+
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if store.Services[0].Name != "Slack" {
+		t.Error("Sorting must not change the stored order")
+	}
+
+	if code := ListCommand([]string{"--sort", "size"}); code != 1 {
+		t.Errorf("ListCommand(--sort size) = %d, want 1", code)
+	}
+}
+
+// TestListCommand_OutFile tests --out writes a 0600 file with the listing
+func TestListCommand_OutFile(t *testing.T) {
+	setupTestStorage(t, "test-passphrase", listTestServices()...)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	outPath := filepath.Join(t.TempDir(), "services.txt")
+
+	var code int
+	stdout := captureStdout(t, func() {
+		code = ListCommand([]string{"--out", outPath})
+	})
+	if code != 0 {
+		t.Fatalf("ListCommand() = %d, want 0", code)
+	}
+	if stdout != "" {
+		t.Errorf("Nothing should be printed to stdout with --out, got %q", stdout)
+	}
+
+	info, err := os.Stat(outPath)
+	if err != nil {
+		t.Fatalf("Output file not created: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Output file permissions = %o, want 600", info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.Contains(string(data), "GitHub") || !strings.Contains(string(data), "AWS") {
+		t.Errorf("Output file missing services:\n%s", data)
+	}
+	if strings.Contains(string(data), "JBSWY3DPEHPK3PXP") {
+		t.Error("Output file must not contain secrets")
+	}
+}
+
+// TestListCommand_OutFileJSON tests --out combined with --format json
+func TestListCommand_OutFileJSON(t *testing.T) {
+	setupTestStorage(t, "test-passphrase", listTestServices()...)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	outPath := filepath.Join(t.TempDir(), "services.json")
+
+	// An existing, more permissive file is tightened to 0600
+	if err := os.WriteFile(outPath, []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if code := ListCommand([]string{"--format", "json", "--out", outPath}); code != 0 {
+		t.Fatalf("ListCommand() = %d, want 0", code)
+	}
+
+	info, err := os.Stat(outPath)
+	if err != nil {
+		t.Fatalf("Failed to stat output file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Output file permissions = %o, want 600", info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+
+	var entries []listEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, data)
+	}
+	if len(entries) != 2 || entries[0].Name != "GitHub" || entries[0].Identifier != "octocat" {
+		t.Errorf("Unexpected JSON entries: %+v", entries)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Error("JSON output must not contain secrets")
+	}
+}
+
+// TestListCommand_UnknownFormat tests an unknown format is rejected
+func TestListCommand_UnknownFormat(t *testing.T) {
+	if code := ListCommand([]string{"--format", "xml"}); code != 1 {
+		t.Errorf("ListCommand() = %d, want 1", code)
+	}
+}
+
+// TestWriteList_CSVQuoting tests names with commas and quotes stay one field
+func TestWriteList_CSVQuoting(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)
+	services := []storage.Service{
+		{Name: "Acme, Inc", Identifier: `bob "the admin"`, CreatedAt: created},
+		{Name: "GitHub", CreatedAt: created},
+	}
+
+	var buf strings.Builder
+	if err := writeList(&buf, services, "csv", false); err != nil {
+		t.Fatalf("writeList() error = %v", err)
+	}
+
+	want := "name,identifier,created_at,last_used\n" +
+		`"Acme, Inc","bob ""the admin""",2024-01-02T03:04:00Z,` + "\n" +
+		"GitHub,,2024-01-02T03:04:00Z,\n"
+	if buf.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("csv.ReadAll() error = %v", err)
+	}
+	if records[1][0] != "Acme, Inc" || records[1][1] != `bob "the admin"` {
+		t.Errorf("Parsed row = %q, want the original name and identifier", records[1])
+	}
+}
+
+// TestPaginate tests limit/offset windowing
+func TestPaginate(t *testing.T) {
+	services := make([]storage.Service, 5)
+	for i := range services {
+		services[i] = storage.Service{Name: fmt.Sprintf("S%d", i)}
+	}
+
+	tests := []struct {
+		name   string
+		offset int
+		limit  int
+		want   []string
+	}{
+		{"All", 0, 0, []string{"S0", "S1", "S2", "S3", "S4"}},
+		{"First page", 0, 2, []string{"S0", "S1"}},
+		{"Middle page", 2, 2, []string{"S2", "S3"}},
+		{"Last partial page", 4, 2, []string{"S4"}},
+		{"Offset only", 3, 0, []string{"S3", "S4"}},
+		{"Offset at end", 5, 2, []string{}},
+		{"Offset past end", 40, 20, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := paginate(services, tt.offset, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("paginate(%d, %d) returned %d services, want %d", tt.offset, tt.limit, len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].Name != tt.want[i] {
+					t.Errorf("paginate(%d, %d)[%d] = %s, want %s", tt.offset, tt.limit, i, got[i].Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestListCommand_LimitOffset tests --limit/--offset select the right slice
+func TestListCommand_LimitOffset(t *testing.T) {
+	setupTestStorage(t, "test-passphrase", listTestServices()...)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ListCommand([]string{"--format", "json", "--limit", "1", "--offset", "1"})
+	})
+	if code != 0 {
+		t.Fatalf("ListCommand() = %d, want 0", code)
+	}
+
+	var entries []listEntry
+	if err := json.Unmarshal([]byte(out), &entries); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "AWS" {
+		t.Errorf("Expected only AWS, got %+v", entries)
+	}
+
+	// Out-of-range offsets are empty, not an error
+	out = captureStdout(t, func() {
+		code = ListCommand([]string{"--format", "json", "--offset", "40"})
+	})
+	if code != 0 {
+		t.Fatalf("ListCommand() = %d with out-of-range offset, want 0", code)
+	}
+	if strings.TrimSpace(out) != "[]" {
+		t.Errorf("Expected empty JSON array, got %q", out)
+	}
+}
+
+// TestListCommand_NegativePagination tests negative values are rejected
+func TestListCommand_NegativePagination(t *testing.T) {
+	if code := ListCommand([]string{"--limit", "-1"}); code != 1 {
+		t.Errorf("ListCommand(--limit -1) = %d, want 1", code)
+	}
+	if code := ListCommand([]string{"--offset", "-1"}); code != 1 {
+		t.Errorf("ListCommand(--offset -1) = %d, want 1", code)
+	}
+}
+
+// TestWriteFileExclusive tests an existing file is kept without force and
+// replaced with it
+func TestWriteFileExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.txt")
+
+	if err := writeFileExclusive(path, []byte("first"), false); err != nil {
+		t.Fatalf("writeFileExclusive(new) error = %v", err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("Mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	err := writeFileExclusive(path, []byte("second"), false)
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("writeFileExclusive(existing) error = %v, want a --force hint", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "first" {
+		t.Errorf("File = %q, want it untouched", data)
+	}
+
+	if err := writeFileExclusive(path, []byte("second"), true); err != nil {
+		t.Fatalf("writeFileExclusive(force) error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "second" {
+		t.Errorf("File = %q, want it overwritten", data)
+	}
+}
+
+// TestWriteFileExclusive_ForceFailureKeepsFile tests a forced write that
+// cannot complete leaves the existing file and no temp file behind
+func TestWriteFileExclusive_ForceFailureKeepsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "backup.enc")
+	if err := os.WriteFile(path, []byte("old backup"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	oldRename := renameFile
+	renameFile = func(string, string) error { return errors.New("disk full") }
+	defer func() { renameFile = oldRename }()
+
+	if err := writeFileExclusive(path, []byte("new backup"), true); err == nil {
+		t.Fatal("writeFileExclusive(force) should report the failed replace")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "old backup" {
+		t.Errorf("File = %q, want the old backup kept", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Directory has %d entries, want only the backup", len(entries))
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanprakash21/totp-manager-go/internal/tui"
+)
+
+// runProgram runs the TUI until it exits (overridable in tests)
+var runProgram = func(model tea.Model) error {
+	_, err := tea.NewProgram(model, tea.WithAltScreen(), tea.WithReportFocus()).Run()
+	return err
+}
+
+// OpenCommand unlocks (or creates) the store and runs the TUI. It is the
+// default command when totp is run without a subcommand.
+func OpenCommand(args []string) int {
+	fs := flag.NewFlagSet("open", flag.ExitOnError)
+	allowRoot := fs.Bool("allow-root", false, "Run even as root (files created will be owned by root)")
+	nerdFonts := fs.Bool("nerd-fonts", false, "Show brand icons next to service names (needs a Nerd Fonts patched font)")
+	clipClear := fs.Int("clip-clear-seconds", 0, clipClearUsage)
+	noAtomic := fs.Bool("no-atomic", false, noAtomicUsage)
+	noLastUsedSave := fs.Bool("no-last-used-save", false, "Don't record last-used times on copy, so copying never rewrites the store")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if *clipClear < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --clip-clear-seconds must be 0 or more")
+		return 1
+	}
+
+	if err := CheckRoot(*allowRoot); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	app.noAtomic = *noAtomic
+
+	if err := app.Initialize(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	useClipboardSetting(app.store.Settings)
+
+	// Codes are generated by the model's Init when the program starts
+	model := tui.NewModel(app.store).
+		WithNerdFonts(*nerdFonts).
+		WithClipClear(*clipClear).
+		WithNoLastUsedSave(*noLastUsedSave)
+
+	if err := runProgram(model); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
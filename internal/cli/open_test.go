@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanprakash21/totp-manager-go/internal/tui"
+)
+
+// stubProgram replaces runProgram, recording the model it was given
+func stubProgram(t *testing.T, err error) *tea.Model {
+	t.Helper()
+	var got tea.Model
+	original := runProgram
+	runProgram = func(model tea.Model) error {
+		got = model
+		return err
+	}
+	t.Cleanup(func() { runProgram = original })
+	return &got
+}
+
+// TestOpenCommand_RunsTUI tests open unlocks the store and runs the TUI
+func TestOpenCommand_RunsTUI(t *testing.T) {
+	setupTestStorage(t, "correct-passphrase")
+	t.Setenv(passphraseEnvVar, "correct-passphrase")
+	got := stubProgram(t, nil)
+
+	if code := OpenCommand([]string{"--allow-root"}); code != 0 {
+		t.Fatalf("OpenCommand() = %d, want 0", code)
+	}
+	if _, ok := (*got).(tui.Model); !ok {
+		t.Errorf("runProgram got %T, want tui.Model", *got)
+	}
+}
+
+// TestOpenCommand_WrongPassphrase tests open exits 1 without running the TUI
+func TestOpenCommand_WrongPassphrase(t *testing.T) {
+	setupTestStorage(t, "correct-passphrase")
+	t.Setenv(passphraseEnvVar, "wrong-passphrase")
+	got := stubProgram(t, nil)
+
+	if code := OpenCommand([]string{"--allow-root"}); code != 1 {
+		t.Fatalf("OpenCommand() = %d, want 1", code)
+	}
+	if *got != nil {
+		t.Error("runProgram should not be called after a failed unlock")
+	}
+}
+
+// TestOpenCommand_ProgramError tests a TUI failure exits 1
+func TestOpenCommand_ProgramError(t *testing.T) {
+	setupTestStorage(t, "correct-passphrase")
+	t.Setenv(passphraseEnvVar, "correct-passphrase")
+	stubProgram(t, errors.New("no tty"))
+
+	if code := OpenCommand([]string{"--allow-root"}); code != 1 {
+		t.Errorf("OpenCommand() = %d, want 1", code)
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/qr"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// QRCommand shows a stored service's otpauth URI as a QR code, in the
+// terminal or as a PNG, for enrolling the same secret on a phone app
+func QRCommand(args []string) int {
+	fs := flag.NewFlagSet("qr", flag.ExitOnError)
+	name := fs.String("name", "", "Service name (required; may also be given as the first argument)")
+	pngPath := fs.String("png", "", "Write the QR code to this PNG file (created with 0600 permissions) instead of the terminal")
+	force := fs.Bool("force", false, "Overwrite an existing PNG file")
+	invert := fs.Bool("invert", false, "Draw dark modules in the text color, for terminals with a light background")
+
+	if err := parseWithName(fs, args, name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *name == "" {
+		fmt.Fprintln(os.Stderr, "Error: --name is required")
+		fmt.Fprintln(os.Stderr, "Usage: totp qr [--name] SERVICE_NAME [--png FILE] [--invert]")
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	service, err := app.store.GetServiceCopy(*name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Authenticator apps would read a Steam secret as a standard TOTP key
+	if service.Type == storage.TypeSteam {
+		fmt.Fprintf(os.Stderr, "Error: service '%s' is a Steam Guard service; otpauth QR codes cannot represent Steam codes\n", service.Name)
+		return 1
+	}
+
+	uri := keyFromService(&service).URI()
+
+	if *pngPath != "" {
+		data, err := qr.EncodePNG(uri, qr.DefaultSize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if err := writeFileExclusive(*pngPath, data, *force); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "✓ QR code for '%s' written to %s\n", service.Name, *pngPath)
+		return 0
+	}
+
+	code, err := qr.Terminal(uri, *invert)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	fmt.Print(code)
+	fmt.Fprintln(os.Stderr, "Warning: this QR code contains the secret; clear the screen after scanning")
+	return 0
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/qr"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// setupQRStorage creates storage holding a TOTP and a Steam service
+func setupQRStorage(t *testing.T) {
+	t.Helper()
+	setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Identifier: "alice", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		storage.Service{Name: "Steam", Secret: "JBSWY3DPEHPK3PXP", Type: storage.TypeSteam, CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+}
+
+// TestQRCommand_Terminal tests the QR code is printed as half blocks
+func TestQRCommand_Terminal(t *testing.T) {
+	setupQRStorage(t)
+
+	var code int
+	out := captureStdout(t, func() { code = QRCommand([]string{"github"}) })
+	if code != 0 {
+		t.Fatalf("QRCommand() = %d, want 0", code)
+	}
+
+	want, err := qr.Terminal("otpauth://totp/GitHub:alice?issuer=GitHub&secret=JBSWY3DPEHPK3PXP", false)
+	if err != nil {
+		t.Fatalf("Terminal() error = %v", err)
+	}
+	if out != want {
+		t.Errorf("Output does not match the service's QR code:\n%s", out)
+	}
+	if strings.Contains(out, "JBSWY3DPEHPK3PXP") {
+		t.Error("The secret must only appear inside the QR code")
+	}
+}
+
+// TestQRCommand_PNG tests --png writes a QR code that decodes to the URI
+func TestQRCommand_PNG(t *testing.T) {
+	setupQRStorage(t)
+	path := filepath.Join(t.TempDir(), "github.png")
+
+	if code := QRCommand([]string{"--name", "GitHub", "--png", path}); code != 0 {
+		t.Fatalf("QRCommand() = %d, want 0", code)
+	}
+
+	text, err := qr.Decode(path)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if text != "otpauth://totp/GitHub:alice?issuer=GitHub&secret=JBSWY3DPEHPK3PXP" {
+		t.Errorf("Decoded %q, want the GitHub URI", text)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("PNG mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Existing files are only replaced with --force
+	if code := QRCommand([]string{"--name", "GitHub", "--png", path}); code != 1 {
+		t.Errorf("QRCommand() over an existing file = %d, want 1", code)
+	}
+	if code := QRCommand([]string{"--name", "GitHub", "--png", path, "--force"}); code != 0 {
+		t.Errorf("QRCommand(--force) = %d, want 0", code)
+	}
+}
+
+// TestQRCommand_Invalid tests missing names, unknown and Steam services fail
+func TestQRCommand_Invalid(t *testing.T) {
+	setupQRStorage(t)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Missing name", []string{}},
+		{"Unknown service", []string{"Slack"}},
+		{"Steam service", []string{"Steam"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := QRCommand(tt.args); code != 1 {
+				t.Errorf("QRCommand(%v) = %d, want 1", tt.args, code)
+			}
+		})
+	}
+}
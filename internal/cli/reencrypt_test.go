@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestReencryptCommand tests repeated rewrites preserve every service and
+// setting while using a fresh salt each time
+func TestReencryptCommand(t *testing.T) {
+	lastUsed := time.Unix(1700000000, 0).UTC()
+	path := setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Identifier: "octocat", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: lastUsed,
+			LastUsed: &lastUsed, UseCount: 4, Recovery: []string{"abcd-efgh"}},
+		storage.Service{Name: "AWS", Secret: "GEZDGNBVGY3TQOJQ", CreatedAt: lastUsed, Algorithm: "SHA256", Digits: 8},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	before, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	before.Settings.Compress = true
+	before.Settings.MaxServices = 10
+	if err := before.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	salt := before.Salt
+	for run := 1; run <= 2; run++ {
+		var code int
+		out := captureStdout(t, func() {
+			code = ReencryptCommand(nil)
+		})
+		if code != 0 {
+			t.Fatalf("Run %d: ReencryptCommand() = %d, want 0", run, code)
+		}
+		if !strings.Contains(out, "✓ Storage re-encrypted (2 services, format version 2") {
+			t.Errorf("Run %d: unexpected output %q", run, out)
+		}
+
+		after, err := storage.Load(path, "test-passphrase")
+		if err != nil {
+			t.Fatalf("Run %d: Load() error = %v", run, err)
+		}
+		if !reflect.DeepEqual(after.Services, before.Services) {
+			t.Errorf("Run %d: services changed:\n got %+v\nwant %+v", run, after.Services, before.Services)
+		}
+		if after.Settings != before.Settings {
+			t.Errorf("Run %d: settings = %+v, want %+v", run, after.Settings, before.Settings)
+		}
+		if bytes.Equal(after.Salt, salt) {
+			t.Errorf("Run %d: salt should change on every rewrite", run)
+		}
+		salt = after.Salt
+	}
+}
+
+// TestReencryptCommand_NoStorage tests a missing store is an error, not created
+func TestReencryptCommand_NoStorage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	if code := ReencryptCommand(nil); code != 1 {
+		t.Errorf("ReencryptCommand() = %d, want 1", code)
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// RemoveCommand deletes a service after the user types its name to confirm
+func RemoveCommand(args []string) int {
+	fs := flag.NewFlagSet("remove", flag.ExitOnError)
+	name := fs.String("name", "", "Service name (required; may also be given as the first argument)")
+	force := fs.Bool("force", false, "Remove without asking for confirmation")
+	noAtomic := fs.Bool("no-atomic", false, noAtomicUsage)
+
+	if err := parseWithName(fs, args, name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *name == "" {
+		fmt.Fprintln(os.Stderr, "Error: --name is required")
+		fmt.Fprintln(os.Stderr, "Usage: totp remove [--force] [--name] SERVICE_NAME")
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	app.noAtomic = *noAtomic
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	service, err := app.store.GetServiceCopy(*name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if !*force {
+		fmt.Printf("This permanently deletes service '%s' and its secret.\n", service.Name)
+		fmt.Print("Type the service name to confirm: ")
+		answer, err := stdinReader().ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			fmt.Fprintf(os.Stderr, "Error: failed to read confirmation: %v\n", err)
+			return 1
+		}
+		fmt.Println()
+		if !storage.SameName(strings.TrimSpace(answer), service.Name) {
+			fmt.Fprintln(os.Stderr, "Error: confirmation did not match; service not removed")
+			return 1
+		}
+	}
+
+	if _, err := app.store.RemoveService(service.Name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.store.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving storage: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✓ Service '%s' removed\n", service.Name)
+	return 0
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// removeTestServices returns sample services for remove tests
+func removeTestServices() []storage.Service {
+	return []storage.Service{
+		{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+	}
+}
+
+// storedNames loads the store at path and returns its service names
+func storedNames(t *testing.T, path string) []string {
+	t.Helper()
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var names []string
+	for _, service := range store.Services {
+		names = append(names, service.Name)
+	}
+	return names
+}
+
+// TestRemoveCommand_Confirm tests removal requires typing the service name
+func TestRemoveCommand_Confirm(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase", removeTestServices()...)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	// Wrong confirmation keeps the service
+	withStdin(t, "yes\n")
+	var code int
+	captureStdout(t, func() {
+		code = RemoveCommand([]string{"--name", "GitHub"})
+	})
+	if code != 1 {
+		t.Errorf("RemoveCommand() with wrong confirmation = %d, want 1", code)
+	}
+	if names := storedNames(t, path); len(names) != 2 {
+		t.Fatalf("Services = %v, want both kept", names)
+	}
+
+	// Names match case-insensitively, for lookup and confirmation alike
+	withStdin(t, "GITHUB\n")
+	captureStdout(t, func() {
+		code = RemoveCommand([]string{"github"})
+	})
+	if code != 0 {
+		t.Fatalf("RemoveCommand() = %d, want 0", code)
+	}
+	if names := storedNames(t, path); len(names) != 1 || names[0] != "AWS" {
+		t.Errorf("Services = %v, want only AWS", names)
+	}
+}
+
+// TestRemoveCommand_Force tests --force skips the prompt
+func TestRemoveCommand_Force(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase", removeTestServices()...)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	captureStdout(t, func() {
+		code = RemoveCommand([]string{"--force", "--name", "AWS"})
+	})
+	if code != 0 {
+		t.Fatalf("RemoveCommand() = %d, want 0", code)
+	}
+	if names := storedNames(t, path); len(names) != 1 || names[0] != "GitHub" {
+		t.Errorf("Services = %v, want only GitHub", names)
+	}
+}
+
+// TestRemoveCommand_InvalidArgs tests missing names and unknown services fail
+func TestRemoveCommand_InvalidArgs(t *testing.T) {
+	setupTestStorage(t, "test-passphrase", removeTestServices()...)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Missing name", []string{"--force"}},
+		{"Unknown service", []string{"--force", "Slack"}},
+		{"Two names", []string{"--force", "GitHub", "AWS"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := RemoveCommand(tt.args); code != 1 {
+				t.Errorf("RemoveCommand(%v) = %d, want 1", tt.args, code)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// ResetStatsCommand zeroes usage counts (and optionally last-used times)
+// for one service or all of them
+func ResetStatsCommand(args []string) int {
+	fs := flag.NewFlagSet("reset-stats", flag.ExitOnError)
+	name := fs.String("name", "", "Reset only this service (default: all)")
+	clearLastUsed := fs.Bool("clear-last-used", false, "Also forget when services were last used")
+	noAtomic := fs.Bool("no-atomic", false, noAtomicUsage)
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	app.noAtomic = *noAtomic
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	reset, err := app.store.ResetStats(*name, *clearLastUsed)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.store.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving storage: %v\n", err)
+		return 1
+	}
+
+	what := "usage counts"
+	if *clearLastUsed {
+		what = "usage counts and last-used times"
+	}
+	if *name != "" {
+		fmt.Printf("✓ Reset %s for '%s'\n", what, *name)
+	} else {
+		fmt.Printf("✓ Reset %s for %d services\n", what, reset)
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestResetStatsCommand tests counts are zeroed for one service or all and saved
+func TestResetStatsCommand(t *testing.T) {
+	used := time.Now()
+	newServices := func() []storage.Service {
+		return []storage.Service{
+			{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(), UseCount: 4, LastUsed: &used},
+			{Name: "AWS", Secret: "GEZDGNBVGY3TQOJQ", CreatedAt: time.Now(), UseCount: 2, LastUsed: &used},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		wantCounts  []int
+		wantCleared bool
+		wantOutput  string
+	}{
+		{"one service", []string{"--name", "github"}, []int{0, 2}, false, "for 'github'"},
+		{"all services", nil, []int{0, 0}, false, "for 2 services"},
+		{"clear last used", []string{"--clear-last-used"}, []int{0, 0}, true, "last-used times"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := setupTestStorage(t, "test-passphrase", newServices()...)
+			t.Setenv(passphraseEnvVar, "test-passphrase")
+
+			var code int
+			out := captureStdout(t, func() { code = ResetStatsCommand(tt.args) })
+			if code != 0 {
+				t.Fatalf("ResetStatsCommand() = %d, want 0", code)
+			}
+			if !strings.Contains(out, tt.wantOutput) {
+				t.Errorf("Output = %q, want %q", out, tt.wantOutput)
+			}
+
+			store, err := storage.Load(path, "test-passphrase")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			for i, want := range tt.wantCounts {
+				service := store.Services[i]
+				if service.UseCount != want {
+					t.Errorf("%s UseCount = %d, want %d", service.Name, service.UseCount, want)
+				}
+				if cleared := service.LastUsed == nil; cleared != tt.wantCleared {
+					t.Errorf("%s LastUsed cleared = %v, want %v", service.Name, cleared, tt.wantCleared)
+				}
+			}
+		})
+	}
+}
+
+// TestResetStatsCommand_UnknownService tests a missing service fails
+func TestResetStatsCommand_UnknownService(t *testing.T) {
+	setupTestStorage(t, "test-passphrase")
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	if code := ResetStatsCommand([]string{"--name", "Missing"}); code != 1 {
+		t.Errorf("ResetStatsCommand() = %d, want 1", code)
+	}
+}
@@ -2,7 +2,9 @@ package cli
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"syscall"
@@ -13,12 +15,44 @@ import (
 
 const maxPassphraseAttempts = 3
 
+// minPassphraseLength is the shortest passphrase accepted for a new store
+const minPassphraseLength = 8
+
+// errPassphraseWhitespace rejects new passphrases with leading or trailing
+// whitespace: typed passphrases are trimmed but piped ones are not, so such
+// a passphrase could only ever be entered one way
+var errPassphraseWhitespace = errors.New("passphrase cannot start or end with whitespace")
+
+// validateNewPassphrase checks a passphrase for a new store or backup
+func validateNewPassphrase(passphrase string) error {
+	if len(passphrase) < minPassphraseLength {
+		return fmt.Errorf("passphrase must be at least %d characters", minPassphraseLength)
+	}
+	if strings.TrimSpace(passphrase) != passphrase {
+		return errPassphraseWhitespace
+	}
+	return nil
+}
+
+// passphraseEnvVar allows non-interactive unlock (e.g., cron, scripts)
+const passphraseEnvVar = "TOTP_PASSPHRASE"
+
 // App represents the CLI application
 type App struct {
 	store       *storage.Store
 	storagePath string
+
+	// noAtomic saves in place instead of via temp file + rename (--no-atomic)
+	noAtomic bool
 }
 
+// noAtomicUsage is the help text for the --no-atomic flag shared by commands that save
+const noAtomicUsage = "Save in place (after a backup) instead of an atomic rename; less safe, for filesystems where rename fails"
+
+// clipClearUsage is the help text for the --clip-clear-seconds flag shared by
+// every command that copies a code (the TUI takes the same flag)
+const clipClearUsage = "Clear the clipboard this many seconds after copying, if it still holds the code (0 = never)"
+
 // NewApp creates a new CLI application instance
 func NewApp() (*App, error) {
 	path, err := storage.GetDefaultStoragePath()
@@ -43,6 +77,15 @@ func (a *App) Initialize() error {
 	return a.loadExistingStorage()
 }
 
+// InitializeExisting loads existing storage without offering to create one.
+// Used by read-only commands where an empty new store makes no sense.
+func (a *App) InitializeExisting() error {
+	if _, err := os.Stat(a.storagePath); os.IsNotExist(err) {
+		return fmt.Errorf("storage not found: %s (add a service first)", a.storagePath)
+	}
+	return a.loadExistingStorage()
+}
+
 // createNewStorage creates a new encrypted storage with passphrase confirmation
 // (T026: Passphrase prompt with confirmation)
 func (a *App) createNewStorage() error {
@@ -61,6 +104,7 @@ func (a *App) createNewStorage() error {
 	if err != nil {
 		return fmt.Errorf("failed to create storage: %w", err)
 	}
+	store.SetNoAtomic(a.noAtomic)
 
 	// Save storage to disk (creates file with 0600 permissions - T031)
 	if err := store.Save(); err != nil {
@@ -81,6 +125,17 @@ func (a *App) createNewStorage() error {
 // loadExistingStorage loads existing storage with 3-attempt limit
 // (T028: Passphrase validation with 3-attempt limit)
 func (a *App) loadExistingStorage() error {
+	// Non-interactive unlock: a single attempt with the env passphrase
+	if passphrase, ok := os.LookupEnv(passphraseEnvVar); ok {
+		store, err := storage.Load(a.storagePath, passphrase)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+		store.SetNoAtomic(a.noAtomic)
+		a.store = store
+		return nil
+	}
+
 	var lastErr error
 
 	// Allow up to 3 attempts
@@ -93,10 +148,17 @@ func (a *App) loadExistingStorage() error {
 		// Try to load storage
 		store, err := storage.Load(a.storagePath, passphrase)
 		if err == nil {
+			store.SetNoAtomic(a.noAtomic)
 			a.store = store
 			return nil
 		}
 
+		// Retrying cannot help with a file from a newer release or a
+		// damaged one
+		if errors.Is(err, storage.ErrNewerVersion) || errors.Is(err, storage.ErrCorrupted) {
+			return err
+		}
+
 		lastErr = err
 
 		// T029: Error handling with clear messages
@@ -126,8 +188,8 @@ func (a *App) promptNewPassphrase() (string, error) {
 	fmt.Println()
 
 	// Validate passphrase strength
-	if len(passphrase1) < 8 {
-		return "", fmt.Errorf("passphrase must be at least 8 characters")
+	if err := validateNewPassphrase(passphrase1); err != nil {
+		return "", err
 	}
 
 	fmt.Print("Confirm passphrase: ")
@@ -162,7 +224,8 @@ func (a *App) promptPassphrase(attempt int) (string, error) {
 
 // readPassword reads a password from stdin without echoing
 func readPassword() (string, error) {
-	// Try to read from terminal (supports masking)
+	// Try to read from terminal (supports masking). Typed passphrases keep
+	// being trimmed: existing stores were encrypted with the trimmed form.
 	if term.IsTerminal(int(syscall.Stdin)) {
 		bytePassword, err := term.ReadPassword(int(syscall.Stdin))
 		if err != nil {
@@ -171,13 +234,35 @@ func readPassword() (string, error) {
 		return strings.TrimSpace(string(bytePassword)), nil
 	}
 
-	// Fallback for non-terminal input (e.g., tests)
-	reader := bufio.NewReader(os.Stdin)
-	password, err := reader.ReadString('\n')
-	if err != nil {
+	// Fallback for non-terminal input (e.g., tests, pipes); a final line
+	// without a newline still counts
+	password, err := stdinReader().ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && password != "") {
 		return "", err
 	}
-	return strings.TrimSpace(password), nil
+	return trimLineEnding(password), nil
+}
+
+// trimLineEnding removes a single trailing "\n" or "\r\n" (input piped from
+// Windows), keeping leading and trailing spaces that belong to the value
+func trimLineEnding(line string) string {
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r")
+}
+
+// Buffered stdin shared across prompts so piped input isn't lost between reads
+var (
+	stdinBuffer *bufio.Reader
+	stdinSource *os.File
+)
+
+// stdinReader returns the shared buffered reader for the current os.Stdin
+func stdinReader() *bufio.Reader {
+	if stdinBuffer == nil || stdinSource != os.Stdin {
+		stdinBuffer = bufio.NewReader(os.Stdin)
+		stdinSource = os.Stdin
+	}
+	return stdinBuffer
 }
 
 // GetStore returns the initialized storage store
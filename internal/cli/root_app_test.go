@@ -1,9 +1,14 @@
 package cli
 
 import (
+	"encoding/binary"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
 )
 
 // TestNewApp tests creating a new CLI app
@@ -76,3 +81,61 @@ func TestApp_CreateWithTestStorage(t *testing.T) {
 		t.Error("Storage path should be absolute")
 	}
 }
+
+// TestApp_LoadNewerVersion tests a file from a newer release fails on the
+// first attempt with ErrNewerVersion instead of re-prompting
+func TestApp_LoadNewerVersion(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	binary.LittleEndian.PutUint32(data[0:4], 99)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// One passphrase line: a retry would hit EOF instead
+	withStdin(t, "test-passphrase\n")
+
+	app, err := NewApp()
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	var loadErr error
+	out := captureStdout(t, func() { loadErr = app.InitializeExisting() })
+	if !errors.Is(loadErr, storage.ErrNewerVersion) {
+		t.Fatalf("InitializeExisting() error = %v, want ErrNewerVersion", loadErr)
+	}
+	if strings.Contains(out, "Incorrect passphrase") {
+		t.Errorf("Output should not blame the passphrase: %q", out)
+	}
+}
+
+// TestApp_LoadCorrupted tests a truncated file fails on the first attempt
+// with ErrCorrupted instead of re-prompting
+func TestApp_LoadCorrupted(t *testing.T) {
+	path := setupTestStorage(t, "test-passphrase")
+	if err := os.WriteFile(path, []byte{1, 0, 0, 0, 42}, 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// One passphrase line: a retry would hit EOF instead
+	withStdin(t, "test-passphrase\n")
+
+	app, err := NewApp()
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	var loadErr error
+	out := captureStdout(t, func() { loadErr = app.InitializeExisting() })
+	if !errors.Is(loadErr, storage.ErrCorrupted) {
+		t.Fatalf("InitializeExisting() error = %v, want ErrCorrupted", loadErr)
+	}
+	if strings.Contains(out, "Incorrect passphrase") {
+		t.Errorf("Output should not blame the passphrase: %q", out)
+	}
+}
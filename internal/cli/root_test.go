@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -93,3 +94,97 @@ func TestPassphraseValidation(t *testing.T) {
 		})
 	}
 }
+
+// TestReadPassword_Piped tests piped passphrases lose only their line ending,
+// keeping meaningful spaces, including CRLF input from Windows
+func TestReadPassword_Piped(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"LF", "secret pass\n", "secret pass"},
+		{"CRLF with trailing spaces", "  spaced out  \r\n", "  spaced out  "},
+		{"no final newline", "last line", "last line"},
+		{"only the first line", "first\r\nsecond\r\n", "first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, tt.input)
+
+			got, err := readPassword()
+			if err != nil {
+				t.Fatalf("readPassword() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("readPassword() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestApp_NewPassphraseRoundTrip tests a new passphrase with edge whitespace
+// is refused, and an accepted one unlocks the store it created
+func TestApp_NewPassphraseRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("TOTP_STORAGE_DIR", tmpDir)
+
+	app, err := NewApp()
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	for _, input := range []string{"correct horse \ncorrect horse \n", " correct horse\n correct horse\n"} {
+		withStdin(t, input)
+		var initErr error
+		captureStdout(t, func() { initErr = app.Initialize() })
+		if !errors.Is(initErr, errPassphraseWhitespace) {
+			t.Errorf("Initialize(%q) error = %v, want errPassphraseWhitespace", input, initErr)
+		}
+	}
+
+	withStdin(t, "correct horse\ncorrect horse\n")
+	var initErr error
+	captureStdout(t, func() { initErr = app.Initialize() })
+	if initErr != nil {
+		t.Fatalf("Initialize() error = %v", initErr)
+	}
+
+	unlock, err := NewApp()
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+	withStdin(t, "correct horse\n")
+	captureStdout(t, func() { initErr = unlock.InitializeExisting() })
+	if initErr != nil {
+		t.Errorf("InitializeExisting() error = %v", initErr)
+	}
+}
+
+// TestReadPassword_EmptyInput tests end of input is an error
+func TestReadPassword_EmptyInput(t *testing.T) {
+	withStdin(t, "")
+
+	if _, err := readPassword(); err == nil {
+		t.Error("readPassword() should fail on empty input")
+	}
+}
+
+// TestApp_UnlockWithSpacedPassphrase tests a passphrase with trailing spaces
+// piped with CRLF unlocks the store
+func TestApp_UnlockWithSpacedPassphrase(t *testing.T) {
+	setupTestStorage(t, "correct horse  ")
+	withStdin(t, "correct horse  \r\n")
+
+	app, err := NewApp()
+	if err != nil {
+		t.Fatalf("NewApp() error = %v", err)
+	}
+
+	var loadErr error
+	captureStdout(t, func() { loadErr = app.InitializeExisting() })
+	if loadErr != nil {
+		t.Fatalf("InitializeExisting() error = %v", loadErr)
+	}
+}
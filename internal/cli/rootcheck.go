@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+)
+
+// rootDecision is the outcome of the startup root check
+type rootDecision int
+
+const (
+	rootOK     rootDecision = iota // not running as root
+	rootWarn                       // root, allowed with --allow-root
+	rootRefuse                     // root without --allow-root
+)
+
+// decideRoot decides whether the TUI may start for the given effective UID.
+// A negative UID means the platform has no UIDs.
+func decideRoot(uid int, allowRoot bool) rootDecision {
+	if uid != 0 {
+		return rootOK
+	}
+	if allowRoot {
+		return rootWarn
+	}
+	return rootRefuse
+}
+
+// CheckRoot refuses to start the TUI as root unless allowRoot (--allow-root)
+// is set, since a store created by root ends up with the wrong ownership
+func CheckRoot(allowRoot bool) error {
+	switch decideRoot(effectiveUID(), allowRoot) {
+	case rootRefuse:
+		return fmt.Errorf("refusing to run as root: the storage file would be owned by root; " +
+			"run as your normal user or pass --allow-root")
+	case rootWarn:
+		fmt.Fprintln(os.Stderr, "Warning: running as root; files created now will be owned by root")
+	}
+	return nil
+}
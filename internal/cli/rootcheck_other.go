@@ -0,0 +1,6 @@
+//go:build !unix
+
+package cli
+
+// effectiveUID reports no UID on platforms without Unix users
+var effectiveUID = func() int { return -1 }
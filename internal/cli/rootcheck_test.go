@@ -0,0 +1,46 @@
+package cli
+
+import "testing"
+
+// TestDecideRoot tests the decision for root and non-root UIDs
+func TestDecideRoot(t *testing.T) {
+	tests := []struct {
+		name      string
+		uid       int
+		allowRoot bool
+		want      rootDecision
+	}{
+		{"Regular user", 1000, false, rootOK},
+		{"Regular user with flag", 1000, true, rootOK},
+		{"No UIDs on platform", -1, false, rootOK},
+		{"Root refused", 0, false, rootRefuse},
+		{"Root allowed", 0, true, rootWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decideRoot(tt.uid, tt.allowRoot); got != tt.want {
+				t.Errorf("decideRoot(%d, %v) = %v, want %v", tt.uid, tt.allowRoot, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCheckRoot tests the injected UID source drives the check
+func TestCheckRoot(t *testing.T) {
+	oldUID := effectiveUID
+	defer func() { effectiveUID = oldUID }()
+
+	effectiveUID = func() int { return 0 }
+	if err := CheckRoot(false); err == nil {
+		t.Error("CheckRoot() as root should refuse without --allow-root")
+	}
+	if err := CheckRoot(true); err != nil {
+		t.Errorf("CheckRoot() as root with --allow-root error = %v", err)
+	}
+
+	effectiveUID = func() int { return 1000 }
+	if err := CheckRoot(false); err != nil {
+		t.Errorf("CheckRoot() as regular user error = %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build unix
+
+package cli
+
+import "os"
+
+// effectiveUID returns the process's effective UID (overridable in tests)
+var effectiveUID = os.Geteuid
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/server"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// serveTokenEnvVar supplies a fixed API token instead of a generated one
+const serveTokenEnvVar = "TOTP_SERVE_TOKEN"
+
+// ServeCommand runs the opt-in local HTTP API on a loopback address until
+// interrupted. The store is not unlocked at startup: clients open a session
+// with the passphrase and it re-locks after --session-ttl of inactivity.
+func ServeCommand(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:0", "Loopback address to listen on (port 0 picks a free port)")
+	sessionTTL := fs.Duration("session-ttl", server.DefaultSessionTTL, "Idle time before an unlocked session re-locks")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if *sessionTTL <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: --session-ttl must be positive")
+		return 1
+	}
+
+	// Refuse non-loopback binds before touching storage
+	if err := server.ValidateLoopback(*addr); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	token := os.Getenv(serveTokenEnvVar)
+	if token == "" {
+		generated, err := server.GenerateToken()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		token = generated
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(app.storagePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: storage not found: %s (add a service first)\n", app.storagePath)
+		return 1
+	}
+
+	unlock := func(passphrase string) (*storage.Store, error) {
+		return storage.Load(app.storagePath, passphrase)
+	}
+	sessions := server.NewSessionManager(*sessionTTL)
+
+	listener, err := server.Listen(*addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	httpServer := &http.Server{
+		Handler:           server.New(unlock, token, sessions).Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	fmt.Printf("✓ Serving on http://%s (Ctrl+C to stop)\n", listener.Addr())
+	fmt.Printf("Token: %s\n", token)
+	fmt.Printf("Sessions re-lock after %s idle\n", *sessionTTL)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		// Drop idle sessions even when no request arrives to notice them
+		ticker := time.NewTicker(*sessionTTL / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				sessions.Sweep()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("✓ Server stopped")
+	return 0
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestServeCommand_RefusesNonLoopback tests wildcard and LAN addresses are
+// rejected before the store is unlocked
+func TestServeCommand_RefusesNonLoopback(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	for _, addr := range []string{":8080", "0.0.0.0:0", "192.168.1.10:8080", "no-port"} {
+		if code := ServeCommand([]string{"--addr", addr}); code != 1 {
+			t.Errorf("ServeCommand(--addr %s) = %d, want 1", addr, code)
+		}
+	}
+
+	if matches, _ := filepath.Glob(filepath.Join(home, ".config", "totp-manager", "*")); len(matches) != 0 {
+		t.Errorf("Refused serve should not touch storage, found %v", matches)
+	}
+}
+
+// TestServeCommand_NoStorage tests serving requires an existing store
+func TestServeCommand_NoStorage(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	if code := ServeCommand([]string{"--addr", "127.0.0.1:0"}); code != 1 {
+		t.Errorf("ServeCommand() = %d, want 1", code)
+	}
+}
+
+// TestServeCommand_InvalidSessionTTL tests a non-positive TTL is refused
+func TestServeCommand_InvalidSessionTTL(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for _, ttl := range []string{"0s", "-1m"} {
+		if code := ServeCommand([]string{"--addr", "127.0.0.1:0", "--session-ttl", ttl}); code != 1 {
+			t.Errorf("ServeCommand(--session-ttl %s) = %d, want 1", ttl, code)
+		}
+	}
+}
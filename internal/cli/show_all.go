@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+const (
+	// maxShowAllDuration bounds how long show-all keeps codes on screen
+	maxShowAllDuration = 5 * time.Minute
+
+	// clearScreen moves the cursor home and clears the terminal and its
+	// scrollback
+	clearScreen = "\033[H\033[2J\033[3J"
+)
+
+// sleep pauses between show-all refreshes (overridable in tests)
+var sleep = time.Sleep
+
+// ShowAllCommand prints every current code, refreshing each second, for a
+// bounded duration and then clears the screen, also when interrupted with
+// Ctrl+C. For quick audits on a trusted machine; requires
+// --i-understand-the-risk.
+func ShowAllCommand(args []string) int {
+	fs := flag.NewFlagSet("show-all", flag.ExitOnError)
+	accepted := fs.Bool("i-understand-the-risk", false, "Confirm that every code will be visible on screen")
+	duration := fs.Duration("for", 10*time.Second, "How long to show codes (max 5m)")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if !*accepted {
+		fmt.Fprintln(os.Stderr, "Error: show-all displays every code on screen; pass --i-understand-the-risk to continue")
+		return 1
+	}
+
+	if *duration < time.Second || *duration > maxShowAllDuration {
+		fmt.Fprintf(os.Stderr, "Error: --for must be between 1s and %s\n", maxShowAllDuration)
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	showAll(os.Stdout, app.store.Services, now().Add(*duration), app.store.Settings.CodeErrorPlaceholder(), ctx.Done())
+	return 0
+}
+
+// showAll redraws all codes once per second until the deadline or an
+// interrupt, then clears the screen so no code is left in view. Codes that
+// fail to generate show the placeholder.
+func showAll(w io.Writer, services []storage.Service, deadline time.Time, placeholder string, interrupted <-chan struct{}) {
+	for t := now(); t.Before(deadline); t = now() {
+		select {
+		case <-interrupted:
+			fmt.Fprint(w, clearScreen)
+			fmt.Fprintln(w, "✓ Codes hidden")
+			return
+		default:
+		}
+
+		fmt.Fprint(w, clearScreen)
+		fmt.Fprintf(w, "Showing all codes (hidden in %ds)\n\n", int(deadline.Sub(t).Round(time.Second)/time.Second))
+		writeCodes(w, services, t, placeholder)
+		pause(time.Second, interrupted)
+	}
+
+	fmt.Fprint(w, clearScreen)
+	fmt.Fprintln(w, "✓ Codes hidden")
+}
+
+// pause sleeps for d, returning early when interrupted
+func pause(d time.Duration, interrupted <-chan struct{}) {
+	done := make(chan struct{})
+	go func() {
+		sleep(d)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-interrupted:
+	}
+}
+
+// writeCodes renders each service's code and seconds remaining at t
+func writeCodes(w io.Writer, services []storage.Service, t time.Time, placeholder string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tCODE\tEXPIRES IN")
+	for i := range services {
+		service := &services[i]
+		code, err := service.Code(t)
+		if err != nil {
+			code = placeholder
+		}
+		period := int64(service.EffectivePeriod())
+		fmt.Fprintf(tw, "%s\t%s\t%ds\n", service.Name, code, period-t.Unix()%period)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// fakeClock makes now() advance only when sleep is called
+func fakeClock(t *testing.T, start time.Time) *int {
+	t.Helper()
+
+	current := start
+	sleeps := 0
+	oldNow, oldSleep := now, sleep
+	now = func() time.Time { return current }
+	sleep = func(d time.Duration) {
+		sleeps++
+		current = current.Add(d)
+	}
+	t.Cleanup(func() { now, sleep = oldNow, oldSleep })
+
+	return &sleeps
+}
+
+// TestShowAll tests codes are emitted and the loop stops at the deadline
+func TestShowAll(t *testing.T) {
+	start := time.Unix(59, 0)
+	sleeps := fakeClock(t, start)
+	services := []storage.Service{
+		{Name: "RFC", Secret: rfc6238Secret, CreatedAt: start},
+		{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: start, Period: 60},
+	}
+
+	var buf bytes.Buffer
+	showAll(&buf, services, start.Add(3*time.Second), storage.DefaultErrorPlaceholder, nil)
+	out := buf.String()
+
+	if *sleeps != 3 {
+		t.Errorf("Refreshed %d times, want 3 before the deadline", *sleeps)
+	}
+	if !strings.Contains(out, "287082") {
+		t.Errorf("Expected the RFC 6238 code for T=59, got:\n%s", out)
+	}
+	if !strings.Contains(out, "hidden in 3s") || !strings.Contains(out, "hidden in 1s") {
+		t.Errorf("Expected a countdown to the deadline, got:\n%s", out)
+	}
+	if !strings.HasSuffix(out, clearScreen+"✓ Codes hidden\n") {
+		t.Errorf("Output should end by clearing the screen, got:\n%q", out[len(out)-40:])
+	}
+}
+
+// TestShowAll_Interrupted tests an interrupt stops the loop and still
+// clears the screen
+func TestShowAll_Interrupted(t *testing.T) {
+	start := time.Unix(59, 0)
+	fakeClock(t, start)
+	services := []storage.Service{{Name: "RFC", Secret: rfc6238Secret, CreatedAt: start}}
+
+	interrupted := make(chan struct{})
+	close(interrupted)
+
+	var buf bytes.Buffer
+	showAll(&buf, services, start.Add(time.Minute), storage.DefaultErrorPlaceholder, interrupted)
+	out := buf.String()
+
+	if strings.Contains(out, "287082") {
+		t.Errorf("No code should be shown after an interrupt, got:\n%s", out)
+	}
+	if out != clearScreen+"✓ Codes hidden\n" {
+		t.Errorf("Output = %q, want only the screen clear", out)
+	}
+
+	// Interrupted while waiting for the next refresh (fakeClock restores sleep)
+	interrupted = make(chan struct{})
+	sleep = func(time.Duration) { close(interrupted) }
+	buf.Reset()
+	showAll(&buf, services, start.Add(time.Minute), storage.DefaultErrorPlaceholder, interrupted)
+	out = buf.String()
+
+	if strings.Count(out, "287082") != 1 || !strings.HasSuffix(out, clearScreen+"✓ Codes hidden\n") {
+		t.Errorf("Expected one refresh and then the screen clear, got:\n%q", out)
+	}
+}
+
+// TestShowAllCommand tests the risk flag and duration bounds
+func TestShowAllCommand(t *testing.T) {
+	setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	fakeClock(t, time.Unix(1700000000, 0))
+
+	for _, args := range [][]string{
+		{"--for", "10s"},
+		{"--i-understand-the-risk", "--for", "0s"},
+		{"--i-understand-the-risk", "--for", "500ms"},
+		{"--i-understand-the-risk", "--for", "10m"},
+	} {
+		if code := ShowAllCommand(args); code != 1 {
+			t.Errorf("ShowAllCommand(%v) = %d, want 1", args, code)
+		}
+	}
+
+	var code int
+	out := captureStdout(t, func() {
+		code = ShowAllCommand([]string{"--i-understand-the-risk", "--for", "2s"})
+	})
+	if code != 0 {
+		t.Fatalf("ShowAllCommand() = %d, want 0", code)
+	}
+	if !strings.Contains(out, "GitHub") || !strings.Contains(out, "Codes hidden") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TopCommand lists the most frequently used services by copy count
+func TopCommand(args []string) int {
+	fs := flag.NewFlagSet("top", flag.ExitOnError)
+	limit := fs.Int("limit", 10, "Number of services to show")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if *limit < 1 {
+		fmt.Fprintln(os.Stderr, "Error: --limit must be at least 1")
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	services := topServices(app.store.Services, *limit)
+	if len(services) == 0 {
+		fmt.Println("No codes copied yet")
+		return 0
+	}
+
+	if err := writeTop(os.Stdout, services); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// topServices returns up to limit used services ordered by descending
+// UseCount; ties go to the most recently used, then by name
+func topServices(services []storage.Service, limit int) []storage.Service {
+	used := make([]storage.Service, 0, len(services))
+	for _, service := range services {
+		if service.UseCount > 0 {
+			used = append(used, service)
+		}
+	}
+
+	sort.SliceStable(used, func(i, j int) bool {
+		a, b := used[i], used[j]
+		if a.UseCount != b.UseCount {
+			return a.UseCount > b.UseCount
+		}
+		if newer, older := storage.ByLastUsed(a, b), storage.ByLastUsed(b, a); newer != older {
+			return newer
+		}
+		return storage.ByName(a, b)
+	})
+
+	if len(used) > limit {
+		used = used[:limit]
+	}
+	return used
+}
+
+// writeTop renders the ranked services as a table
+func writeTop(w io.Writer, services []storage.Service) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RANK\tNAME\tUSES\tLAST USED")
+	for i, service := range services {
+		lastUsed := "never"
+		if service.LastUsed != nil {
+			lastUsed = service.LastUsed.Format(listTimeFormat)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%s\n", i+1, service.Name, service.UseCount, lastUsed)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestTopServices tests ordering by descending use count with tie-breaks
+func TestTopServices(t *testing.T) {
+	older := time.Unix(1700000000, 0)
+	newer := time.Unix(1700001000, 0)
+	services := []storage.Service{
+		{Name: "Unused"},
+		{Name: "AWS", UseCount: 3, LastUsed: &older},
+		{Name: "GitHub", UseCount: 7, LastUsed: &older},
+		{Name: "Slack", UseCount: 3, LastUsed: &newer},
+		{Name: "Gmail", UseCount: 1, LastUsed: &newer},
+	}
+
+	got := topServices(services, 10)
+	want := []string{"GitHub", "Slack", "AWS", "Gmail"}
+	if len(got) != len(want) {
+		t.Fatalf("topServices() returned %d services, want %d", len(got), len(want))
+	}
+	for i, name := range want {
+		if got[i].Name != name {
+			t.Errorf("topServices()[%d] = %s, want %s", i, got[i].Name, name)
+		}
+	}
+
+	if limited := topServices(services, 2); len(limited) != 2 || limited[1].Name != "Slack" {
+		t.Errorf("topServices(limit 2) = %v, want [GitHub Slack]", limited)
+	}
+}
+
+// TestTopCommand tests the ranked table output and --limit
+func TestTopCommand(t *testing.T) {
+	lastUsed := time.Date(2024, 1, 2, 3, 4, 0, 0, time.UTC)
+	setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(), UseCount: 2, LastUsed: &lastUsed},
+		storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(), UseCount: 9, LastUsed: &lastUsed},
+		storage.Service{Name: "Slack", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = TopCommand([]string{})
+	})
+	if code != 0 {
+		t.Fatalf("TopCommand() = %d, want 0", code)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %q", out)
+	}
+	if !strings.HasPrefix(lines[1], "1") || !strings.Contains(lines[1], "GitHub") || !strings.Contains(lines[1], "9") {
+		t.Errorf("First row = %q, want GitHub ranked 1 with 9 uses", lines[1])
+	}
+	if !strings.Contains(lines[2], "AWS") || !strings.Contains(lines[2], "2024-01-02 03:04") {
+		t.Errorf("Second row = %q, want AWS with its last-used time", lines[2])
+	}
+	if strings.Contains(out, "Slack") {
+		t.Error("Unused services should not be listed")
+	}
+
+	out = captureStdout(t, func() {
+		code = TopCommand([]string{"--limit", "1"})
+	})
+	if code != 0 || strings.Contains(out, "AWS") {
+		t.Errorf("TopCommand(--limit 1) = %d, output %q; want only GitHub", code, out)
+	}
+}
+
+// TestTopCommand_NoUsage tests the message when nothing has been copied
+func TestTopCommand_NoUsage(t *testing.T) {
+	setupTestStorage(t, "test-passphrase",
+		storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+	)
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+
+	out := captureStdout(t, func() {
+		if code := TopCommand([]string{}); code != 0 {
+			t.Errorf("TopCommand() = %d, want 0", code)
+		}
+	})
+	if !strings.Contains(out, "No codes copied yet") {
+		t.Errorf("Expected empty-usage message, got %q", out)
+	}
+}
+
+// TestTopCommand_InvalidLimit tests --limit must be positive
+func TestTopCommand_InvalidLimit(t *testing.T) {
+	if code := TopCommand([]string{"--limit", "0"}); code != 1 {
+		t.Errorf("TopCommand(--limit 0) = %d, want 1", code)
+	}
+}
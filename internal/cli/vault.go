@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// vaultExt is the file extension of encrypted vault files
+const vaultExt = ".enc"
+
+// vaultInfo describes a vault file; vault commands never decrypt
+type vaultInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+	Default bool
+}
+
+// VaultCommand manages vault files in the storage directory: list, rename, delete
+func VaultCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: a vault subcommand is required")
+		fmt.Fprintln(os.Stderr, "Usage: totp vault list | rename OLD NEW | delete [--yes] NAME")
+		return 1
+	}
+
+	path, err := storage.GetDefaultStoragePath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to get storage path: %v\n", err)
+		return 1
+	}
+	dir := filepath.Dir(path)
+	defaultName := strings.TrimSuffix(filepath.Base(path), vaultExt)
+
+	switch args[0] {
+	case "list":
+		return vaultList(dir, defaultName)
+	case "rename":
+		return vaultRename(dir, defaultName, args[1:])
+	case "delete":
+		return vaultDelete(dir, defaultName, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown vault subcommand %q (use list, rename or delete)\n", args[0])
+		return 1
+	}
+}
+
+// vaultList prints the vault files in dir, marking the default vault
+func vaultList(dir, defaultName string) int {
+	vaults, err := listVaults(dir, defaultName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if len(vaults) == 0 {
+		fmt.Printf("No vaults found in %s\n", dir)
+		return 0
+	}
+
+	if err := writeVaults(os.Stdout, vaults); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// vaultSidecars are the suffixes of files the store writes next to a vault:
+// the in-place write backup and the atomic write temp file
+var vaultSidecars = []string{".bak", ".tmp"}
+
+// vaultRename renames a vault file and its sidecar files, refusing to
+// overwrite an existing vault or to rename the default vault
+func vaultRename(dir, defaultName string, args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: totp vault rename OLD NEW")
+		return 1
+	}
+
+	for _, name := range args {
+		if err := validateVaultName(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	// Every command opens the default vault, so renaming it would leave
+	// the next command to create an empty one in its place
+	if args[0] == defaultName {
+		fmt.Fprintf(os.Stderr, "Error: '%s' is the default vault and cannot be renamed\n", args[0])
+		return 1
+	}
+
+	oldPath := vaultPath(dir, args[0])
+	newPath := vaultPath(dir, args[1])
+
+	if _, err := os.Stat(oldPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: vault '%s' not found\n", args[0])
+		return 1
+	}
+	if _, err := os.Lstat(newPath); err == nil {
+		fmt.Fprintf(os.Stderr, "Error: vault '%s' already exists\n", args[1])
+		return 1
+	}
+
+	// Check every sidecar target before moving anything, so a leftover
+	// backup of the new name is never overwritten
+	for _, suffix := range vaultSidecars {
+		if _, err := os.Lstat(newPath + suffix); err == nil {
+			fmt.Fprintf(os.Stderr, "Error: %s already exists (move or delete it first)\n", filepath.Base(newPath+suffix))
+			return 1
+		}
+	}
+
+	if err := os.Rename(oldPath, newPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to rename vault: %v\n", err)
+		return 1
+	}
+	for _, suffix := range vaultSidecars {
+		if err := os.Rename(oldPath+suffix, newPath+suffix); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to rename %s: %v\n", filepath.Base(oldPath+suffix), err)
+		}
+	}
+
+	fmt.Printf("✓ Vault '%s' renamed to '%s'\n", args[0], args[1])
+	return 0
+}
+
+// vaultDelete removes a vault file and its sidecar files after the user
+// types its name to confirm; the default vault cannot be deleted
+func vaultDelete(dir, defaultName string, args []string) int {
+	fs := flag.NewFlagSet("vault delete", flag.ExitOnError)
+	yes := fs.Bool("yes", false, "Delete without asking for confirmation")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: totp vault delete [--yes] NAME")
+		return 1
+	}
+	name := fs.Arg(0)
+
+	if err := validateVaultName(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if name == defaultName {
+		fmt.Fprintf(os.Stderr, "Error: '%s' is the default vault and cannot be deleted\n", name)
+		return 1
+	}
+
+	path := vaultPath(dir, name)
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: vault '%s' not found\n", name)
+		return 1
+	}
+
+	if !*yes {
+		fmt.Printf("This permanently deletes vault '%s' and every secret in it.\n", name)
+		fmt.Print("Type the vault name to confirm: ")
+		answer, err := stdinReader().ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			fmt.Fprintf(os.Stderr, "Error: failed to read confirmation: %v\n", err)
+			return 1
+		}
+		fmt.Println()
+		if strings.TrimSpace(answer) != name {
+			fmt.Fprintln(os.Stderr, "Error: confirmation did not match; vault not deleted")
+			return 1
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to delete vault: %v\n", err)
+		return 1
+	}
+	// The backup holds the same secrets, so it must go too
+	for _, suffix := range vaultSidecars {
+		if err := os.Remove(path + suffix); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to delete %s: %v\n", filepath.Base(path+suffix), err)
+		}
+	}
+
+	fmt.Printf("✓ Vault '%s' deleted\n", name)
+	return 0
+}
+
+// listVaults returns the vault files in dir sorted by name
+func listVaults(dir, defaultName string) ([]vaultInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read storage directory: %w", err)
+	}
+
+	var vaults []vaultInfo
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != vaultExt {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", entry.Name(), err)
+		}
+		name := strings.TrimSuffix(entry.Name(), vaultExt)
+		vaults = append(vaults, vaultInfo{
+			Name:    name,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			Default: name == defaultName,
+		})
+	}
+
+	sort.Slice(vaults, func(i, j int) bool { return vaults[i].Name < vaults[j].Name })
+	return vaults, nil
+}
+
+// writeVaults renders vaults as a table; the default vault is marked with '*'
+func writeVaults(w io.Writer, vaults []vaultInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSIZE\tMODIFIED")
+	for _, vault := range vaults {
+		name := vault.Name
+		if vault.Default {
+			name += " *"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", name, vault.Size, vault.ModTime.Format(listTimeFormat))
+	}
+	return tw.Flush()
+}
+
+// validateVaultName rejects names that could escape the storage directory
+func validateVaultName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid vault name %q", name)
+	}
+	return nil
+}
+
+// vaultPath returns the file path of a named vault
+func vaultPath(dir, name string) string {
+	return filepath.Join(dir, name+vaultExt)
+}
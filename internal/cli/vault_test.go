@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupVaults creates the default vault plus extra vault files (never decrypted,
+// so their content is arbitrary) and returns the storage directory
+func setupVaults(t *testing.T, names ...string) string {
+	t.Helper()
+
+	dir := filepath.Dir(setupTestStorage(t, "test-passphrase"))
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name+".enc"), []byte("ciphertext"), 0600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a vault"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return dir
+}
+
+// TestVaultCommand_List tests listing enumerates vault files and marks the default
+func TestVaultCommand_List(t *testing.T) {
+	setupVaults(t, "work", "personal")
+
+	var code int
+	out := captureStdout(t, func() {
+		code = VaultCommand([]string{"list"})
+	})
+	if code != 0 {
+		t.Fatalf("VaultCommand(list) = %d, want 0", code)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected header and 3 vaults, got %q", out)
+	}
+	for i, want := range []string{"personal", "secrets *", "work"} {
+		if !strings.HasPrefix(lines[i+1], want) {
+			t.Errorf("Row %d = %q, want prefix %q", i+1, lines[i+1], want)
+		}
+	}
+	if strings.Contains(out, "notes") {
+		t.Error("Non-vault files should not be listed")
+	}
+}
+
+// TestVaultCommand_Rename tests rename moves the file and refuses collisions
+func TestVaultCommand_Rename(t *testing.T) {
+	dir := setupVaults(t, "work", "personal")
+
+	var code int
+	captureStdout(t, func() {
+		code = VaultCommand([]string{"rename", "work", "job"})
+	})
+	if code != 0 {
+		t.Fatalf("VaultCommand(rename) = %d, want 0", code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "job.enc")); err != nil {
+		t.Errorf("Renamed vault missing: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "work.enc")); !os.IsNotExist(err) {
+		t.Error("Old vault file should be gone")
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Collision", []string{"rename", "job", "personal"}},
+		{"Missing source", []string{"rename", "missing", "other"}},
+		{"Path traversal", []string{"rename", "job", "../escape"}},
+		{"Wrong arg count", []string{"rename", "job"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := VaultCommand(tt.args); code != 1 {
+				t.Errorf("VaultCommand(%v) = %d, want 1", tt.args, code)
+			}
+		})
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "personal.enc"))
+	if err != nil || string(data) != "ciphertext" {
+		t.Error("A rename collision must not overwrite the existing vault")
+	}
+}
+
+// TestVaultCommand_Delete tests delete requires typing the vault name
+func TestVaultCommand_Delete(t *testing.T) {
+	dir := setupVaults(t, "work")
+	path := filepath.Join(dir, "work.enc")
+
+	// Wrong confirmation keeps the vault
+	withStdin(t, "yes\n")
+	var code int
+	captureStdout(t, func() {
+		code = VaultCommand([]string{"delete", "work"})
+	})
+	if code != 1 {
+		t.Errorf("VaultCommand(delete) with wrong confirmation = %d, want 1", code)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatal("Vault should not be deleted without confirmation")
+	}
+
+	// Typing the name confirms
+	withStdin(t, "work\n")
+	captureStdout(t, func() {
+		code = VaultCommand([]string{"delete", "work"})
+	})
+	if code != 0 {
+		t.Fatalf("VaultCommand(delete) = %d, want 0", code)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Vault should be deleted after confirmation")
+	}
+}
+
+// TestVaultCommand_DeleteYes tests --yes skips the prompt
+func TestVaultCommand_DeleteYes(t *testing.T) {
+	dir := setupVaults(t, "work")
+
+	var code int
+	captureStdout(t, func() {
+		code = VaultCommand([]string{"delete", "--yes", "work"})
+	})
+	if code != 0 {
+		t.Fatalf("VaultCommand(delete --yes) = %d, want 0", code)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "work.enc")); !os.IsNotExist(err) {
+		t.Error("Vault should be deleted")
+	}
+
+	if code := VaultCommand([]string{"delete", "--yes", "missing"}); code != 1 {
+		t.Errorf("Deleting a missing vault = %d, want 1", code)
+	}
+}
+
+// TestVaultCommand_Sidecars tests rename and delete carry the vault's
+// backup and temp files along
+func TestVaultCommand_Sidecars(t *testing.T) {
+	dir := setupVaults(t, "work")
+	for _, suffix := range []string{".bak", ".tmp"} {
+		if err := os.WriteFile(filepath.Join(dir, "work.enc"+suffix), []byte("ciphertext"), 0600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	var code int
+	captureStdout(t, func() {
+		code = VaultCommand([]string{"rename", "work", "job"})
+	})
+	if code != 0 {
+		t.Fatalf("VaultCommand(rename) = %d, want 0", code)
+	}
+	for _, suffix := range []string{".bak", ".tmp"} {
+		if _, err := os.Stat(filepath.Join(dir, "job.enc"+suffix)); err != nil {
+			t.Errorf("%s file should follow the rename: %v", suffix, err)
+		}
+	}
+
+	captureStdout(t, func() {
+		code = VaultCommand([]string{"delete", "--yes", "job"})
+	})
+	if code != 0 {
+		t.Fatalf("VaultCommand(delete) = %d, want 0", code)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "job.") || strings.HasPrefix(entry.Name(), "work.") {
+			t.Errorf("%s should be deleted with the vault", entry.Name())
+		}
+	}
+}
+
+// TestVaultCommand_RenameKeepsExistingSidecars tests a rename fails without
+// moving anything when a sidecar of the new name already exists
+func TestVaultCommand_RenameKeepsExistingSidecars(t *testing.T) {
+	dir := setupVaults(t, "work")
+	for name, content := range map[string]string{"work.enc.bak": "work backup", "job.enc.bak": "job backup"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	if code := VaultCommand([]string{"rename", "work", "job"}); code != 1 {
+		t.Fatalf("VaultCommand(rename) = %d, want 1", code)
+	}
+	for _, name := range []string{"work.enc", "work.enc.bak"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s should not be moved: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "job.enc")); !os.IsNotExist(err) {
+		t.Errorf("job.enc should not be created, stat error = %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "job.enc.bak")); err != nil || string(data) != "job backup" {
+		t.Errorf("job.enc.bak = %q, %v; want it untouched", data, err)
+	}
+}
+
+// TestVaultCommand_DefaultVaultProtected tests the default vault can be
+// neither renamed nor deleted
+func TestVaultCommand_DefaultVaultProtected(t *testing.T) {
+	dir := setupVaults(t)
+
+	for _, args := range [][]string{{"rename", "secrets", "old"}, {"delete", "--yes", "secrets"}} {
+		if code := VaultCommand(args); code != 1 {
+			t.Errorf("VaultCommand(%v) = %d, want 1", args, code)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "secrets.enc")); err != nil {
+		t.Errorf("Default vault should be kept: %v", err)
+	}
+}
+
+// TestVaultCommand_Usage tests missing and unknown subcommands
+func TestVaultCommand_Usage(t *testing.T) {
+	setupVaults(t)
+
+	for _, args := range [][]string{{}, {"move"}} {
+		if code := VaultCommand(args); code != 1 {
+			t.Errorf("VaultCommand(%v) = %d, want 1", args, code)
+		}
+	}
+}
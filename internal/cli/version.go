@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// Version is the binary version, injected at build time:
+//
+//	go build -ldflags "-X github.com/pavanprakash21/totp-manager-go/internal/cli.Version=v1.2.3"
+var Version = "dev"
+
+// versionInfo is the version report printed by the version command
+type versionInfo struct {
+	Version        string `json:"version"`
+	StorageFormats []int  `json:"storage_formats"`
+	GoVersion      string `json:"go_version"`
+}
+
+// VersionCommand prints the binary, storage format and Go runtime versions
+func VersionCommand(args []string) int {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print version information as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	info := versionInfo{
+		Version:        Version,
+		StorageFormats: storage.SupportedFormatVersions(),
+		GoVersion:      runtime.Version(),
+	}
+
+	if err := writeVersion(os.Stdout, info, *asJSON); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeVersion renders version information as text or JSON
+func writeVersion(w io.Writer, info versionInfo, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	}
+
+	formats := make([]string, len(info.StorageFormats))
+	for i, format := range info.StorageFormats {
+		formats[i] = fmt.Sprintf("%d", format)
+	}
+
+	_, err := fmt.Fprintf(w, "totp %s\nstorage formats: %s\ngo: %s\n",
+		info.Version, strings.Join(formats, ", "), info.GoVersion)
+	return err
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestVersionCommand tests the text output contains every field
+func TestVersionCommand(t *testing.T) {
+	oldVersion := Version
+	Version = "v1.2.3"
+	defer func() { Version = oldVersion }()
+
+	var code int
+	out := captureStdout(t, func() {
+		code = VersionCommand([]string{})
+	})
+	if code != 0 {
+		t.Fatalf("VersionCommand() = %d, want 0", code)
+	}
+
+	for _, want := range []string{"totp v1.2.3", "storage formats: 1, 2", "go: " + runtime.Version()} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Output %q missing %q", out, want)
+		}
+	}
+}
+
+// TestVersionCommand_JSON tests --json output parses with the expected fields
+func TestVersionCommand_JSON(t *testing.T) {
+	var code int
+	out := captureStdout(t, func() {
+		code = VersionCommand([]string{"--json"})
+	})
+	if code != 0 {
+		t.Fatalf("VersionCommand() = %d, want 0", code)
+	}
+
+	var info versionInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, out)
+	}
+	if info.Version != Version {
+		t.Errorf("version = %q, want %q", info.Version, Version)
+	}
+	if len(info.StorageFormats) != 4 || info.StorageFormats[0] != 1 || info.StorageFormats[3] != 4 {
+		t.Errorf("storage_formats = %v, want [1 2 3 4]", info.StorageFormats)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("go_version = %q, want %q", info.GoVersion, runtime.Version())
+	}
+}
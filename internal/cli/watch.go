@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// watchEvent is one line of `watch --json` output
+type watchEvent struct {
+	Name      string `json:"name"`
+	Code      string `json:"code"`
+	Remaining int    `json:"remaining"` // seconds until the code changes
+	Step      int64  `json:"step"`      // RFC 6238 time step (unix time / period)
+}
+
+// WatchCommand prints a service's code each time it changes, until
+// interrupted or --count codes have been printed
+func WatchCommand(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	name := fs.String("name", "", "Service name (required)")
+	asJSON := fs.Bool("json", false, "Emit one JSON object per refresh (name, code, remaining, step)")
+	count := fs.Int("count", 0, "Stop after this many codes (0 = until interrupted)")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		return 1
+	}
+
+	if *name == "" {
+		fmt.Fprintln(os.Stderr, "Error: --name is required")
+		fmt.Fprintln(os.Stderr, "Usage: totp watch --name SERVICE_NAME [--json] [--count N]")
+		return 1
+	}
+
+	if *count < 0 {
+		fmt.Fprintln(os.Stderr, "Error: --count must be 0 or more")
+		return 1
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := app.InitializeExisting(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	service, err := app.store.GetServiceCopy(*name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := watch(os.Stdout, &service, *count, *asJSON); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// watch writes the current code, then sleeps to each period boundary and
+// writes the next one; count 0 repeats forever
+func watch(w io.Writer, service *storage.Service, count int, asJSON bool) error {
+	period := int64(service.EffectivePeriod())
+	encoder := json.NewEncoder(w)
+
+	for i := 0; count == 0 || i < count; i++ {
+		t := now()
+		code, err := service.Code(t)
+		if err != nil {
+			return fmt.Errorf("failed to generate code: %w", err)
+		}
+
+		step := t.Unix() / period
+		remaining := int(period - t.Unix()%period)
+		if asJSON {
+			if err := encoder.Encode(watchEvent{Name: service.Name, Code: code, Remaining: remaining, Step: step}); err != nil {
+				return err
+			}
+		} else {
+			fmt.Fprintf(w, "%s  %s  (%ds)\n", service.Name, code, remaining)
+		}
+
+		if count != 0 && i == count-1 {
+			break
+		}
+		sleep(time.Unix((step+1)*period, 0).Sub(t))
+	}
+
+	return nil
+}
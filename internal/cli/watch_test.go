@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestWatch_JSON tests one valid JSON line is emitted at each boundary
+func TestWatch_JSON(t *testing.T) {
+	start := time.Unix(50, 0)
+	sleeps := fakeClock(t, start)
+	service := &storage.Service{Name: "RFC", Secret: rfc6238Secret, CreatedAt: start}
+
+	var buf bytes.Buffer
+	if err := watch(&buf, service, 3, true); err != nil {
+		t.Fatalf("watch() error = %v", err)
+	}
+
+	var events []watchEvent
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var event watchEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("Invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+
+	want := []watchEvent{
+		{Name: "RFC", Code: "287082", Remaining: 10, Step: 1},
+		{Name: "RFC", Remaining: 30, Step: 2},
+		{Name: "RFC", Remaining: 30, Step: 3},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Got %d events, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.Name != want[i].Name || event.Remaining != want[i].Remaining || event.Step != want[i].Step {
+			t.Errorf("Event %d = %+v, want %+v", i, event, want[i])
+		}
+		code, _ := service.Code(time.Unix(event.Step*30, 0))
+		if event.Code != code {
+			t.Errorf("Event %d code = %s, want %s", i, event.Code, code)
+		}
+	}
+	if events[0].Code != want[0].Code {
+		t.Errorf("First code = %s, want RFC 6238 vector %s", events[0].Code, want[0].Code)
+	}
+
+	// Sleeps land on boundaries; none after the last event
+	if *sleeps != 2 {
+		t.Errorf("Slept %d times, want 2", *sleeps)
+	}
+}
+
+// TestWatch_Text tests the plain output and the service's own period
+func TestWatch_Text(t *testing.T) {
+	start := time.Unix(100, 0)
+	fakeClock(t, start)
+	service := &storage.Service{Name: "Slow", Secret: rfc6238Secret, Period: 60, CreatedAt: start}
+
+	var buf bytes.Buffer
+	if err := watch(&buf, service, 2, false); err != nil {
+		t.Fatalf("watch() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "(20s)") || !strings.HasSuffix(lines[1], "(60s)") {
+		t.Errorf("Output = %q, want two lines counting down 20s then 60s", buf.String())
+	}
+}
+
+// TestWatchCommand_InvalidArgs tests argument validation
+func TestWatchCommand_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Missing name", []string{"--json"}},
+		{"Negative count", []string{"--name", "RFC", "--count", "-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := WatchCommand(tt.args); code != 1 {
+				t.Errorf("WatchCommand() = %d, want 1", code)
+			}
+		})
+	}
+}
+
+// TestWatchCommand_Count tests the command stops after --count codes
+func TestWatchCommand_Count(t *testing.T) {
+	setupTestStorage(t, "test-passphrase", storage.Service{
+		Name: "RFC", Secret: rfc6238Secret, CreatedAt: time.Now(),
+	})
+	t.Setenv(passphraseEnvVar, "test-passphrase")
+	fakeClock(t, time.Unix(59, 0))
+
+	var code int
+	out := captureStdout(t, func() {
+		code = WatchCommand([]string{"--name", "rfc", "--json", "--count", "2"})
+	})
+	if code != 0 {
+		t.Fatalf("WatchCommand() = %d, want 0", code)
+	}
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 2 || !strings.Contains(lines[0], `"code":"287082"`) {
+		t.Errorf("Output = %q, want 2 JSON lines starting with 287082", out)
+	}
+}
@@ -1,13 +1,169 @@
 package clipboard
 
 import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/atotto/clipboard"
 )
 
-// Copy copies text to the system clipboard
+// System hooks (overridable in tests)
+var (
+	getenv   = os.Getenv
+	lookPath = exec.LookPath
+
+	// commandHelp returns a command's --help output, used to probe flag support
+	commandHelp = func(name string) string {
+		out, _ := exec.Command(name, "--help").CombinedOutput()
+		return string(out)
+	}
+
+	// runCopy runs a copy command with text on stdin
+	runCopy = func(name string, args []string, text string) error {
+		cmd := exec.Command(name, args...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+
+	writeAll  = clipboard.WriteAll
+	readAll   = clipboard.ReadAll
+	afterFunc = time.AfterFunc
+)
+
+// Copy copies text to the clipboard through the configured backend
 // (T047: Clipboard copy with visual confirmation)
 // (T048: Clipboard error handling)
 func Copy(text string) error {
+	active := activeBackend()
+	if active == BackendOSC52 {
+		return copyOSC52(text)
+	}
+
+	// Prefer a backend that can mark the entry as sensitive so clipboard
+	// managers skip it in their history
+	if name, args, ok := sensitiveCopyCommand(); ok {
+		return runCopy(name, args, text)
+	}
+
 	// Use atotto/clipboard for cross-platform support
-	return clipboard.WriteAll(text)
+	err := writeAll(text)
+	if err != nil && active == BackendAuto {
+		// e.g. a headless terminal without clipboard utilities
+		if copyOSC52(text) == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// wlCopyProbe caches whether wl-copy supports --sensitive. Probing runs
+// wl-copy --help, which is too slow to repeat on every copy.
+type wlCopyProbe struct {
+	once sync.Once
+	path string
+	ok   bool
+}
+
+// sensitiveProbe is the process-wide probe result (replaced in tests)
+var sensitiveProbe = &wlCopyProbe{}
+
+// sensitiveCopyCommand returns a copy command that marks entries as
+// sensitive, when the current backend supports it. Currently wl-copy on
+// Wayland (wl-clipboard >= 2.2 has --sensitive).
+func sensitiveCopyCommand() (string, []string, bool) {
+	if getenv("WAYLAND_DISPLAY") == "" {
+		return "", nil, false
+	}
+
+	probe := sensitiveProbe
+	probe.once.Do(func() {
+		path, err := lookPath("wl-copy")
+		if err != nil {
+			return
+		}
+		probe.path, probe.ok = path, strings.Contains(commandHelp(path), "--sensitive")
+	})
+	if !probe.ok {
+		return "", nil, false
+	}
+
+	return probe.path, []string{"--sensitive"}, true
+}
+
+// Read returns the current clipboard contents, or ErrReadUnsupported when
+// the clipboard is set through OSC 52
+func Read() (string, error) {
+	if activeBackend() == BackendOSC52 {
+		return "", ErrReadUnsupported
+	}
+	return readAll()
+}
+
+// ScheduledClear is a pending clipboard clear started by ClearAfter
+type ScheduledClear struct {
+	text  string
+	timer *time.Timer
+	done  chan struct{}
+}
+
+// ClearAfter schedules ClearIfUnchanged(text) after the given number of
+// seconds. seconds <= 0 never clears.
+func ClearAfter(text string, seconds int) *ScheduledClear {
+	c := &ScheduledClear{text: text, done: make(chan struct{})}
+	if seconds <= 0 {
+		close(c.done)
+		return c
+	}
+
+	c.timer = afterFunc(time.Duration(seconds)*time.Second, func() {
+		defer close(c.done)
+		_, _ = ClearIfUnchanged(text)
+	})
+	return c
+}
+
+// Stop cancels the clear if it has not run yet
+func (c *ScheduledClear) Stop() {
+	if c.timer != nil && c.timer.Stop() {
+		close(c.done)
+	}
+}
+
+// Flush runs the clear now if it has not run yet, then waits for it. Callers
+// about to exit use it so quitting early never leaves the text behind.
+func (c *ScheduledClear) Flush() {
+	if c.timer != nil && c.timer.Stop() {
+		defer close(c.done)
+		_, _ = ClearIfUnchanged(c.text)
+		return
+	}
+	c.Wait()
+}
+
+// Wait blocks until the clear has run or been stopped; short-lived commands
+// use it so the process does not exit before clearing
+func (c *ScheduledClear) Wait() {
+	<-c.done
+}
+
+// ClearIfUnchanged empties the clipboard only if it still holds text, so
+// anything the user copied since is left alone. It reports whether it cleared.
+// A clipboard that cannot be read back (OSC 52, including the auto fallback
+// when no system clipboard is reachable) is cleared unconditionally.
+func ClearIfUnchanged(text string) (bool, error) {
+	current, err := Read()
+	if err != nil {
+		if errors.Is(err, ErrReadUnsupported) || activeBackend() == BackendAuto {
+			return true, Copy("")
+		}
+		return false, err
+	}
+	if current != text {
+		return false, nil
+	}
+	return true, Copy("")
 }
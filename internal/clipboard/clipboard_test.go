@@ -1,7 +1,9 @@
 package clipboard
 
 import (
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestCopy(t *testing.T) {
@@ -69,3 +71,225 @@ func TestCopy_Unicode(t *testing.T) {
 		t.Logf("Clipboard error (expected in CI): %v", err)
 	}
 }
+
+func TestRead_RoundTrip(t *testing.T) {
+	// Test reading back copied text
+	if err := Copy("654321"); err != nil {
+		t.Skipf("Clipboard not available (expected in CI): %v", err)
+	}
+
+	got, err := Read()
+	if err != nil {
+		t.Skipf("Clipboard read not available (expected in CI): %v", err)
+	}
+	if got != "654321" {
+		t.Errorf("Read() = %q, want %q", got, "654321")
+	}
+}
+
+// stubBackend fakes the environment for backend detection
+func stubBackend(t *testing.T, wayland bool, hasWlCopy bool, help string) {
+	t.Helper()
+
+	oldGetenv, oldLookPath, oldHelp, oldProbe := getenv, lookPath, commandHelp, sensitiveProbe
+	t.Cleanup(func() { getenv, lookPath, commandHelp, sensitiveProbe = oldGetenv, oldLookPath, oldHelp, oldProbe })
+	sensitiveProbe = &wlCopyProbe{}
+
+	getenv = func(key string) string {
+		if key == "WAYLAND_DISPLAY" && wayland {
+			return "wayland-0"
+		}
+		return ""
+	}
+	lookPath = func(name string) (string, error) {
+		if name == "wl-copy" && hasWlCopy {
+			return "/usr/bin/wl-copy", nil
+		}
+		return "", errors.New("not found")
+	}
+	commandHelp = func(string) string { return help }
+}
+
+func TestSensitiveCopyCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		wayland   bool
+		hasWlCopy bool
+		help      string
+		wantOK    bool
+	}{
+		{"wl-copy with --sensitive", true, true, "  -s, --sensitive  Mark the copy as sensitive", true},
+		{"Old wl-copy", true, true, "  -o, --paste-once", false},
+		{"Wayland without wl-copy", true, false, "", false},
+		{"Not Wayland", false, true, "--sensitive", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubBackend(t, tt.wayland, tt.hasWlCopy, tt.help)
+
+			name, args, ok := sensitiveCopyCommand()
+			if ok != tt.wantOK {
+				t.Fatalf("sensitiveCopyCommand() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && (name != "/usr/bin/wl-copy" || len(args) != 1 || args[0] != "--sensitive") {
+				t.Errorf("Command = %s %v, want /usr/bin/wl-copy [--sensitive]", name, args)
+			}
+		})
+	}
+}
+
+// TestSensitiveCopyCommand_ProbesOnce tests wl-copy --help runs only once
+func TestSensitiveCopyCommand_ProbesOnce(t *testing.T) {
+	stubBackend(t, true, true, "")
+	probes := 0
+	commandHelp = func(string) string {
+		probes++
+		return "--sensitive"
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, _, ok := sensitiveCopyCommand(); !ok {
+			t.Fatal("sensitiveCopyCommand() ok = false, want true")
+		}
+	}
+	if probes != 1 {
+		t.Errorf("wl-copy --help ran %d times, want 1", probes)
+	}
+}
+
+func TestCopy_WlCopySensitive(t *testing.T) {
+	stubBackend(t, true, true, "--sensitive")
+
+	var gotName, gotText string
+	var gotArgs []string
+	oldRun := runCopy
+	runCopy = func(name string, args []string, text string) error {
+		gotName, gotArgs, gotText = name, args, text
+		return nil
+	}
+	defer func() { runCopy = oldRun }()
+
+	if err := Copy("123456"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if gotName != "/usr/bin/wl-copy" || len(gotArgs) != 1 || gotArgs[0] != "--sensitive" || gotText != "123456" {
+		t.Errorf("Ran %s %v with %q, want wl-copy --sensitive with the code", gotName, gotArgs, gotText)
+	}
+}
+
+// fakeClipboard replaces the clipboard backend with an in-memory value
+func fakeClipboard(t *testing.T, initial string) *string {
+	t.Helper()
+
+	stubBackend(t, false, false, "")
+	content := initial
+	oldWrite, oldRead := writeAll, readAll
+	t.Cleanup(func() { writeAll, readAll = oldWrite, oldRead })
+
+	writeAll = func(text string) error {
+		content = text
+		return nil
+	}
+	readAll = func() (string, error) { return content, nil }
+	return &content
+}
+
+// stubAfterFunc records the scheduled delay and runs the callback when fire is called
+func stubAfterFunc(t *testing.T) (delay *time.Duration, fire func()) {
+	t.Helper()
+
+	var d time.Duration
+	var f func()
+	old := afterFunc
+	t.Cleanup(func() { afterFunc = old })
+
+	afterFunc = func(after time.Duration, fn func()) *time.Timer {
+		d, f = after, fn
+		return time.NewTimer(time.Hour)
+	}
+	return &d, func() {
+		if f != nil {
+			f()
+		}
+	}
+}
+
+func TestClearAfter_HonorsSeconds(t *testing.T) {
+	content := fakeClipboard(t, "123456")
+	delay, fire := stubAfterFunc(t)
+
+	clear := ClearAfter("123456", 45)
+	if *delay != 45*time.Second {
+		t.Errorf("Scheduled after %v, want 45s", *delay)
+	}
+
+	fire()
+	clear.Wait()
+	if *content != "" {
+		t.Errorf("Clipboard = %q, want cleared", *content)
+	}
+}
+
+func TestClearAfter_ZeroDisables(t *testing.T) {
+	content := fakeClipboard(t, "123456")
+	delay, fire := stubAfterFunc(t)
+
+	clear := ClearAfter("123456", 0)
+	clear.Wait() // returns immediately when disabled
+	fire()
+
+	if *delay != 0 {
+		t.Errorf("Scheduled after %v, want no timer", *delay)
+	}
+	if *content != "123456" {
+		t.Errorf("Clipboard = %q, want it left alone", *content)
+	}
+}
+
+func TestScheduledClear_Flush(t *testing.T) {
+	content := fakeClipboard(t, "123456")
+	stubAfterFunc(t)
+
+	clear := ClearAfter("123456", 60)
+	clear.Flush()
+	if *content != "" {
+		t.Errorf("Clipboard = %q, want cleared before the timer fires", *content)
+	}
+
+	// The clear already ran, so flushing again does nothing
+	*content = "newer"
+	clear.Flush()
+	if *content != "newer" {
+		t.Errorf("Clipboard = %q, a second flush should do nothing", *content)
+	}
+}
+
+func TestClearIfUnchanged(t *testing.T) {
+	content := fakeClipboard(t, "something else")
+
+	cleared, err := ClearIfUnchanged("123456")
+	if err != nil || cleared {
+		t.Fatalf("ClearIfUnchanged() = %v, %v; want false, nil", cleared, err)
+	}
+	if *content != "something else" {
+		t.Errorf("Clipboard = %q, should keep newer content", *content)
+	}
+
+	*content = "123456"
+	if cleared, err := ClearIfUnchanged("123456"); err != nil || !cleared || *content != "" {
+		t.Errorf("ClearIfUnchanged() = %v, %v with clipboard %q; want cleared", cleared, err, *content)
+	}
+}
+
+func TestScheduledClear_Stop(t *testing.T) {
+	content := fakeClipboard(t, "123456")
+
+	clear := ClearAfter("123456", 60)
+	clear.Stop()
+	clear.Wait() // returns once stopped
+
+	if *content != "123456" {
+		t.Errorf("Clipboard = %q, stopped clear should leave it alone", *content)
+	}
+}
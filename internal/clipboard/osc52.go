@@ -0,0 +1,100 @@
+package clipboard
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Backend selects how text reaches the clipboard
+type Backend string
+
+const (
+	// BackendAuto uses OSC 52 in SSH sessions without a forwarded display
+	// and the system clipboard otherwise, falling back to OSC 52 when the
+	// system clipboard is unavailable
+	BackendAuto Backend = "auto"
+
+	// BackendSystem uses the local system clipboard only
+	BackendSystem Backend = "system"
+
+	// BackendOSC52 asks the terminal to set its clipboard with an OSC 52
+	// escape sequence, which works over SSH
+	BackendOSC52 Backend = "osc52"
+)
+
+// ErrReadUnsupported is returned by Read when the clipboard is set through
+// OSC 52, which this package cannot read back
+var ErrReadUnsupported = errors.New("clipboard cannot be read back over OSC 52")
+
+// backend is the configured backend (see SetBackend)
+var backend = BackendAuto
+
+// openTTY opens the controlling terminal for OSC 52 output (overridable in
+// tests). Writing to it rather than stdout keeps escapes out of pipes.
+var openTTY = func() (io.WriteCloser, error) {
+	return os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+}
+
+// ParseBackend parses a backend name; empty means BackendAuto
+func ParseBackend(name string) (Backend, error) {
+	switch b := Backend(strings.ToLower(strings.TrimSpace(name))); b {
+	case "":
+		return BackendAuto, nil
+	case BackendAuto, BackendSystem, BackendOSC52:
+		return b, nil
+	default:
+		return "", fmt.Errorf("unknown clipboard backend %q (use %s, %s or %s)", name, BackendAuto, BackendSystem, BackendOSC52)
+	}
+}
+
+// SetBackend selects the backend used by Copy and Read
+func SetBackend(b Backend) {
+	backend = b
+}
+
+// activeBackend resolves BackendAuto for the current session
+func activeBackend() Backend {
+	if backend != BackendAuto {
+		return backend
+	}
+	if remoteSession() {
+		return BackendOSC52
+	}
+	return BackendAuto
+}
+
+// remoteSession reports whether this is an SSH session with no forwarded
+// display, where the system clipboard would be the remote machine's (if any)
+func remoteSession() bool {
+	if getenv("SSH_TTY") == "" && getenv("SSH_CONNECTION") == "" {
+		return false
+	}
+	return getenv("DISPLAY") == "" && getenv("WAYLAND_DISPLAY") == ""
+}
+
+// copyOSC52 writes text to the terminal's clipboard
+func copyOSC52(text string) error {
+	tty, err := openTTY()
+	if err != nil {
+		return fmt.Errorf("no terminal for OSC 52: %w", err)
+	}
+	defer tty.Close()
+
+	_, err = io.WriteString(tty, osc52Sequence(text, getenv("TMUX") != ""))
+	return err
+}
+
+// osc52Sequence builds the escape sequence setting the clipboard to text.
+// Inside tmux it is wrapped in a passthrough sequence so tmux forwards it
+// to the outer terminal.
+func osc52Sequence(text string, tmux bool) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	if !tmux {
+		return seq
+	}
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
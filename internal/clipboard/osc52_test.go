@@ -0,0 +1,171 @@
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// ttyBuffer is a fake terminal recording what was written to it
+type ttyBuffer struct{ bytes.Buffer }
+
+func (*ttyBuffer) Close() error { return nil }
+
+// stubOSC52 sets the backend and environment and captures terminal output
+func stubOSC52(t *testing.T, b Backend, env map[string]string) *ttyBuffer {
+	t.Helper()
+	oldBackend, oldGetenv, oldOpen := backend, getenv, openTTY
+	t.Cleanup(func() { backend, getenv, openTTY = oldBackend, oldGetenv, oldOpen })
+
+	backend = b
+	getenv = func(key string) string { return env[key] }
+	tty := &ttyBuffer{}
+	openTTY = func() (io.WriteCloser, error) { return tty, nil }
+	return tty
+}
+
+func TestParseBackend(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Backend
+		wantErr bool
+	}{
+		{"", BackendAuto, false},
+		{"auto", BackendAuto, false},
+		{"System", BackendSystem, false},
+		{" osc52 ", BackendOSC52, false},
+		{"xclip", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseBackend(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseBackend(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestOSC52Sequence(t *testing.T) {
+	// "123456" is MTIzNDU2 in Base64
+	if got := osc52Sequence("123456", false); got != "\x1b]52;c;MTIzNDU2\a" {
+		t.Errorf("osc52Sequence() = %q", got)
+	}
+	if got := osc52Sequence("123456", true); got != "\x1bPtmux;\x1b\x1b]52;c;MTIzNDU2\a\x1b\\" {
+		t.Errorf("osc52Sequence(tmux) = %q", got)
+	}
+}
+
+func TestCopy_OSC52Backend(t *testing.T) {
+	tty := stubOSC52(t, BackendOSC52, nil)
+
+	if err := Copy("123456"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if tty.String() != "\x1b]52;c;MTIzNDU2\a" {
+		t.Errorf("Terminal got %q, want the OSC 52 sequence", tty.String())
+	}
+
+	if _, err := Read(); !errors.Is(err, ErrReadUnsupported) {
+		t.Errorf("Read() error = %v, want ErrReadUnsupported", err)
+	}
+}
+
+func TestClearIfUnchanged_OSC52(t *testing.T) {
+	tty := stubOSC52(t, BackendOSC52, nil)
+
+	cleared, err := ClearIfUnchanged("123456")
+	if err != nil || !cleared {
+		t.Fatalf("ClearIfUnchanged() = %v, %v; want true, nil", cleared, err)
+	}
+	if tty.String() != "\x1b]52;c;\a" {
+		t.Errorf("Terminal got %q, want an empty OSC 52 sequence", tty.String())
+	}
+}
+
+func TestClearIfUnchanged_AutoFallback(t *testing.T) {
+	tty := stubOSC52(t, BackendAuto, nil)
+	oldWrite, oldRead := writeAll, readAll
+	t.Cleanup(func() { writeAll, readAll = oldWrite, oldRead })
+	writeAll = func(string) error { return errors.New("no clipboard utilities") }
+	readAll = func() (string, error) { return "", errors.New("no clipboard utilities") }
+
+	if err := Copy("123456"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	tty.Reset()
+
+	if cleared, err := ClearIfUnchanged("123456"); err != nil || !cleared {
+		t.Fatalf("ClearIfUnchanged() = %v, %v; want true, nil", cleared, err)
+	}
+	if tty.String() != "\x1b]52;c;\a" {
+		t.Errorf("Terminal got %q, want the fallback to clear over OSC 52", tty.String())
+	}
+}
+
+func TestClearIfUnchanged_SystemReadError(t *testing.T) {
+	tty := stubOSC52(t, BackendSystem, nil)
+	oldWrite, oldRead := writeAll, readAll
+	t.Cleanup(func() { writeAll, readAll = oldWrite, oldRead })
+	writeAll = func(string) error {
+		t.Error("A forced system backend should not clear what it cannot read")
+		return nil
+	}
+	readAll = func() (string, error) { return "", errors.New("xclip failed") }
+
+	if cleared, err := ClearIfUnchanged("123456"); err == nil || cleared {
+		t.Errorf("ClearIfUnchanged() = %v, %v; want the read error", cleared, err)
+	}
+	if tty.Len() != 0 {
+		t.Errorf("Terminal got %q, want nothing", tty.String())
+	}
+}
+
+func TestActiveBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend Backend
+		env     map[string]string
+		want    Backend
+	}{
+		{"Local", BackendAuto, map[string]string{"DISPLAY": ":0"}, BackendAuto},
+		{"SSH", BackendAuto, map[string]string{"SSH_TTY": "/dev/pts/1"}, BackendOSC52},
+		{"SSH with X forwarding", BackendAuto, map[string]string{"SSH_CONNECTION": "10.0.0.1 22", "DISPLAY": "localhost:10.0"}, BackendAuto},
+		{"SSH forced system", BackendSystem, map[string]string{"SSH_TTY": "/dev/pts/1"}, BackendSystem},
+		{"Local forced OSC 52", BackendOSC52, nil, BackendOSC52},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubOSC52(t, tt.backend, tt.env)
+			if got := activeBackend(); got != tt.want {
+				t.Errorf("activeBackend() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopy_AutoFallsBackToOSC52(t *testing.T) {
+	tty := stubOSC52(t, BackendAuto, nil)
+	oldLookPath, oldWrite := lookPath, writeAll
+	t.Cleanup(func() { lookPath, writeAll = oldLookPath, oldWrite })
+	lookPath = func(string) (string, error) { return "", errors.New("not found") }
+	writeAll = func(string) error { return errors.New("no clipboard utilities available") }
+
+	if err := Copy("123456"); err != nil {
+		t.Fatalf("Copy() error = %v, want the OSC 52 fallback to succeed", err)
+	}
+	if tty.Len() == 0 {
+		t.Error("Expected the code to be sent over OSC 52")
+	}
+
+	// The system backend never falls back
+	tty.Reset()
+	backend = BackendSystem
+	if err := Copy("123456"); err == nil {
+		t.Error("Copy() with the system backend should report the failure")
+	}
+	if tty.Len() != 0 {
+		t.Error("The system backend must not write OSC 52")
+	}
+}
@@ -5,72 +5,136 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
 )
 
 const (
 	nonceSize = 12 // 12 bytes for GCM (96 bits)
 )
 
-// Encrypt encrypts plaintext using AES-256-GCM with authenticated encryption
-// Returns ciphertext (including auth tag), nonce, and error
-func Encrypt(plaintext, key []byte) (ciphertext, nonce []byte, err error) {
-	// Validate key size (must be 32 bytes for AES-256)
+// randReader supplies nonces and salts; tests swap in a deterministic reader
+// to check exact outputs, production always uses crypto/rand
+var randReader io.Reader = rand.Reader
+
+// Cipher identifies the AEAD used to encrypt storage
+type Cipher byte
+
+const (
+	// CipherAESGCM is AES-256-GCM with a 96-bit random nonce (the default)
+	CipherAESGCM Cipher = iota
+
+	// CipherXChaCha20Poly1305 is XChaCha20-Poly1305 with a 192-bit random
+	// nonce, safe from nonce collisions even for very frequent re-saves
+	CipherXChaCha20Poly1305
+)
+
+// String returns the cipher name accepted by ParseCipher
+func (c Cipher) String() string {
+	switch c {
+	case CipherAESGCM:
+		return "aes-256-gcm"
+	case CipherXChaCha20Poly1305:
+		return "xchacha20-poly1305"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(c))
+	}
+}
+
+// NonceSize returns the nonce length in bytes (0 for unknown ciphers)
+func (c Cipher) NonceSize() int {
+	switch c {
+	case CipherAESGCM:
+		return nonceSize
+	case CipherXChaCha20Poly1305:
+		return chacha20poly1305.NonceSizeX
+	default:
+		return 0
+	}
+}
+
+// ParseCipher parses a cipher name; empty means CipherAESGCM
+func ParseCipher(name string) (Cipher, error) {
+	switch strings.ToLower(name) {
+	case "", "aes-256-gcm", "aes-gcm":
+		return CipherAESGCM, nil
+	case "xchacha20-poly1305", "xchacha":
+		return CipherXChaCha20Poly1305, nil
+	default:
+		return 0, fmt.Errorf("unknown cipher %q (use aes-256-gcm or xchacha20-poly1305)", name)
+	}
+}
+
+// newAEAD creates the AEAD for c with a 32-byte key
+func newAEAD(c Cipher, key []byte) (cipher.AEAD, error) {
+	// Validate key size (must be 32 bytes for both ciphers)
 	if len(key) != 32 {
-		return nil, nil, fmt.Errorf("invalid key size: need 32 bytes for AES-256, got %d", len(key))
+		return nil, fmt.Errorf("invalid key size: need 32 bytes for %s, got %d", c, len(key))
 	}
 
-	// Create AES cipher block
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create cipher: %w", err)
+	switch c {
+	case CipherAESGCM:
+		// Create AES cipher block
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cipher: %w", err)
+		}
+
+		// Create GCM mode
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GCM: %w", err)
+		}
+		return gcm, nil
+	case CipherXChaCha20Poly1305:
+		aead, err := chacha20poly1305.NewX(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create XChaCha20-Poly1305: %w", err)
+		}
+		return aead, nil
+	default:
+		return nil, fmt.Errorf("unsupported cipher: %s", c)
 	}
+}
 
-	// Create GCM mode
-	gcm, err := cipher.NewGCM(block)
+// Encrypt encrypts plaintext with the given cipher (authenticated encryption)
+// Returns ciphertext (including auth tag), nonce, and error
+func Encrypt(c Cipher, plaintext, key []byte) (ciphertext, nonce []byte, err error) {
+	aead, err := newAEAD(c, key)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create GCM: %w", err)
+		return nil, nil, err
 	}
 
-	// Generate random nonce (12 bytes for GCM)
-	nonce = make([]byte, gcm.NonceSize())
-	if _, err := rand.Read(nonce); err != nil {
+	// Generate random nonce (12 bytes for GCM, 24 for XChaCha20)
+	nonce = make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(randReader, nonce); err != nil {
 		return nil, nil, fmt.Errorf("failed to generate nonce: %w", err)
 	}
 
 	// Encrypt and authenticate
-	// GCM automatically appends 16-byte authentication tag
-	ciphertext = gcm.Seal(nil, nonce, plaintext, nil)
+	// Both ciphers append a 16-byte authentication tag
+	ciphertext = aead.Seal(nil, nonce, plaintext, nil)
 
 	return ciphertext, nonce, nil
 }
 
-// Decrypt decrypts ciphertext using AES-256-GCM and verifies authentication tag
+// Decrypt decrypts ciphertext with the given cipher and verifies the authentication tag
 // Returns plaintext and error (error if authentication fails or decryption fails)
-func Decrypt(ciphertext, key, nonce []byte) (plaintext []byte, err error) {
-	// Validate key size
-	if len(key) != 32 {
-		return nil, fmt.Errorf("invalid key size: need 32 bytes for AES-256, got %d", len(key))
-	}
-
-	// Validate nonce size
-	if len(nonce) != nonceSize {
-		return nil, fmt.Errorf("invalid nonce size: need %d bytes, got %d", nonceSize, len(nonce))
-	}
-
-	// Create AES cipher block
-	block, err := aes.NewCipher(key)
+func Decrypt(c Cipher, ciphertext, key, nonce []byte) (plaintext []byte, err error) {
+	aead, err := newAEAD(c, key)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create cipher: %w", err)
+		return nil, err
 	}
 
-	// Create GCM mode
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	// Validate nonce size
+	if len(nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce size: need %d bytes, got %d", aead.NonceSize(), len(nonce))
 	}
 
 	// Decrypt and verify authentication tag
-	plaintext, err = gcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err = aead.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
 		return nil, fmt.Errorf("decryption failed (wrong key or tampered data): %w", err)
 	}
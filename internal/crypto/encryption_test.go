@@ -2,9 +2,20 @@ package crypto
 
 import (
 	"bytes"
+	"crypto/rand"
+	"errors"
 	"testing"
+	"testing/iotest"
 )
 
+// useRandBytes makes randReader return data (then EOF) for the rest of the test
+func useRandBytes(t *testing.T, data []byte) {
+	t.Helper()
+	original := randReader
+	randReader = bytes.NewReader(data)
+	t.Cleanup(func() { randReader = original })
+}
+
 // TestEncryptDecrypt tests encryption and decryption round-trip
 func TestEncryptDecrypt(t *testing.T) {
 	key := make([]byte, 32) // 256-bit key
@@ -15,7 +26,7 @@ func TestEncryptDecrypt(t *testing.T) {
 	plaintext := []byte("This is a secret message for TOTP storage")
 
 	// Encrypt
-	ciphertext, nonce, err := Encrypt(plaintext, key)
+	ciphertext, nonce, err := Encrypt(CipherAESGCM, plaintext, key)
 	if err != nil {
 		t.Fatalf("Encrypt() error = %v", err)
 	}
@@ -31,7 +42,7 @@ func TestEncryptDecrypt(t *testing.T) {
 	}
 
 	// Decrypt
-	decrypted, err := Decrypt(ciphertext, key, nonce)
+	decrypted, err := Decrypt(CipherAESGCM, ciphertext, key, nonce)
 	if err != nil {
 		t.Fatalf("Decrypt() error = %v", err)
 	}
@@ -47,14 +58,14 @@ func TestEncrypt_DifferentNonces(t *testing.T) {
 	key := make([]byte, 32)
 	plaintext := []byte("test message")
 
-	_, nonce1, err1 := Encrypt(plaintext, key)
+	_, nonce1, err1 := Encrypt(CipherAESGCM, plaintext, key)
 	if err1 != nil {
-		t.Fatalf("First Encrypt() error = %v", err1)
+		t.Fatalf("First Encrypt(CipherAESGCM, ) error = %v", err1)
 	}
 
-	_, nonce2, err2 := Encrypt(plaintext, key)
+	_, nonce2, err2 := Encrypt(CipherAESGCM, plaintext, key)
 	if err2 != nil {
-		t.Fatalf("Second Encrypt() error = %v", err2)
+		t.Fatalf("Second Encrypt(CipherAESGCM, ) error = %v", err2)
 	}
 
 	if bytes.Equal(nonce1, nonce2) {
@@ -73,13 +84,13 @@ func TestDecrypt_WrongKey(t *testing.T) {
 	plaintext := []byte("secret message")
 
 	// Encrypt with correct key
-	ciphertext, nonce, err := Encrypt(plaintext, correctKey)
+	ciphertext, nonce, err := Encrypt(CipherAESGCM, plaintext, correctKey)
 	if err != nil {
 		t.Fatalf("Encrypt() error = %v", err)
 	}
 
 	// Try to decrypt with wrong key
-	_, err = Decrypt(ciphertext, wrongKey, nonce)
+	_, err = Decrypt(CipherAESGCM, ciphertext, wrongKey, nonce)
 	if err == nil {
 		t.Error("Decrypt() should fail with wrong key, but succeeded")
 	}
@@ -91,7 +102,7 @@ func TestDecrypt_TamperedCiphertext(t *testing.T) {
 	plaintext := []byte("secret message")
 
 	// Encrypt
-	ciphertext, nonce, err := Encrypt(plaintext, key)
+	ciphertext, nonce, err := Encrypt(CipherAESGCM, plaintext, key)
 	if err != nil {
 		t.Fatalf("Encrypt() error = %v", err)
 	}
@@ -100,7 +111,7 @@ func TestDecrypt_TamperedCiphertext(t *testing.T) {
 	ciphertext[0] ^= 0xFF
 
 	// Try to decrypt tampered ciphertext
-	_, err = Decrypt(ciphertext, key, nonce)
+	_, err = Decrypt(CipherAESGCM, ciphertext, key, nonce)
 	if err == nil {
 		t.Error("Decrypt() should fail with tampered ciphertext (auth tag verification)")
 	}
@@ -128,7 +139,7 @@ func TestEncrypt_InvalidKey(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, _, err := Encrypt([]byte("test"), tt.key)
+			_, _, err := Encrypt(CipherAESGCM, []byte("test"), tt.key)
 			if err == nil {
 				t.Error("Encrypt() expected error for invalid key size, got nil")
 			}
@@ -161,7 +172,7 @@ func TestDecrypt_InvalidNonce(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := Decrypt(ciphertext, key, tt.nonce)
+			_, err := Decrypt(CipherAESGCM, ciphertext, key, tt.nonce)
 			if err == nil {
 				t.Error("Decrypt() expected error for invalid nonce, got nil")
 			}
@@ -174,7 +185,7 @@ func TestEncryptDecrypt_EmptyPlaintext(t *testing.T) {
 	key := make([]byte, 32)
 	plaintext := []byte{}
 
-	ciphertext, nonce, err := Encrypt(plaintext, key)
+	ciphertext, nonce, err := Encrypt(CipherAESGCM, plaintext, key)
 	if err != nil {
 		t.Fatalf("Encrypt() error = %v", err)
 	}
@@ -184,7 +195,7 @@ func TestEncryptDecrypt_EmptyPlaintext(t *testing.T) {
 		t.Error("Encrypt() produced empty ciphertext for empty plaintext")
 	}
 
-	decrypted, err := Decrypt(ciphertext, key, nonce)
+	decrypted, err := Decrypt(CipherAESGCM, ciphertext, key, nonce)
 	if err != nil {
 		t.Fatalf("Decrypt() error = %v", err)
 	}
@@ -204,12 +215,12 @@ func TestEncryptDecrypt_LargeData(t *testing.T) {
 		plaintext[i] = byte(i % 256)
 	}
 
-	ciphertext, nonce, err := Encrypt(plaintext, key)
+	ciphertext, nonce, err := Encrypt(CipherAESGCM, plaintext, key)
 	if err != nil {
 		t.Fatalf("Encrypt() error = %v", err)
 	}
 
-	decrypted, err := Decrypt(ciphertext, key, nonce)
+	decrypted, err := Decrypt(CipherAESGCM, ciphertext, key, nonce)
 	if err != nil {
 		t.Fatalf("Decrypt() error = %v", err)
 	}
@@ -224,7 +235,7 @@ func TestEncrypt_InvalidKeySize(t *testing.T) {
 	invalidKey := make([]byte, 16) // Wrong size (should be 32)
 	plaintext := []byte("test")
 
-	_, _, err := Encrypt(plaintext, invalidKey)
+	_, _, err := Encrypt(CipherAESGCM, plaintext, invalidKey)
 	if err == nil {
 		t.Error("Encrypt() should fail with invalid key size")
 	}
@@ -236,7 +247,7 @@ func TestDecrypt_InvalidKeySize(t *testing.T) {
 	ciphertext := []byte("dummy")
 	nonce := make([]byte, 12)
 
-	_, err := Decrypt(ciphertext, invalidKey, nonce)
+	_, err := Decrypt(CipherAESGCM, ciphertext, invalidKey, nonce)
 	if err == nil {
 		t.Error("Decrypt() should fail with invalid key size")
 	}
@@ -247,7 +258,7 @@ func TestDecrypt_TamperedNonce(t *testing.T) {
 	key := make([]byte, 32)
 	plaintext := []byte("secret message")
 
-	ciphertext, nonce, err := Encrypt(plaintext, key)
+	ciphertext, nonce, err := Encrypt(CipherAESGCM, plaintext, key)
 	if err != nil {
 		t.Fatalf("Encrypt() error = %v", err)
 	}
@@ -256,7 +267,7 @@ func TestDecrypt_TamperedNonce(t *testing.T) {
 	nonce[0] ^= 0xFF
 
 	// Try to decrypt
-	_, err = Decrypt(ciphertext, key, nonce)
+	_, err = Decrypt(CipherAESGCM, ciphertext, key, nonce)
 	if err == nil {
 		t.Error("Decrypt() should fail with tampered nonce")
 	}
@@ -267,12 +278,12 @@ func TestEncrypt_EmptyPlaintext(t *testing.T) {
 	key := make([]byte, 32)
 	plaintext := []byte{}
 
-	ciphertext, nonce, err := Encrypt(plaintext, key)
+	ciphertext, nonce, err := Encrypt(CipherAESGCM, plaintext, key)
 	if err != nil {
 		t.Fatalf("Encrypt() error = %v", err)
 	}
 
-	decrypted, err := Decrypt(ciphertext, key, nonce)
+	decrypted, err := Decrypt(CipherAESGCM, ciphertext, key, nonce)
 	if err != nil {
 		t.Fatalf("Decrypt() error = %v", err)
 	}
@@ -289,7 +300,7 @@ func BenchmarkEncrypt(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _, _ = Encrypt(plaintext, key)
+		_, _, _ = Encrypt(CipherAESGCM, plaintext, key)
 	}
 }
 
@@ -297,10 +308,146 @@ func BenchmarkEncrypt(b *testing.B) {
 func BenchmarkDecrypt(b *testing.B) {
 	key := make([]byte, 32)
 	plaintext := make([]byte, 1024)
-	ciphertext, nonce, _ := Encrypt(plaintext, key)
+	ciphertext, nonce, _ := Encrypt(CipherAESGCM, plaintext, key)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, _ = Decrypt(ciphertext, key, nonce)
+		_, _ = Decrypt(CipherAESGCM, ciphertext, key, nonce)
+	}
+}
+
+// TestEncryptDecrypt_XChaCha20Poly1305 tests the XChaCha cipher round-trips
+// with a 24-byte nonce and cannot be opened as AES-GCM
+func TestEncryptDecrypt_XChaCha20Poly1305(t *testing.T) {
+	key := make([]byte, 32)
+	plaintext := []byte("This is a secret message for TOTP storage")
+
+	ciphertext, nonce, err := Encrypt(CipherXChaCha20Poly1305, plaintext, key)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if len(nonce) != 24 {
+		t.Errorf("Nonce length = %d, want 24", len(nonce))
+	}
+
+	decrypted, err := Decrypt(CipherXChaCha20Poly1305, ciphertext, key, nonce)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("Decrypted text doesn't match original.\nWant: %s\nGot:  %s", plaintext, decrypted)
+	}
+
+	if _, err := Decrypt(CipherAESGCM, ciphertext, key, nonce); err == nil {
+		t.Error("Decrypt() with the wrong cipher should fail")
+	}
+}
+
+// TestParseCipher tests cipher names and their round-trip through String
+func TestParseCipher(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Cipher
+		wantErr bool
+	}{
+		{"", CipherAESGCM, false},
+		{"aes-256-gcm", CipherAESGCM, false},
+		{"XChaCha20-Poly1305", CipherXChaCha20Poly1305, false},
+		{"xchacha", CipherXChaCha20Poly1305, false},
+		{"des", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCipher(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCipher(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseCipher(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+			if again, _ := ParseCipher(got.String()); again != got {
+				t.Errorf("ParseCipher(%q) = %v, want %v", got.String(), again, got)
+			}
+		})
+	}
+}
+
+// TestRandReader_Production tests nonces and salts come from crypto/rand by default
+func TestRandReader_Production(t *testing.T) {
+	if randReader != rand.Reader {
+		t.Error("randReader must default to crypto/rand.Reader")
+	}
+}
+
+// TestEncrypt_DeterministicNonce tests a fixed reader yields a known nonce and
+// reproducible ciphertext
+func TestEncrypt_DeterministicNonce(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	plaintext := []byte("deterministic")
+
+	for _, c := range []Cipher{CipherAESGCM, CipherXChaCha20Poly1305} {
+		t.Run(c.String(), func(t *testing.T) {
+			wantNonce := make([]byte, c.NonceSize())
+			for i := range wantNonce {
+				wantNonce[i] = byte(i)
+			}
+
+			useRandBytes(t, wantNonce)
+			first, nonce, err := Encrypt(c, plaintext, key)
+			if err != nil {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+			if !bytes.Equal(nonce, wantNonce) {
+				t.Errorf("nonce = %x, want %x", nonce, wantNonce)
+			}
+
+			useRandBytes(t, wantNonce)
+			second, _, err := Encrypt(c, plaintext, key)
+			if err != nil {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+			if !bytes.Equal(first, second) {
+				t.Error("Encrypt() with the same nonce should produce identical ciphertext")
+			}
+
+			decrypted, err := Decrypt(c, first, key, nonce)
+			if err != nil || !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("Decrypt() = %q, %v; want %q", decrypted, err, plaintext)
+			}
+		})
+	}
+}
+
+// TestEncrypt_AESGCMKnownAnswer tests exact AES-256-GCM output for a fixed nonce
+// (NIST GCM test case 13: zero key, zero nonce, empty plaintext)
+func TestEncrypt_AESGCMKnownAnswer(t *testing.T) {
+	useRandBytes(t, make([]byte, nonceSize))
+
+	ciphertext, _, err := Encrypt(CipherAESGCM, nil, make([]byte, 32))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	wantTag := []byte{
+		0x53, 0x0f, 0x8a, 0xfb, 0xc7, 0x45, 0x36, 0xb9,
+		0xa9, 0x63, 0xb4, 0xf1, 0xc4, 0xcb, 0x73, 0x8b,
+	}
+	if !bytes.Equal(ciphertext, wantTag) {
+		t.Errorf("ciphertext = %x, want %x", ciphertext, wantTag)
+	}
+}
+
+// TestEncrypt_RandFailure tests a failing random source is reported
+func TestEncrypt_RandFailure(t *testing.T) {
+	original := randReader
+	randReader = iotest.ErrReader(errors.New("entropy exhausted"))
+	t.Cleanup(func() { randReader = original })
+
+	if _, _, err := Encrypt(CipherAESGCM, []byte("data"), make([]byte, 32)); err == nil {
+		t.Error("Encrypt() should fail when the random source fails")
 	}
 }
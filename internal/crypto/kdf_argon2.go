@@ -0,0 +1,22 @@
+//go:build !noargon2
+
+package crypto
+
+import "golang.org/x/crypto/argon2"
+
+// DefaultKDF is the KDF for new stores: Argon2id when it is built in
+const DefaultKDF = KDFArgon2id
+
+// deriveArgon2id derives the key using Argon2id (memory-hard KDF resistant
+// to GPU attacks)
+func deriveArgon2id(passphrase string, salt []byte) ([]byte, error) {
+	key := argon2.IDKey(
+		[]byte(passphrase),
+		salt,
+		time,
+		memory,
+		threads,
+		keyLength,
+	)
+	return key, nil
+}
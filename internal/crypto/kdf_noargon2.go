@@ -0,0 +1,14 @@
+//go:build noargon2
+
+package crypto
+
+import "fmt"
+
+// DefaultKDF is the KDF for new stores: scrypt, since this build has no Argon2id
+const DefaultKDF = KDFScrypt
+
+// deriveArgon2id fails: this build was made with -tags noargon2, so stores
+// using Argon2id must be opened (or re-encrypted with scrypt) elsewhere
+func deriveArgon2id(passphrase string, salt []byte) ([]byte, error) {
+	return nil, fmt.Errorf("%w: %s (built with -tags noargon2)", ErrKDFUnavailable, KDFArgon2id)
+}
@@ -0,0 +1,22 @@
+//go:build noargon2
+
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestDeriveKeyWith_NoArgon2 tests builds without Argon2id default to
+// scrypt and report Argon2id stores as unavailable
+func TestDeriveKeyWith_NoArgon2(t *testing.T) {
+	if DefaultKDF != KDFScrypt {
+		t.Errorf("DefaultKDF = %s, want scrypt", DefaultKDF)
+	}
+
+	salt := bytes.Repeat([]byte{0x01}, saltLength)
+	if _, err := DeriveKeyWith(KDFArgon2id, "test-passphrase", salt); !errors.Is(err, ErrKDFUnavailable) {
+		t.Errorf("DeriveKeyWith(argon2id) error = %v, want ErrKDFUnavailable", err)
+	}
+}
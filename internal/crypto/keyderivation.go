@@ -1,10 +1,12 @@
 package crypto
 
 import (
-	"crypto/rand"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 
-	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
 )
 
 const (
@@ -14,33 +16,91 @@ const (
 	time       = 4         // Number of iterations
 	memory     = 64 * 1024 // 64 MB memory
 	threads    = 4         // Number of parallel threads
+
+	// scrypt parameters (N*r*128 bytes = 64 MB, matching Argon2id's memory)
+	scryptN = 1 << 16
+	scryptR = 8
+	scryptP = 1
 )
 
-// DeriveKey derives a 256-bit encryption key from a passphrase using Argon2id
-// Parameters: 64MB memory, 4 iterations, 4 threads
+// KDF identifies the function deriving the storage key from the passphrase
+type KDF byte
+
+const (
+	// KDFArgon2id is Argon2id (the default wherever it is built in)
+	KDFArgon2id KDF = iota
+
+	// KDFScrypt is scrypt, the fallback for builds without Argon2id
+	// (-tags noargon2)
+	KDFScrypt
+)
+
+// ErrKDFUnavailable is returned when a KDF is not built into this binary
+var ErrKDFUnavailable = errors.New("key derivation function not available in this build")
+
+// String returns the KDF name accepted by ParseKDF
+func (k KDF) String() string {
+	switch k {
+	case KDFArgon2id:
+		return "argon2id"
+	case KDFScrypt:
+		return "scrypt"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(k))
+	}
+}
+
+// Known reports whether k is a KDF this package defines, whether or not
+// it is built in
+func (k KDF) Known() bool {
+	return k == KDFArgon2id || k == KDFScrypt
+}
+
+// ParseKDF parses a KDF name; empty means DefaultKDF
+func ParseKDF(name string) (KDF, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return DefaultKDF, nil
+	case "argon2id", "argon2":
+		return KDFArgon2id, nil
+	case "scrypt":
+		return KDFScrypt, nil
+	default:
+		return 0, fmt.Errorf("unknown kdf %q (use argon2id or scrypt)", name)
+	}
+}
+
+// DeriveKey derives a 256-bit encryption key from a passphrase with DefaultKDF
 func DeriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return DeriveKeyWith(DefaultKDF, passphrase, salt)
+}
+
+// DeriveKeyWith derives a 256-bit encryption key from a passphrase using k.
+// Argon2id: 64MB memory, 4 iterations, 4 threads; scrypt: N=2^16, r=8, p=1
+func DeriveKeyWith(k KDF, passphrase string, salt []byte) ([]byte, error) {
 	// Validate salt length
 	if len(salt) < saltLength {
 		return nil, fmt.Errorf("salt too short: need %d bytes, got %d", saltLength, len(salt))
 	}
 
-	// Derive key using Argon2id (memory-hard KDF resistant to GPU attacks)
-	key := argon2.IDKey(
-		[]byte(passphrase),
-		salt,
-		time,
-		memory,
-		threads,
-		keyLength,
-	)
-
-	return key, nil
+	switch k {
+	case KDFArgon2id:
+		return deriveArgon2id(passphrase, salt)
+	case KDFScrypt:
+		key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keyLength)
+		if err != nil {
+			return nil, fmt.Errorf("scrypt: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unknown kdf: %s", k)
+	}
 }
 
 // GenerateSalt generates a cryptographically secure random salt
 func GenerateSalt() ([]byte, error) {
 	salt := make([]byte, saltLength)
-	if _, err := rand.Read(salt); err != nil {
+	if _, err := io.ReadFull(randReader, salt); err != nil {
 		return nil, fmt.Errorf("failed to generate random salt: %w", err)
 	}
 	return salt, nil
@@ -107,6 +107,48 @@ func TestDeriveKey_InvalidSalt(t *testing.T) {
 	}
 }
 
+// TestDeriveKeyWith_Scrypt tests scrypt derivation is deterministic and
+// differs from Argon2id for the same inputs
+func TestDeriveKeyWith_Scrypt(t *testing.T) {
+	salt := bytes.Repeat([]byte{0x01}, saltLength)
+
+	key1, err := DeriveKeyWith(KDFScrypt, "test-passphrase", salt)
+	if err != nil {
+		t.Fatalf("DeriveKeyWith(scrypt) error = %v", err)
+	}
+	key2, err := DeriveKeyWith(KDFScrypt, "test-passphrase", salt)
+	if err != nil {
+		t.Fatalf("DeriveKeyWith(scrypt) error = %v", err)
+	}
+	if len(key1) != keyLength || !bytes.Equal(key1, key2) {
+		t.Errorf("scrypt keys: len %d, equal %t; want %d and true", len(key1), bytes.Equal(key1, key2), keyLength)
+	}
+
+	if argonKey, err := DeriveKeyWith(KDFArgon2id, "test-passphrase", salt); err == nil && bytes.Equal(argonKey, key1) {
+		t.Error("scrypt and Argon2id produced the same key")
+	}
+
+	if _, err := DeriveKeyWith(KDF(0xEE), "test-passphrase", salt); err == nil {
+		t.Error("DeriveKeyWith() should reject an unknown kdf")
+	}
+}
+
+// TestParseKDF tests KDF names round-trip and empty means the default
+func TestParseKDF(t *testing.T) {
+	for _, k := range []KDF{KDFArgon2id, KDFScrypt} {
+		got, err := ParseKDF(k.String())
+		if err != nil || got != k {
+			t.Errorf("ParseKDF(%q) = %v, %v; want %v", k.String(), got, err, k)
+		}
+	}
+	if got, err := ParseKDF(""); err != nil || got != DefaultKDF {
+		t.Errorf("ParseKDF(\"\") = %v, %v; want %v", got, err, DefaultKDF)
+	}
+	if _, err := ParseKDF("pbkdf2"); err == nil {
+		t.Error("ParseKDF() should reject unknown names")
+	}
+}
+
 // TestGenerateSalt tests salt generation
 func TestGenerateSalt(t *testing.T) {
 	salt, err := GenerateSalt()
@@ -223,3 +265,23 @@ func BenchmarkGenerateSalt(b *testing.B) {
 		_, _ = GenerateSalt()
 	}
 }
+
+// TestGenerateSalt_Deterministic tests the salt comes from randReader and a
+// short read is an error
+func TestGenerateSalt_Deterministic(t *testing.T) {
+	want := bytes.Repeat([]byte{0xAB}, saltLength)
+	useRandBytes(t, want)
+
+	salt, err := GenerateSalt()
+	if err != nil {
+		t.Fatalf("GenerateSalt() error = %v", err)
+	}
+	if !bytes.Equal(salt, want) {
+		t.Errorf("salt = %x, want %x", salt, want)
+	}
+
+	useRandBytes(t, want[:saltLength-1])
+	if _, err := GenerateSalt(); err == nil {
+		t.Error("GenerateSalt() should fail on a short read")
+	}
+}
@@ -0,0 +1,255 @@
+package otpauth
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MigrationScheme is the URI scheme of Google Authenticator's "Transfer
+// accounts" QR codes (otpauth-migration://offline?data=...)
+const MigrationScheme = "otpauth-migration"
+
+// Google Authenticator MigrationPayload.OtpParameters enum values
+const (
+	migrationAlgorithmSHA1   = 1
+	migrationAlgorithmSHA256 = 2
+	migrationAlgorithmSHA512 = 3
+	migrationAlgorithmMD5    = 4
+
+	migrationDigitsSix   = 1
+	migrationDigitsEight = 2
+
+	migrationTypeHOTP = 1
+	migrationTypeTOTP = 2
+)
+
+// migrationParameters is one account of a MigrationPayload
+type migrationParameters struct {
+	secret    []byte
+	name      string
+	issuer    string
+	algorithm uint64
+	digits    uint64
+	otpType   uint64
+}
+
+// IsMigrationURI reports whether uri is an otpauth-migration:// URI
+func IsMigrationURI(uri string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(uri)), MigrationScheme+"://")
+}
+
+// ParseMigration decodes a Google Authenticator otpauth-migration URI into
+// keys, one per TOTP account. Accounts this app cannot use (HOTP, MD5) are
+// left out and described in skipped instead of failing the whole batch.
+func ParseMigration(uri string) (keys []*Key, skipped []string, err error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid URI: %w", err)
+	}
+	if u.Scheme != MigrationScheme {
+		return nil, nil, fmt.Errorf("invalid URI scheme: expected %s, got %q", MigrationScheme, u.Scheme)
+	}
+
+	data := u.Query().Get("data")
+	if data == "" {
+		return nil, nil, fmt.Errorf("missing data parameter")
+	}
+	// An unescaped '+' in the query decodes as a space
+	data = strings.ReplaceAll(data, " ", "+")
+
+	payload, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		if payload, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "=")); err != nil {
+			return nil, nil, fmt.Errorf("invalid data parameter: %w", err)
+		}
+	}
+
+	accounts, err := decodeMigrationPayload(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid migration payload: %w", err)
+	}
+
+	for _, account := range accounts {
+		key, reason := account.key()
+		if key == nil {
+			skipped = append(skipped, fmt.Sprintf("%q: %s", account.displayName(), reason))
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, skipped, nil
+}
+
+// displayName identifies an account in skip messages
+func (p migrationParameters) displayName() string {
+	if p.issuer != "" && !strings.HasPrefix(p.name, p.issuer+":") {
+		return p.issuer + ":" + p.name
+	}
+	return p.name
+}
+
+// key converts the account into a Key, or returns why it can't be used
+func (p migrationParameters) key() (*Key, string) {
+	if p.otpType == migrationTypeHOTP {
+		return nil, "HOTP (counter-based) accounts are not supported"
+	}
+	if p.otpType != 0 && p.otpType != migrationTypeTOTP {
+		return nil, fmt.Sprintf("unknown OTP type %d", p.otpType)
+	}
+	if len(p.secret) == 0 {
+		return nil, "missing secret"
+	}
+
+	key := &Key{
+		Account:            strings.TrimSpace(p.name),
+		Issuer:             strings.TrimSpace(p.issuer),
+		Label:              p.name,
+		Secret:             base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(p.secret),
+		Algorithm:          DefaultAlgorithm,
+		AlgorithmDefaulted: p.algorithm == 0,
+		Digits:             DefaultDigits,
+		Period:             DefaultPeriod,
+	}
+
+	// Names are often "Issuer:account", like otpauth labels
+	if issuer, account, found := strings.Cut(p.name, ":"); found {
+		if key.Issuer == "" {
+			key.Issuer = strings.TrimSpace(issuer)
+		}
+		if strings.EqualFold(strings.TrimSpace(issuer), key.Issuer) {
+			key.Account = strings.TrimSpace(account)
+		}
+	}
+
+	switch p.algorithm {
+	case 0, migrationAlgorithmSHA1:
+	case migrationAlgorithmSHA256:
+		key.Algorithm = "SHA256"
+	case migrationAlgorithmSHA512:
+		key.Algorithm = "SHA512"
+	case migrationAlgorithmMD5:
+		return nil, "MD5 accounts are not supported"
+	default:
+		return nil, fmt.Sprintf("unknown algorithm %d", p.algorithm)
+	}
+
+	switch p.digits {
+	case 0, migrationDigitsSix:
+	case migrationDigitsEight:
+		key.Digits = 8
+	default:
+		return nil, fmt.Sprintf("unknown digit count %d", p.digits)
+	}
+
+	return key, ""
+}
+
+// errTruncated is returned for payloads that end inside a field
+var errTruncated = errors.New("truncated protobuf data")
+
+// decodeMigrationPayload reads the otp_parameters (field 1) of a
+// MigrationPayload; batch fields are ignored
+func decodeMigrationPayload(data []byte) ([]migrationParameters, error) {
+	var accounts []migrationParameters
+	err := walkProtobuf(data, func(field int, value []byte, _ uint64) error {
+		if field != 1 {
+			return nil
+		}
+		account, err := decodeMigrationParameters(value)
+		if err != nil {
+			return err
+		}
+		accounts = append(accounts, account)
+		return nil
+	})
+	return accounts, err
+}
+
+// decodeMigrationParameters reads one OtpParameters message
+func decodeMigrationParameters(data []byte) (migrationParameters, error) {
+	var p migrationParameters
+	err := walkProtobuf(data, func(field int, value []byte, number uint64) error {
+		switch field {
+		case 1:
+			p.secret = append([]byte(nil), value...)
+		case 2:
+			p.name = string(value)
+		case 3:
+			p.issuer = string(value)
+		case 4:
+			p.algorithm = number
+		case 5:
+			p.digits = number
+		case 6:
+			p.otpType = number
+		}
+		return nil
+	})
+	return p, err
+}
+
+// walkProtobuf calls fn for each field of a protobuf message: value holds
+// length-delimited contents, number holds varints. Fixed-width fields are
+// skipped; groups are rejected.
+func walkProtobuf(data []byte, fn func(field int, value []byte, number uint64) error) error {
+	for len(data) > 0 {
+		tag, n := readVarint(data)
+		if n == 0 {
+			return errTruncated
+		}
+		data = data[n:]
+
+		field, wireType := int(tag>>3), tag&7
+		switch wireType {
+		case 0: // varint
+			number, n := readVarint(data)
+			if n == 0 {
+				return errTruncated
+			}
+			data = data[n:]
+			if err := fn(field, nil, number); err != nil {
+				return err
+			}
+		case 1: // 64-bit
+			if len(data) < 8 {
+				return errTruncated
+			}
+			data = data[8:]
+		case 2: // length-delimited
+			length, n := readVarint(data)
+			if n == 0 || uint64(len(data)-n) < length {
+				return errTruncated
+			}
+			value := data[n : n+int(length)]
+			data = data[n+int(length):]
+			if err := fn(field, value, 0); err != nil {
+				return err
+			}
+		case 5: // 32-bit
+			if len(data) < 4 {
+				return errTruncated
+			}
+			data = data[4:]
+		default:
+			return fmt.Errorf("unsupported protobuf wire type %d", wireType)
+		}
+	}
+	return nil
+}
+
+// readVarint decodes a protobuf varint, returning it and the bytes used
+// (0 if data ends first or the varint is longer than 10 bytes)
+func readVarint(data []byte) (uint64, int) {
+	var value uint64
+	for i := 0; i < len(data) && i < 10; i++ {
+		value |= uint64(data[i]&0x7F) << (7 * i)
+		if data[i]&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+	return 0, 0
+}
@@ -0,0 +1,173 @@
+package otpauth
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// migrationSecret is the raw form of the Base32 secret JBSWY3DPEHPK3PXP
+var migrationSecret = []byte{0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x21, 0xde, 0xad, 0xbe, 0xef}
+
+// appendVarintField appends a varint protobuf field
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	b = appendVarint(b, uint64(field)<<3)
+	return appendVarint(b, v)
+}
+
+// appendBytesField appends a length-delimited protobuf field
+func appendBytesField(b []byte, field int, v []byte) []byte {
+	b = appendVarint(b, uint64(field)<<3|2)
+	b = appendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+// migrationAccount encodes one OtpParameters message
+func migrationAccount(name, issuer string, algorithm, digits, otpType uint64) []byte {
+	var b []byte
+	b = appendBytesField(b, 1, migrationSecret)
+	b = appendBytesField(b, 2, []byte(name))
+	if issuer != "" {
+		b = appendBytesField(b, 3, []byte(issuer))
+	}
+	b = appendVarintField(b, 4, algorithm)
+	b = appendVarintField(b, 5, digits)
+	return appendVarintField(b, 6, otpType)
+}
+
+// migrationURI wraps OtpParameters messages in a MigrationPayload URI
+func migrationURI(accounts ...[]byte) string {
+	var payload []byte
+	for _, account := range accounts {
+		payload = appendBytesField(payload, 1, account)
+	}
+	payload = appendVarintField(payload, 2, 1) // version
+	payload = appendVarintField(payload, 3, 1) // batch_size
+	return "otpauth-migration://offline?data=" + url.QueryEscape(base64.StdEncoding.EncodeToString(payload))
+}
+
+// TestParseMigration tests decoding TOTP accounts with their parameters
+func TestParseMigration(t *testing.T) {
+	uri := migrationURI(
+		migrationAccount("alice@example.com", "GitHub", migrationAlgorithmSHA1, migrationDigitsSix, migrationTypeTOTP),
+		migrationAccount("AWS:root", "", migrationAlgorithmSHA512, migrationDigitsEight, migrationTypeTOTP),
+	)
+
+	keys, skipped, err := ParseMigration(uri)
+	if err != nil {
+		t.Fatalf("ParseMigration() error = %v", err)
+	}
+	if len(skipped) != 0 {
+		t.Errorf("skipped = %v, want none", skipped)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("ParseMigration() returned %d keys, want 2", len(keys))
+	}
+
+	want := Key{
+		Issuer:    "GitHub",
+		Account:   "alice@example.com",
+		Label:     "alice@example.com",
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Algorithm: "SHA1",
+		Digits:    6,
+		Period:    30,
+	}
+	if *keys[0] != want {
+		t.Errorf("keys[0] = %+v, want %+v", *keys[0], want)
+	}
+
+	// The issuer comes from the "Issuer:account" name when the field is empty
+	aws := keys[1]
+	if aws.Issuer != "AWS" || aws.Account != "root" || aws.Algorithm != "SHA512" || aws.Digits != 8 {
+		t.Errorf("keys[1] = %+v, want issuer AWS, account root, SHA512, 8 digits", *aws)
+	}
+}
+
+// TestParseMigration_SkipsUnsupported tests HOTP and MD5 accounts are
+// reported instead of failing the batch
+func TestParseMigration_SkipsUnsupported(t *testing.T) {
+	uri := migrationURI(
+		migrationAccount("Counter", "", migrationAlgorithmSHA1, migrationDigitsSix, migrationTypeHOTP),
+		migrationAccount("Legacy", "", migrationAlgorithmMD5, migrationDigitsSix, migrationTypeTOTP),
+		migrationAccount("Okta:bob", "Okta", migrationAlgorithmSHA256, migrationDigitsSix, migrationTypeTOTP),
+	)
+
+	keys, skipped, err := ParseMigration(uri)
+	if err != nil {
+		t.Fatalf("ParseMigration() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].Issuer != "Okta" || keys[0].Account != "bob" || keys[0].Algorithm != "SHA256" {
+		t.Fatalf("keys = %+v, want only Okta:bob with SHA256", keys)
+	}
+	if len(skipped) != 2 {
+		t.Fatalf("skipped = %v, want 2 entries", skipped)
+	}
+	if !strings.Contains(skipped[0], `"Counter"`) || !strings.Contains(skipped[0], "HOTP") {
+		t.Errorf("skipped[0] = %q, want the HOTP account named", skipped[0])
+	}
+	if !strings.Contains(skipped[1], `"Legacy"`) || !strings.Contains(skipped[1], "MD5") {
+		t.Errorf("skipped[1] = %q, want the MD5 account named", skipped[1])
+	}
+}
+
+// TestParseMigration_UnescapedData tests a data parameter whose '+' was
+// not percent-encoded still decodes
+func TestParseMigration_UnescapedData(t *testing.T) {
+	uri := migrationURI(migrationAccount("alice", "GitHub", migrationAlgorithmSHA1, migrationDigitsSix, migrationTypeTOTP))
+	raw, err := url.QueryUnescape(strings.TrimPrefix(uri, "otpauth-migration://offline?data="))
+	if err != nil {
+		t.Fatalf("QueryUnescape() error = %v", err)
+	}
+
+	keys, _, err := ParseMigration("otpauth-migration://offline?data=" + raw)
+	if err != nil {
+		t.Fatalf("ParseMigration() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].Secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("keys = %+v, want one key with secret JBSWY3DPEHPK3PXP", keys)
+	}
+}
+
+// TestParseMigration_Invalid tests malformed migration URIs are rejected
+func TestParseMigration_Invalid(t *testing.T) {
+	truncated := appendBytesField(nil, 1, migrationAccount("alice", "", migrationAlgorithmSHA1, migrationDigitsSix, migrationTypeTOTP))
+	truncated = truncated[:len(truncated)-3]
+
+	tests := []struct {
+		name string
+		uri  string
+	}{
+		{"wrong scheme", "otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP"},
+		{"missing data", "otpauth-migration://offline"},
+		{"bad base64", "otpauth-migration://offline?data=%21%21%21"},
+		{"truncated payload", "otpauth-migration://offline?data=" + url.QueryEscape(base64.StdEncoding.EncodeToString(truncated))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseMigration(tt.uri); err == nil {
+				t.Errorf("ParseMigration(%q) expected error", tt.uri)
+			}
+		})
+	}
+}
+
+// TestIsMigrationURI tests scheme detection
+func TestIsMigrationURI(t *testing.T) {
+	if !IsMigrationURI("  OTPAUTH-MIGRATION://offline?data=abc") {
+		t.Error("IsMigrationURI() = false for a migration URI")
+	}
+	if IsMigrationURI("otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP") {
+		t.Error("IsMigrationURI() = true for an otpauth URI")
+	}
+}
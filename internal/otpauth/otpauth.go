@@ -0,0 +1,242 @@
+package otpauth
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+	"github.com/pavanprakash21/totp-manager-go/internal/totp"
+)
+
+// Default TOTP parameters (RFC 6238 / Google Authenticator key URI format)
+const (
+	DefaultAlgorithm = totp.DefaultAlgorithm
+	DefaultDigits    = totp.DefaultDigits
+	DefaultPeriod    = totp.DefaultPeriod
+)
+
+// Key is the content of an otpauth://totp URI with defaults applied
+type Key struct {
+	// Issuer is the provider name (e.g., "GitHub")
+	Issuer string
+
+	// Account is the account name from the label (e.g., email, username)
+	Account string
+
+	// Label is the raw, unescaped label (e.g., "GitHub:user@example.com").
+	// When set, URI emits it verbatim so the original label survives a round-trip.
+	Label string
+
+	// Secret is the Base32-encoded shared secret
+	Secret string
+
+	// Algorithm is the HMAC algorithm (SHA1, SHA256 or SHA512)
+	Algorithm string
+
+	// AlgorithmDefaulted is true when the URI had no algorithm parameter
+	// and DefaultAlgorithm was applied
+	AlgorithmDefaulted bool
+
+	// Digits is the code length
+	Digits int
+
+	// Period is the code validity window in seconds
+	Period int
+}
+
+// Parse parses an otpauth://totp URI. Optional parameters that are absent
+// are filled with their defaults so the returned Key is always complete.
+func Parse(uri string) (*Key, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, fmt.Errorf("invalid URI: %w", err)
+	}
+
+	if u.Scheme != "otpauth" {
+		return nil, fmt.Errorf("invalid URI scheme: expected otpauth, got %q", u.Scheme)
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return nil, fmt.Errorf("unsupported OTP type: %q (only totp is supported)", u.Host)
+	}
+
+	key := &Key{
+		Algorithm:          DefaultAlgorithm,
+		AlgorithmDefaulted: true,
+		Digits:             DefaultDigits,
+		Period:             DefaultPeriod,
+	}
+
+	// Label is "Issuer:Account" or just "Account". Only a literal ':'
+	// separates the two; an escaped one (%3A) belongs to the name.
+	escaped := strings.TrimPrefix(u.EscapedPath(), "/")
+	rawIssuer, rawAccount, found := strings.Cut(escaped, ":")
+	if !found {
+		rawIssuer, rawAccount = "", escaped
+	}
+	issuer, err := url.PathUnescape(rawIssuer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label: %w", err)
+	}
+	account, err := url.PathUnescape(rawAccount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label: %w", err)
+	}
+	key.Issuer = strings.TrimSpace(issuer)
+	key.Account = strings.TrimSpace(account)
+
+	// The raw label is kept only when it reads back the same way; one with
+	// a ':' inside a name is rebuilt from the escaped parts instead
+	switch {
+	case strings.Contains(issuer, ":") || (!found && strings.Contains(account, ":")):
+		key.Label = ""
+	case found:
+		key.Label = issuer + ":" + account
+	default:
+		key.Label = account
+	}
+
+	query := u.Query()
+
+	// The issuer parameter takes precedence over the label prefix
+	if issuer := query.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+
+	// Padding is optional in Base32 secrets; store the unpadded form
+	key.Secret = strings.TrimRight(strings.ToUpper(strings.ReplaceAll(query.Get("secret"), " ", "")), "=")
+	if key.Secret == "" {
+		return nil, fmt.Errorf("missing secret parameter")
+	}
+
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		key.Algorithm = strings.ToUpper(algorithm)
+		key.AlgorithmDefaulted = false
+	}
+
+	if digits := query.Get("digits"); digits != "" {
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			return nil, fmt.Errorf("invalid digits: %q", digits)
+		}
+		key.Digits = n
+	}
+
+	if period := query.Get("period"); period != "" {
+		n, err := strconv.Atoi(period)
+		if err != nil {
+			return nil, fmt.Errorf("invalid period: %q", period)
+		}
+		key.Period = n
+	}
+
+	if err := totp.ValidateParams(key.Algorithm, key.Digits, key.Period); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// ImportFromURI parses an otpauth://totp URI (as found in provider QR codes)
+// into a service created at createdAt; see Key.Service for the mapping
+func ImportFromURI(uri string, createdAt time.Time) (storage.Service, error) {
+	key, err := Parse(uri)
+	if err != nil {
+		return storage.Service{}, err
+	}
+	return key.Service(createdAt), nil
+}
+
+// Service converts the key into a service. The name is the issuer (or the
+// account when there is none); the account becomes the identifier. The
+// original issuer and label are kept for re-export. Default parameters are
+// left empty so they stay implicit.
+func (k *Key) Service(createdAt time.Time) storage.Service {
+	service := storage.Service{
+		Name:      k.Issuer,
+		Secret:    k.Secret,
+		CreatedAt: createdAt,
+		Issuer:    k.Issuer,
+		Label:     k.Label,
+	}
+	if service.Name == "" {
+		service.Name = k.Account
+	} else {
+		service.Identifier = k.Account
+	}
+
+	if !strings.EqualFold(k.Algorithm, DefaultAlgorithm) {
+		service.Algorithm = strings.ToUpper(k.Algorithm)
+	}
+	if k.Digits != DefaultDigits {
+		service.Digits = k.Digits
+	}
+	if k.Period != DefaultPeriod {
+		service.Period = k.Period
+	}
+
+	return service
+}
+
+// WithoutIssuerPrefix returns a copy labelled with just the account
+// ("alice" rather than "GitHub:alice"). The issuer stays in the issuer=
+// parameter, so authenticator apps still show it. A key with no account
+// is returned unchanged since the issuer is all its label has.
+func (k *Key) WithoutIssuerPrefix() *Key {
+	stripped := *k
+
+	account := k.Account
+	if account == "" {
+		if _, after, found := strings.Cut(k.Label, ":"); found {
+			account = strings.TrimSpace(after)
+		}
+	}
+	if account == "" {
+		return &stripped
+	}
+
+	// An explicit label stops URI from adding the issuer prefix back
+	stripped.Label = account
+	return &stripped
+}
+
+// URI formats the key as an otpauth://totp URI. Algorithm, digits and period
+// are only included when they differ from the defaults to keep URIs short.
+func (k *Key) URI() string {
+	label := escapeLabelPart(k.Account)
+	if k.Label != "" {
+		label = url.PathEscape(k.Label)
+	} else if k.Issuer != "" {
+		label = escapeLabelPart(k.Issuer) + ":" + label
+	}
+
+	query := url.Values{}
+	query.Set("secret", k.Secret)
+	if k.Issuer != "" {
+		query.Set("issuer", k.Issuer)
+	}
+	if k.Algorithm != "" && !strings.EqualFold(k.Algorithm, DefaultAlgorithm) {
+		query.Set("algorithm", strings.ToUpper(k.Algorithm))
+	}
+	if k.Digits != 0 && k.Digits != DefaultDigits {
+		query.Set("digits", strconv.Itoa(k.Digits))
+	}
+	if k.Period != 0 && k.Period != DefaultPeriod {
+		query.Set("period", strconv.Itoa(k.Period))
+	}
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Opaque:   "//totp/" + label,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// escapeLabelPart escapes an issuer or account for the label. PathEscape
+// leaves ':' alone, but in a label it would read back as the separator.
+func escapeLabelPart(part string) string {
+	return strings.ReplaceAll(url.PathEscape(part), ":", "%3A")
+}
@@ -0,0 +1,280 @@
+package otpauth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParse tests parsing a full otpauth URI
+func TestParse(t *testing.T) {
+	uri := "otpauth://totp/GitHub:user@example.com?secret=JBSWY3DPEHPK3PXP&issuer=GitHub&algorithm=SHA256&digits=8&period=60"
+
+	key, err := Parse(uri)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := Key{
+		Issuer:    "GitHub",
+		Account:   "user@example.com",
+		Label:     "GitHub:user@example.com",
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Algorithm: "SHA256",
+		Digits:    8,
+		Period:    60,
+	}
+	if *key != want {
+		t.Errorf("Parse() = %+v, want %+v", *key, want)
+	}
+}
+
+// TestParse_Defaults tests that missing optional parameters get defaults
+func TestParse_Defaults(t *testing.T) {
+	key, err := Parse("otpauth://totp/alice?secret=jbswy3dpehpk3pxp")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if key.Account != "alice" || key.Issuer != "" {
+		t.Errorf("Unexpected label parse: issuer=%q account=%q", key.Issuer, key.Account)
+	}
+	if key.Secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Secret = %q, want uppercase JBSWY3DPEHPK3PXP", key.Secret)
+	}
+	if key.Algorithm != DefaultAlgorithm || key.Digits != DefaultDigits || key.Period != DefaultPeriod {
+		t.Errorf("Defaults not applied: %+v", *key)
+	}
+	if !key.AlgorithmDefaulted {
+		t.Error("AlgorithmDefaulted should be true when the URI has no algorithm")
+	}
+}
+
+// TestParse_PaddedSecret tests that "=" padding is stripped from the secret
+func TestParse_PaddedSecret(t *testing.T) {
+	key, err := Parse("otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXPJBSW%3D%3D%3D%3D")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if key.Secret != "JBSWY3DPEHPK3PXPJBSW" {
+		t.Errorf("Secret = %q, want unpadded JBSWY3DPEHPK3PXPJBSW", key.Secret)
+	}
+}
+
+// TestParse_Invalid tests rejection of malformed URIs
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+	}{
+		{"Wrong scheme", "https://totp/GitHub?secret=JBSWY3DPEHPK3PXP"},
+		{"HOTP type", "otpauth://hotp/GitHub?secret=JBSWY3DPEHPK3PXP&counter=1"},
+		{"Missing secret", "otpauth://totp/GitHub"},
+		{"Unknown algorithm", "otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP&algorithm=MD5"},
+		{"Digits out of range", "otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP&digits=4"},
+		{"Non-numeric period", "otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP&period=abc"},
+		{"Zero period", "otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP&period=0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.uri); err == nil {
+				t.Errorf("Parse(%q) should fail", tt.uri)
+			}
+		})
+	}
+}
+
+// TestURI_NonDefaultRoundTrip tests a non-default key round-trips exactly
+func TestURI_NonDefaultRoundTrip(t *testing.T) {
+	key := Key{
+		Issuer:    "Acme Corp",
+		Account:   "user@example.com",
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Algorithm: "SHA512",
+		Digits:    8,
+		Period:    60,
+	}
+
+	uri := key.URI()
+	for _, param := range []string{"algorithm=SHA512", "digits=8", "period=60"} {
+		if !strings.Contains(uri, param) {
+			t.Errorf("URI %q should contain %q", uri, param)
+		}
+	}
+
+	parsed, err := Parse(uri)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	key.Label = "Acme Corp:user@example.com"
+	if *parsed != key {
+		t.Errorf("Round-trip mismatch: got %+v, want %+v", *parsed, key)
+	}
+}
+
+// TestURI_ColonInLabelParts tests a ':' inside an issuer or account is
+// escaped so it isn't read back as the issuer separator
+func TestURI_ColonInLabelParts(t *testing.T) {
+	tests := []struct {
+		name string
+		key  Key
+	}{
+		{"account without issuer", Key{Account: "host:alice", Secret: "JBSWY3DPEHPK3PXP"}},
+		{"account with issuer", Key{Issuer: "Acme", Account: "host:alice", Secret: "JBSWY3DPEHPK3PXP"}},
+		{"issuer", Key{Issuer: "Acme:EU", Account: "alice", Secret: "JBSWY3DPEHPK3PXP"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uri := tt.key.URI()
+			parsed, err := Parse(uri)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", uri, err)
+			}
+			if parsed.Issuer != tt.key.Issuer || parsed.Account != tt.key.Account {
+				t.Errorf("Parse(%q) issuer %q account %q, want %q and %q", uri, parsed.Issuer, parsed.Account, tt.key.Issuer, tt.key.Account)
+			}
+
+			// The parsed key formats back to the same parts
+			again, err := Parse(parsed.URI())
+			if err != nil {
+				t.Fatalf("Parse(URI()) error = %v", err)
+			}
+			if *again != *parsed {
+				t.Errorf("Round-trip mismatch: got %+v, want %+v", *again, *parsed)
+			}
+		})
+	}
+}
+
+// TestURI_DefaultMinimal tests a default key produces a minimal URI that round-trips
+func TestURI_DefaultMinimal(t *testing.T) {
+	key := Key{
+		Issuer:    "GitHub",
+		Account:   "octocat",
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Algorithm: DefaultAlgorithm,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}
+
+	uri := key.URI()
+	want := "otpauth://totp/GitHub:octocat?issuer=GitHub&secret=JBSWY3DPEHPK3PXP"
+	if uri != want {
+		t.Errorf("URI() = %q, want %q", uri, want)
+	}
+
+	parsed, err := Parse(uri)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	key.Label = "GitHub:octocat"
+	key.AlgorithmDefaulted = true // the default algorithm is omitted from the URI
+	if *parsed != key {
+		t.Errorf("Round-trip mismatch: got %+v, want %+v", *parsed, key)
+	}
+}
+
+// TestURI_PreservesRawLabel tests that a raw label is emitted verbatim even
+// when it disagrees with the issuer parameter
+func TestURI_PreservesRawLabel(t *testing.T) {
+	uri := "otpauth://totp/Old%20Name:alice?secret=JBSWY3DPEHPK3PXP&issuer=NewCo"
+
+	key, err := Parse(uri)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if key.Label != "Old Name:alice" || key.Issuer != "NewCo" {
+		t.Fatalf("Parse() label %q issuer %q, want \"Old Name:alice\" and NewCo", key.Label, key.Issuer)
+	}
+
+	parsed, err := Parse(key.URI())
+	if err != nil {
+		t.Fatalf("Parse(URI()) error = %v", err)
+	}
+	if *parsed != *key {
+		t.Errorf("Round-trip mismatch: got %+v, want %+v", *parsed, *key)
+	}
+}
+
+// TestWithoutIssuerPrefix tests the label drops the issuer but issuer= stays
+func TestWithoutIssuerPrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       Key
+		wantLabel string
+	}{
+		{"issuer and account", Key{Issuer: "GitHub", Account: "alice", Secret: "JBSWY3DPEHPK3PXP"}, "alice"},
+		{"raw label", Key{Issuer: "GitHub", Label: "GitHub:alice", Secret: "JBSWY3DPEHPK3PXP"}, "alice"},
+		{"issuer only", Key{Issuer: "GitHub", Secret: "JBSWY3DPEHPK3PXP"}, "GitHub:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.key
+			stripped := tt.key.WithoutIssuerPrefix()
+
+			parsed, err := Parse(stripped.URI())
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if parsed.Label != tt.wantLabel {
+				t.Errorf("label = %q, want %q", parsed.Label, tt.wantLabel)
+			}
+			if parsed.Issuer != "GitHub" {
+				t.Errorf("issuer = %q, want GitHub from the issuer= parameter", parsed.Issuer)
+			}
+			if tt.key != original {
+				t.Error("WithoutIssuerPrefix must not modify the original key")
+			}
+		})
+	}
+}
+
+// TestKey_Service tests label and parameter mapping
+func TestKey_Service(t *testing.T) {
+	key := &Key{Account: "alice", Secret: "JBSWY3DPEHPK3PXP", Algorithm: "SHA1", Digits: 6, Period: 30}
+	service := key.Service(time.Now())
+	if service.Name != "alice" || service.Identifier != "" {
+		t.Errorf("Without issuer: name %q identifier %q, want alice and empty", service.Name, service.Identifier)
+	}
+	if service.Algorithm != "" || service.Digits != 0 || service.Period != 0 {
+		t.Errorf("Default params should stay implicit, got %s/%d/%d", service.Algorithm, service.Digits, service.Period)
+	}
+
+	key = &Key{Issuer: "AWS", Account: "root", Secret: "JBSWY3DPEHPK3PXP", Algorithm: "sha512", Digits: 8, Period: 60}
+	service = key.Service(time.Now())
+	if service.Name != "AWS" || service.Identifier != "root" {
+		t.Errorf("With issuer: name %q identifier %q, want AWS and root", service.Name, service.Identifier)
+	}
+	if service.Algorithm != "SHA512" || service.Digits != 8 || service.Period != 60 {
+		t.Errorf("Params = %s/%d/%d, want SHA512/8/60", service.Algorithm, service.Digits, service.Period)
+	}
+}
+
+// TestImportFromURI tests a full URI becomes a service with its parameters
+// and provenance, and invalid URIs are rejected
+func TestImportFromURI(t *testing.T) {
+	createdAt := time.Unix(1700000000, 0)
+	service, err := ImportFromURI("otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP&issuer=GitHub&digits=8&period=60&algorithm=sha256", createdAt)
+	if err != nil {
+		t.Fatalf("ImportFromURI() error = %v", err)
+	}
+	if service.Name != "GitHub" || service.Identifier != "octocat" || service.Secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Service = %q/%q/%q, want GitHub/octocat/JBSWY3DPEHPK3PXP", service.Name, service.Identifier, service.Secret)
+	}
+	if service.Algorithm != "SHA256" || service.Digits != 8 || service.Period != 60 {
+		t.Errorf("Params = %s/%d/%d, want SHA256/8/60", service.Algorithm, service.Digits, service.Period)
+	}
+	if service.Label != "GitHub:octocat" || !service.CreatedAt.Equal(createdAt) {
+		t.Errorf("Label %q created %v, want GitHub:octocat and %v", service.Label, service.CreatedAt, createdAt)
+	}
+	if err := service.Validate(); err != nil {
+		t.Errorf("Imported service should validate: %v", err)
+	}
+
+	if _, err := ImportFromURI("otpauth://totp/GitHub?digits=8", createdAt); err == nil {
+		t.Error("ImportFromURI() should reject a URI without a secret")
+	}
+}
@@ -0,0 +1,48 @@
+package qr
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg" // register JPEG decoder
+	_ "image/png"  // register PNG decoder
+	"os"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// Decode reads a QR code from a PNG or JPEG image file and returns its text
+func Decode(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open image: %w", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image (PNG or JPEG expected): %w", err)
+	}
+
+	return DecodeImage(img)
+}
+
+// DecodeImage decodes a QR code from an already-loaded image
+func DecodeImage(img image.Image) (string, error) {
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	// TRY_HARDER helps with screenshots where the code is small or off-center
+	hints := map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_TRY_HARDER: true,
+	}
+
+	result, err := qrcode.NewQRCodeReader().Decode(bitmap, hints)
+	if err != nil {
+		return "", fmt.Errorf("no QR code found in image: %w", err)
+	}
+
+	return result.GetText(), nil
+}
@@ -0,0 +1,76 @@
+package qr
+
+import (
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+const testURI = "otpauth://totp/GitHub:user@example.com?issuer=GitHub&secret=JBSWY3DPEHPK3PXP"
+
+// writeQRImage encodes content as a QR code image file (PNG or JPEG by extension)
+func writeQRImage(t *testing.T, path, content string) {
+	t.Helper()
+
+	matrix, err := qrcode.NewQRCodeWriter().Encode(content, gozxing.BarcodeFormat_QR_CODE, 256, 256, nil)
+	if err != nil {
+		t.Fatalf("Failed to encode QR: %v", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create image file: %v", err)
+	}
+	defer f.Close()
+
+	if filepath.Ext(path) == ".jpg" {
+		err = jpeg.Encode(f, matrix, nil)
+	} else {
+		err = png.Encode(f, matrix)
+	}
+	if err != nil {
+		t.Fatalf("Failed to write image: %v", err)
+	}
+}
+
+// TestDecode tests decoding PNG and JPEG QR images
+func TestDecode(t *testing.T) {
+	for _, name := range []string{"code.png", "code.jpg"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			writeQRImage(t, path, testURI)
+
+			text, err := Decode(path)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if text != testURI {
+				t.Errorf("Decode() = %q, want %q", text, testURI)
+			}
+		})
+	}
+}
+
+// TestDecode_MissingFile tests a missing file returns an error
+func TestDecode_MissingFile(t *testing.T) {
+	if _, err := Decode(filepath.Join(t.TempDir(), "missing.png")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+// TestDecode_NotAnImage tests a non-image file returns an error
+func TestDecode_NotAnImage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.png")
+	if err := os.WriteFile(path, []byte("not an image"), 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := Decode(path); err == nil {
+		t.Error("Expected error for non-image file")
+	}
+}
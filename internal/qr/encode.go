@@ -0,0 +1,39 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// DefaultSize is the default side length in pixels of encoded QR images
+const DefaultSize = 256
+
+// Encode renders text as a square QR code image of at least size pixels
+// (the writer grows it if the code needs more modules than fit)
+func Encode(text string, size int) (image.Image, error) {
+	matrix, err := qrcode.NewQRCodeWriter().Encode(text, gozxing.BarcodeFormat_QR_CODE, size, size, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	// BitMatrix is an image.Image (set modules are black)
+	return matrix, nil
+}
+
+// EncodePNG renders text as a QR code and returns it as PNG data
+func EncodePNG(text string, size int) ([]byte, error) {
+	img, err := Encode(text, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to write PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,38 @@
+package qr
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+// TestEncodePNG_RoundTrip tests an encoded PNG decodes back to the same text
+func TestEncodePNG_RoundTrip(t *testing.T) {
+	data, err := EncodePNG(testURI, DefaultSize)
+	if err != nil {
+		t.Fatalf("EncodePNG() error = %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() < DefaultSize || bounds.Dy() < DefaultSize {
+		t.Errorf("image size = %v, want at least %dx%d", bounds.Size(), DefaultSize, DefaultSize)
+	}
+
+	text, err := DecodeImage(img)
+	if err != nil {
+		t.Fatalf("DecodeImage() error = %v", err)
+	}
+	if text != testURI {
+		t.Errorf("DecodeImage() = %q, want %q", text, testURI)
+	}
+}
+
+// TestEncode_Empty tests empty text is rejected
+func TestEncode_Empty(t *testing.T) {
+	if _, err := Encode("", DefaultSize); err == nil {
+		t.Error("Encode() should fail for empty text")
+	}
+}
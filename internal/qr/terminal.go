@@ -0,0 +1,51 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// terminalMargin is the quiet zone in modules around terminal QR codes;
+// smaller than the spec's 4 to save rows, which phone scanners tolerate
+const terminalMargin = 2
+
+// Terminal renders text as a QR code of Unicode half blocks, two module rows
+// per line. Light modules are drawn with the foreground color, which suits
+// the usual light-on-dark terminal; invert draws dark modules instead, for
+// dark-on-light terminals.
+func Terminal(text string, invert bool) (string, error) {
+	hints := map[gozxing.EncodeHintType]interface{}{gozxing.EncodeHintType_MARGIN: terminalMargin}
+	matrix, err := qrcode.NewQRCodeWriter().Encode(text, gozxing.BarcodeFormat_QR_CODE, 0, 0, hints)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	width, height := matrix.GetWidth(), matrix.GetHeight()
+	// drawn reports whether a module is drawn in the foreground color; rows
+	// past the bottom edge count as light quiet zone
+	drawn := func(x, y int) bool {
+		dark := y < height && matrix.Get(x, y)
+		return dark == invert
+	}
+
+	var b strings.Builder
+	for y := 0; y < height; y += 2 {
+		for x := 0; x < width; x++ {
+			switch top, bottom := drawn(x, y), drawn(x, y+1); {
+			case top && bottom:
+				b.WriteRune('█')
+			case top:
+				b.WriteRune('▀')
+			case bottom:
+				b.WriteRune('▄')
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
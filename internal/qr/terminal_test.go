@@ -0,0 +1,61 @@
+package qr
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// terminalImage turns half-block output back into a bitmap, scale pixels
+// per module, treating drawn cells as light unless invert is set
+func terminalImage(out string, invert bool, scale int) image.Image {
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	width := len([]rune(lines[0]))
+	img := image.NewGray(image.Rect(0, 0, width*scale, len(lines)*2*scale))
+
+	set := func(x, y int, drawn bool) {
+		c := color.Gray{Y: 0xFF}
+		if drawn == invert {
+			c = color.Gray{}
+		}
+		for dy := 0; dy < scale; dy++ {
+			for dx := 0; dx < scale; dx++ {
+				img.SetGray(x*scale+dx, y*scale+dy, c)
+			}
+		}
+	}
+
+	for row, line := range lines {
+		for x, r := range []rune(line) {
+			set(x, row*2, r == '█' || r == '▀')
+			set(x, row*2+1, r == '█' || r == '▄')
+		}
+	}
+	return img
+}
+
+// TestTerminal_RoundTrip tests both renderings decode back to the same text
+func TestTerminal_RoundTrip(t *testing.T) {
+	for _, invert := range []bool{false, true} {
+		out, err := Terminal(testURI, invert)
+		if err != nil {
+			t.Fatalf("Terminal(invert=%v) error = %v", invert, err)
+		}
+
+		text, err := DecodeImage(terminalImage(out, invert, 4))
+		if err != nil {
+			t.Fatalf("DecodeImage(invert=%v) error = %v", invert, err)
+		}
+		if text != testURI {
+			t.Errorf("DecodeImage(invert=%v) = %q, want %q", invert, text, testURI)
+		}
+	}
+}
+
+// TestTerminal_Empty tests empty text is rejected
+func TestTerminal_Empty(t *testing.T) {
+	if _, err := Terminal("", false); err == nil {
+		t.Error("Terminal() should fail for empty text")
+	}
+}
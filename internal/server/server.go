@@ -0,0 +1,211 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// tokenBytes is the size of generated API tokens and session IDs
+// (hex-encoded for transport)
+const tokenBytes = 32
+
+// SessionHeader carries the session ID returned by POST /v1/sessions
+const SessionHeader = "X-TOTP-Session"
+
+// maxRequestBody bounds request bodies (only the unlock request has one)
+const maxRequestBody = 4096
+
+// UnlockFunc decrypts the store with a passphrase
+type UnlockFunc func(passphrase string) (*storage.Store, error)
+
+// Server is a minimal local HTTP API for integrations (e.g., a browser
+// extension helper). Every request must carry the bearer token, and it only
+// ever listens on a loopback address. The store stays locked until a client
+// opens a session with the passphrase; sessions re-lock after an idle TTL.
+// Fetching a code does not record a use.
+type Server struct {
+	unlock   UnlockFunc
+	token    string
+	sessions *SessionManager
+	now      func() time.Time // clock (overridable in tests)
+}
+
+// unlockRequest is the JSON body of POST /v1/sessions
+type unlockRequest struct {
+	Passphrase string `json:"passphrase"`
+}
+
+// sessionResponse is the JSON body of a successful unlock
+type sessionResponse struct {
+	Session   string `json:"session"`
+	ExpiresIn int    `json:"expires_in"` // idle seconds before the session re-locks
+}
+
+// codeResponse is the JSON body of a successful code fetch
+type codeResponse struct {
+	Name      string `json:"name"`
+	Code      string `json:"code"`
+	ExpiresIn int    `json:"expires_in"`
+}
+
+// errorResponse is the JSON body of a failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// New creates a server that unlocks the store per session, authenticating
+// requests with token
+func New(unlock UnlockFunc, token string, sessions *SessionManager) *Server {
+	return &Server{
+		unlock:   unlock,
+		token:    token,
+		sessions: sessions,
+		now:      time.Now,
+	}
+}
+
+// GenerateToken returns a random hex token for authenticating API requests
+func GenerateToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Handler returns the API routes, all behind token authentication:
+//
+//	POST   /v1/sessions      unlock with {"passphrase": ...}, returns a session ID
+//	DELETE /v1/sessions      lock the session in the X-TOTP-Session header
+//	GET    /v1/codes/{name}  current code for a service (needs a session)
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/sessions", s.handleUnlock)
+	mux.HandleFunc("DELETE /v1/sessions", s.handleLock)
+	mux.HandleFunc("GET /v1/codes/{name}", s.handleCode)
+	return s.requireToken(mux)
+}
+
+// Listen validates that addr is a loopback address and listens on it
+func Listen(addr string) (net.Listener, error) {
+	if err := ValidateLoopback(addr); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	// "localhost" could resolve to something else; check what was bound
+	if tcp, ok := listener.Addr().(*net.TCPAddr); !ok || !tcp.IP.IsLoopback() {
+		listener.Close()
+		return nil, fmt.Errorf("refusing to serve on non-loopback address %s", listener.Addr())
+	}
+
+	return listener, nil
+}
+
+// ValidateLoopback checks addr is host:port with a loopback host. An empty
+// host (e.g., ":8080") would bind every interface and is refused.
+func ValidateLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+
+	if strings.EqualFold(host, "localhost") {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("refusing non-loopback address %q: use 127.0.0.1 or [::1]", addr)
+}
+
+// requireToken rejects requests without the bearer token
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "invalid or missing token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleUnlock decrypts the store with the posted passphrase and opens a session
+func (s *Server) handleUnlock(w http.ResponseWriter, r *http.Request) {
+	var req unlockRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil || req.Passphrase == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "expected JSON body with a passphrase"})
+		return
+	}
+
+	store, err := s.unlock(req.Passphrase)
+	if err != nil {
+		writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "unlock failed"})
+		return
+	}
+
+	id, err := s.sessions.Create(store)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "failed to create session"})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, sessionResponse{Session: id, ExpiresIn: int(s.sessions.ttl / time.Second)})
+}
+
+// handleLock ends the caller's session
+func (s *Server) handleLock(w http.ResponseWriter, r *http.Request) {
+	s.sessions.Delete(r.Header.Get(SessionHeader))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleCode returns the current code for the named service
+func (s *Server) handleCode(w http.ResponseWriter, r *http.Request) {
+	store, ok := s.sessions.Get(r.Header.Get(SessionHeader))
+	if !ok {
+		writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "session expired or missing: unlock again"})
+		return
+	}
+
+	service, err := store.GetServiceCopy(r.PathValue("name"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: err.Error()})
+		return
+	}
+
+	now := s.now()
+	code, err := service.Code(now)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "failed to generate code"})
+		return
+	}
+
+	period := int64(service.EffectivePeriod())
+	writeJSON(w, http.StatusOK, codeResponse{
+		Name:      service.Name,
+		Code:      code,
+		ExpiresIn: int(period - now.Unix()%period),
+	})
+}
+
+// writeJSON writes v as an uncacheable JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
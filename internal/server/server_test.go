@@ -0,0 +1,290 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+const (
+	testToken      = "test-token"
+	testPassphrase = "test-passphrase"
+)
+
+// testClock is a settable clock shared by a server and its session manager
+type testClock struct {
+	at time.Time
+}
+
+func (c *testClock) now() time.Time { return c.at }
+
+// newTestServer returns a locked server over a store with one service, a
+// one-minute session TTL and a fixed clock
+func newTestServer(t *testing.T) (*Server, *storage.Store, *testClock) {
+	t.Helper()
+
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+	unlock := func(passphrase string) (*storage.Store, error) {
+		if passphrase != testPassphrase {
+			return nil, errors.New("authentication failed")
+		}
+		return store, nil
+	}
+
+	clock := &testClock{at: time.Unix(1700000020, 0)}
+	sessions := NewSessionManager(time.Minute)
+	sessions.now = clock.now
+	server := New(unlock, testToken, sessions)
+	server.now = clock.now
+	return server, store, clock
+}
+
+// request performs a request against the server's handler with an optional
+// token and session
+func request(t *testing.T, server *Server, method, path, token, session, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if session != "" {
+		req.Header.Set(SessionHeader, session)
+	}
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+// unlockSession opens a session with the test passphrase and returns its ID
+func unlockSession(t *testing.T, server *Server) string {
+	t.Helper()
+
+	rec := request(t, server, http.MethodPost, "/v1/sessions", testToken, "", `{"passphrase":"`+testPassphrase+`"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Unlock status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+
+	var body sessionResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if body.Session == "" || body.ExpiresIn != 60 {
+		t.Fatalf("Unlock body = %+v, want a session expiring in 60s", body)
+	}
+	return body.Session
+}
+
+// TestHandler_FetchCode tests a valid token and session fetch the current code
+func TestHandler_FetchCode(t *testing.T) {
+	server, store, clock := newTestServer(t)
+	session := unlockSession(t, server)
+
+	rec := request(t, server, http.MethodGet, "/v1/codes/github", testToken, session, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+
+	var body codeResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+
+	want, err := store.Services[0].Code(clock.at)
+	if err != nil {
+		t.Fatalf("Code() error = %v", err)
+	}
+	if body.Name != "GitHub" || body.Code != want || body.ExpiresIn != 20 {
+		t.Errorf("Body = %+v, want GitHub/%s/20", body, want)
+	}
+}
+
+// TestHandler_Auth tests missing and wrong tokens are rejected
+func TestHandler_Auth(t *testing.T) {
+	server, _, _ := newTestServer(t)
+	session := unlockSession(t, server)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"Missing token", ""},
+		{"Wrong token", "not-the-token"},
+		{"Token prefix", testToken[:4]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := request(t, server, http.MethodGet, "/v1/codes/GitHub", tt.token, session, "")
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("Status = %d, want 401", rec.Code)
+			}
+			if containsCode(rec.Body.String()) {
+				t.Errorf("Unauthorized response leaked a code: %s", rec.Body)
+			}
+		})
+	}
+}
+
+// TestHandler_RequiresSession tests codes are refused while locked
+func TestHandler_RequiresSession(t *testing.T) {
+	server, _, _ := newTestServer(t)
+
+	for _, session := range []string{"", "unknown-session"} {
+		rec := request(t, server, http.MethodGet, "/v1/codes/GitHub", testToken, session, "")
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Session %q: status = %d, want 401", session, rec.Code)
+		}
+		if containsCode(rec.Body.String()) {
+			t.Errorf("Locked response leaked a code: %s", rec.Body)
+		}
+	}
+}
+
+// TestHandler_Unlock tests bad unlock requests open no session
+func TestHandler_Unlock(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"Wrong passphrase", `{"passphrase":"wrong"}`, http.StatusUnauthorized},
+		{"Empty passphrase", `{"passphrase":""}`, http.StatusBadRequest},
+		{"Not JSON", `passphrase`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _, _ := newTestServer(t)
+			rec := request(t, server, http.MethodPost, "/v1/sessions", testToken, "", tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if server.sessions.Len() != 0 {
+				t.Errorf("Sessions = %d, want 0", server.sessions.Len())
+			}
+		})
+	}
+}
+
+// TestHandler_SessionExpiry tests a fetch after the idle TTL returns 401
+func TestHandler_SessionExpiry(t *testing.T) {
+	server, _, clock := newTestServer(t)
+	session := unlockSession(t, server)
+
+	// Use within the TTL extends it
+	clock.at = clock.at.Add(50 * time.Second)
+	if rec := request(t, server, http.MethodGet, "/v1/codes/GitHub", testToken, session, ""); rec.Code != http.StatusOK {
+		t.Fatalf("Status within TTL = %d, want 200", rec.Code)
+	}
+
+	clock.at = clock.at.Add(time.Minute)
+	rec := request(t, server, http.MethodGet, "/v1/codes/GitHub", testToken, session, "")
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Status after TTL = %d, want 401", rec.Code)
+	}
+	if containsCode(rec.Body.String()) {
+		t.Errorf("Expired response leaked a code: %s", rec.Body)
+	}
+}
+
+// TestHandler_Lock tests DELETE /v1/sessions locks immediately
+func TestHandler_Lock(t *testing.T) {
+	server, _, _ := newTestServer(t)
+	session := unlockSession(t, server)
+
+	if rec := request(t, server, http.MethodDelete, "/v1/sessions", testToken, session, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("Lock status = %d, want 204", rec.Code)
+	}
+	if rec := request(t, server, http.MethodGet, "/v1/codes/GitHub", testToken, session, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Status after lock = %d, want 401", rec.Code)
+	}
+}
+
+// TestHandler_UnknownService tests an unknown name is a 404
+func TestHandler_UnknownService(t *testing.T) {
+	server, _, _ := newTestServer(t)
+	session := unlockSession(t, server)
+
+	if rec := request(t, server, http.MethodGet, "/v1/codes/Missing", testToken, session, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Status = %d, want 404", rec.Code)
+	}
+}
+
+// TestValidateLoopback tests only loopback hosts are accepted
+func TestValidateLoopback(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{"127.0.0.1:0", false},
+		{"127.0.0.2:8080", false},
+		{"[::1]:8080", false},
+		{"localhost:8080", false},
+		{":8080", true},
+		{"0.0.0.0:8080", true},
+		{"192.168.1.10:8080", true},
+		{"example.com:80", true},
+		{"127.0.0.1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			err := ValidateLoopback(tt.addr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLoopback(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestListen tests listening on an ephemeral loopback port
+func TestListen(t *testing.T) {
+	listener, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	if _, err := Listen("0.0.0.0:0"); err == nil {
+		t.Error("Listen() should refuse a wildcard address")
+	}
+}
+
+// TestGenerateToken tests tokens are random 64-character hex strings
+func TestGenerateToken(t *testing.T) {
+	a, err := GenerateToken()
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	b, _ := GenerateToken()
+	if len(a) != 2*tokenBytes || a == b {
+		t.Errorf("GenerateToken() = %q, %q: want distinct %d-char tokens", a, b, 2*tokenBytes)
+	}
+}
+
+// containsCode reports whether a response body includes a "code" field
+func containsCode(body string) bool {
+	var v map[string]any
+	if json.Unmarshal([]byte(body), &v) != nil {
+		return false
+	}
+	_, ok := v["code"]
+	return ok
+}
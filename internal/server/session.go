@@ -0,0 +1,111 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+	"github.com/pavanprakash21/totp-manager-go/internal/totp"
+)
+
+// DefaultSessionTTL is how long an unlocked session may sit idle before it re-locks
+const DefaultSessionTTL = 5 * time.Minute
+
+// purgeCodes drops cached codes once a session locks, since the code cache
+// would otherwise outlive the store (overridable in tests)
+var purgeCodes = totp.Purge
+
+// SessionManager holds unlocked stores per session and drops them once idle
+// for longer than the TTL, so decrypted secrets are not kept indefinitely
+type SessionManager struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	now      func() time.Time // clock (overridable in tests)
+	sessions map[string]*session
+}
+
+// session is one unlocked store and when it re-locks
+type session struct {
+	store   *storage.Store
+	expires time.Time
+}
+
+// NewSessionManager creates a session manager with the given idle TTL
+func NewSessionManager(ttl time.Duration) *SessionManager {
+	return &SessionManager{
+		ttl:      ttl,
+		now:      time.Now,
+		sessions: make(map[string]*session),
+	}
+}
+
+// Create starts a session for an unlocked store and returns its ID
+func (m *SessionManager) Create(store *storage.Store) (string, error) {
+	id, err := GenerateToken()
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[id] = &session{store: store, expires: m.now().Add(m.ttl)}
+	return id, nil
+}
+
+// Get returns the session's store and extends its idle timeout. Expired
+// sessions are removed and reported as missing.
+func (m *SessionManager) Get(id string) (*storage.Store, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, false
+	}
+
+	now := m.now()
+	if !now.Before(s.expires) {
+		delete(m.sessions, id)
+		purgeCodes()
+		return nil, false
+	}
+
+	s.expires = now.Add(m.ttl)
+	return s.store, true
+}
+
+// Delete ends a session immediately (explicit lock)
+func (m *SessionManager) Delete(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sessions[id]; ok {
+		delete(m.sessions, id)
+		purgeCodes()
+	}
+}
+
+// Sweep removes every expired session; run it periodically so idle stores
+// are released even if no further request arrives
+func (m *SessionManager) Sweep() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	expired := false
+	for id, s := range m.sessions {
+		if !now.Before(s.expires) {
+			delete(m.sessions, id)
+			expired = true
+		}
+	}
+	if expired {
+		purgeCodes()
+	}
+}
+
+// Len returns the number of live (not yet swept) sessions
+func (m *SessionManager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// newTestSessions returns a session manager with a settable clock
+func newTestSessions(ttl time.Duration) (*SessionManager, *testClock) {
+	clock := &testClock{at: time.Unix(1700000000, 0)}
+	sessions := NewSessionManager(ttl)
+	sessions.now = clock.now
+	return sessions, clock
+}
+
+// TestSessionManager_Expiry tests a session expires once idle for the TTL
+func TestSessionManager_Expiry(t *testing.T) {
+	sessions, clock := newTestSessions(time.Minute)
+	store := &storage.Store{}
+
+	id, err := sessions.Create(store)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	clock.at = clock.at.Add(59 * time.Second)
+	if got, ok := sessions.Get(id); !ok || got != store {
+		t.Fatal("Get() before TTL should return the store")
+	}
+
+	// The Get above reset the idle timer
+	clock.at = clock.at.Add(59 * time.Second)
+	if _, ok := sessions.Get(id); !ok {
+		t.Fatal("Get() should extend the session")
+	}
+
+	clock.at = clock.at.Add(time.Minute)
+	if _, ok := sessions.Get(id); ok {
+		t.Error("Get() after TTL should report the session missing")
+	}
+	if sessions.Len() != 0 {
+		t.Errorf("Len() = %d, want 0 after expiry", sessions.Len())
+	}
+}
+
+// TestSessionManager_Sweep tests Sweep drops only expired sessions
+func TestSessionManager_Sweep(t *testing.T) {
+	sessions, clock := newTestSessions(time.Minute)
+
+	old, _ := sessions.Create(&storage.Store{})
+	clock.at = clock.at.Add(30 * time.Second)
+	fresh, _ := sessions.Create(&storage.Store{})
+
+	clock.at = clock.at.Add(30 * time.Second)
+	sessions.Sweep()
+
+	if sessions.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", sessions.Len())
+	}
+	if _, ok := sessions.Get(old); ok {
+		t.Error("Expired session should have been swept")
+	}
+	if _, ok := sessions.Get(fresh); !ok {
+		t.Error("Live session should survive Sweep()")
+	}
+}
+
+// TestSessionManager_Delete tests explicit locking
+func TestSessionManager_Delete(t *testing.T) {
+	sessions, _ := newTestSessions(time.Minute)
+
+	id, _ := sessions.Create(&storage.Store{})
+	sessions.Delete(id)
+	sessions.Delete("unknown") // no-op
+
+	if _, ok := sessions.Get(id); ok {
+		t.Error("Deleted session should be gone")
+	}
+}
+
+// TestSessionManager_PurgesCodes tests cached codes are purged whenever a
+// session locks: on expiry, on Sweep and on Delete
+func TestSessionManager_PurgesCodes(t *testing.T) {
+	purges := 0
+	oldPurge := purgeCodes
+	purgeCodes = func() { purges++ }
+	t.Cleanup(func() { purgeCodes = oldPurge })
+
+	sessions, clock := newTestSessions(time.Minute)
+
+	expiring, _ := sessions.Create(&storage.Store{})
+	clock.at = clock.at.Add(time.Minute)
+	sessions.Get(expiring)
+	if purges != 1 {
+		t.Fatalf("Purges after expiry = %d, want 1", purges)
+	}
+
+	swept, _ := sessions.Create(&storage.Store{})
+	sessions.Sweep() // nothing expired yet
+	clock.at = clock.at.Add(time.Minute)
+	sessions.Sweep()
+	if _, ok := sessions.Get(swept); ok || purges != 2 {
+		t.Fatalf("Purges after Sweep = %d, want 2", purges)
+	}
+
+	locked, _ := sessions.Create(&storage.Store{})
+	sessions.Delete(locked)
+	sessions.Delete("unknown")
+	if purges != 3 {
+		t.Errorf("Purges after Delete = %d, want 3", purges)
+	}
+}
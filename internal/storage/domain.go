@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"net/url"
+	"strings"
+)
+
+// NormalizeDomain turns a domain or URL into a bare lowercase host without
+// a port, trailing dot or leading "www." ("https://www.GitHub.com/login" ->
+// "github.com"). It returns "" when nothing host-like is left.
+func NormalizeDomain(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+
+	host := raw
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return ""
+		}
+		host = u.Hostname()
+	} else {
+		// Bare domains may still carry a path or port ("github.com/login")
+		host, _, _ = strings.Cut(host, "/")
+		if h, _, ok := strings.Cut(host, ":"); ok {
+			host = h
+		}
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	return strings.TrimPrefix(host, "www.")
+}
+
+// MatchesDomain reports whether the service belongs to domain: its URL host
+// is the domain or a subdomain of it, or its issuer is exactly the domain.
+// An issuer like "GitHub" never matches, since it cannot tell github.com
+// from github.io or github.co.
+func (s *Service) MatchesDomain(domain string) bool {
+	domain = NormalizeDomain(domain)
+	if domain == "" {
+		return false
+	}
+
+	if host := NormalizeDomain(s.URL); host != "" {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+
+	return NormalizeDomain(s.Issuer) == domain
+}
+
+// FindByDomain returns copies of the services matching domain, in stored order
+func (s *Storage) FindByDomain(domain string) []Service {
+	var matches []Service
+	for i := range s.Services {
+		if s.Services[i].MatchesDomain(domain) {
+			matches = append(matches, s.Services[i].Clone())
+		}
+	}
+	return matches
+}
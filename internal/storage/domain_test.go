@@ -0,0 +1,80 @@
+package storage
+
+import "testing"
+
+// TestNormalizeDomain tests reducing domains and URLs to a bare host
+func TestNormalizeDomain(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"github.com", "github.com"},
+		{"GitHub.com.", "github.com"},
+		{"www.github.com", "github.com"},
+		{"https://www.github.com/login", "github.com"},
+		{"http://localhost:8080/auth", "localhost"},
+		{"github.com:443/login", "github.com"},
+		{"  ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeDomain(tt.in); got != tt.want {
+			t.Errorf("NormalizeDomain(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestService_MatchesDomain tests matching by URL host and by exact issuer
+// domain; bare issuer names never match
+func TestService_MatchesDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		service Service
+		domain  string
+		want    bool
+	}{
+		{"URL host", Service{URL: "https://github.com/login"}, "github.com", true},
+		{"URL other domain", Service{URL: "https://github.com/login"}, "gitlab.com", false},
+		{"URL subdomain", Service{URL: "https://accounts.google.com/"}, "google.com", true},
+		{"URL suffix is not a subdomain", Service{URL: "https://notgithub.com/"}, "github.com", false},
+		{"Domain given as URL", Service{URL: "https://github.com/login"}, "https://www.github.com", true},
+		{"Issuer domain", Service{Issuer: "github.com"}, "github.com", true},
+		{"Issuer domain other case", Service{Issuer: "GitHub.com"}, "github.com", true},
+		{"Issuer name", Service{Issuer: "GitHub"}, "github.com", false},
+		{"Issuer name on a lookalike TLD", Service{Issuer: "GitHub"}, "github.io", false},
+		{"Issuer name on another host", Service{Issuer: "GitHub"}, "attacker.github.io", false},
+		{"Issuer domain is not a subdomain wildcard", Service{Issuer: "github.com"}, "evil.github.com", false},
+		{"Issuer name on a second-level TLD", Service{Issuer: "co"}, "example.co.uk", false},
+		{"Issuer other", Service{Issuer: "github.com"}, "gitlab.com", false},
+		{"Nothing to match", Service{Name: "github.com"}, "github.com", false},
+		{"Empty domain", Service{URL: "https://github.com/"}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.service.MatchesDomain(tt.domain); got != tt.want {
+				t.Errorf("MatchesDomain(%q) = %v, want %v", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStorage_FindByDomain tests matches come back as copies in stored order
+func TestStorage_FindByDomain(t *testing.T) {
+	s := &Storage{Services: []Service{
+		{Name: "GitHub Work", URL: "https://github.com/login"},
+		{Name: "GitLab", URL: "https://gitlab.com/users/sign_in"},
+		{Name: "GitHub Personal", Issuer: "github.com"},
+		{Name: "GitHub Pages", Issuer: "GitHub"},
+	}}
+
+	matches := s.FindByDomain("github.com")
+	if len(matches) != 2 || matches[0].Name != "GitHub Work" || matches[1].Name != "GitHub Personal" {
+		t.Fatalf("FindByDomain() = %v, want GitHub Work and GitHub Personal", matches)
+	}
+
+	matches[0].Name = "changed"
+	if s.Services[0].Name != "GitHub Work" {
+		t.Error("FindByDomain() should return copies")
+	}
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ConflictStrategy decides what Import does when a service name already exists
+type ConflictStrategy string
+
+const (
+	// ConflictSkip keeps the existing service and skips the imported one
+	ConflictSkip ConflictStrategy = "skip"
+
+	// ConflictReplace overwrites the existing service with the imported one
+	ConflictReplace ConflictStrategy = "replace"
+
+	// ConflictRename adds the imported service under a new, unused name
+	ConflictRename ConflictStrategy = "rename"
+)
+
+// ParseConflictStrategy parses a --on-conflict value
+func ParseConflictStrategy(value string) (ConflictStrategy, error) {
+	switch strategy := ConflictStrategy(strings.ToLower(value)); strategy {
+	case ConflictSkip, ConflictReplace, ConflictRename:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown conflict strategy %q (use skip, replace or rename)", value)
+	}
+}
+
+// ImportOutcome is what happened to a single imported service
+type ImportOutcome string
+
+const (
+	ImportAdded    ImportOutcome = "added"
+	ImportReplaced ImportOutcome = "replaced"
+	ImportSkipped  ImportOutcome = "skipped"
+	ImportFailed   ImportOutcome = "failed"
+)
+
+// ImportResult records the outcome of importing one service
+type ImportResult struct {
+	Name        string
+	Outcome     ImportOutcome
+	Err         error  // set when Outcome is ImportFailed
+	RenamedFrom string // original name when ConflictRename chose a new one
+	Warning     string // non-fatal problem fixed during import
+}
+
+// futureCreatedAtWarning is the ImportResult warning for a clamped CreatedAt
+const futureCreatedAtWarning = "created_at was in the future; set to now"
+
+// Import adds a service, resolving a name conflict with the given strategy.
+// This is the shared pipeline for all importers; the caller decides whether
+// to Save (a dry run simply doesn't).
+func (s *Storage) Import(service Service, strategy ConflictStrategy) ImportResult {
+	result := ImportResult{Name: service.Name}
+	if service.ClampCreatedAt(time.Now()) {
+		result.Warning = futureCreatedAtWarning
+	}
+
+	existing, err := s.getService(service.Name)
+	if err != nil {
+		// No conflict
+		if err := s.AddService(service); err != nil {
+			result.Outcome, result.Err = ImportFailed, err
+			return result
+		}
+		result.Outcome = ImportAdded
+		return result
+	}
+
+	if strategy == ConflictRename {
+		service.Name = s.uniqueName(service)
+		if err := s.AddService(service); err != nil {
+			result.Outcome, result.Err = ImportFailed, err
+			return result
+		}
+		result.Name, result.RenamedFrom = service.Name, result.Name
+		result.Outcome = ImportAdded
+		return result
+	}
+
+	if strategy != ConflictReplace {
+		result.Outcome = ImportSkipped
+		return result
+	}
+
+	if err := service.Validate(); err != nil {
+		result.Outcome, result.Err = ImportFailed, err
+		return result
+	}
+
+	// The replacement is the same account, so its usage history carries over
+	service.LastUsed, service.UseCount = existing.LastUsed, existing.UseCount
+	*existing = service
+	result.Outcome = ImportReplaced
+	return result
+}
+
+// uniqueName picks an unused, valid name for a conflicting service: the name
+// with its identifier appended ("GitHub (alice)"), otherwise the name with
+// the lowest free numeric suffix ("GitHub 2"). The name is truncated so the
+// result fits maxServiceNameLength.
+func (s *Storage) uniqueName(service Service) string {
+	available := func(name string) bool {
+		if ValidateServiceName(name) != nil {
+			return false
+		}
+		_, err := s.getService(name)
+		return err != nil
+	}
+
+	if service.Identifier != "" {
+		if name := withSuffix(service.Name, " ("+service.Identifier+")"); available(name) {
+			return name
+		}
+	}
+
+	for n := 2; ; n++ {
+		if name := withSuffix(service.Name, fmt.Sprintf(" %d", n)); available(name) {
+			return name
+		}
+	}
+}
+
+// withSuffix appends suffix to name, truncating name at a rune boundary so
+// the result is at most maxServiceNameLength bytes. Returns "" if the suffix
+// alone does not fit.
+func withSuffix(name, suffix string) string {
+	limit := maxServiceNameLength - len(suffix)
+	if limit <= 0 {
+		return ""
+	}
+
+	name = strings.TrimSpace(name)
+	for len(name) > limit {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return strings.TrimSpace(name) + suffix
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseConflictStrategy tests valid and unknown strategies
+func TestParseConflictStrategy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ConflictStrategy
+		wantErr bool
+	}{
+		{"skip", ConflictSkip, false},
+		{"REPLACE", ConflictReplace, false},
+		{"rename", ConflictRename, false},
+		{"merge", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseConflictStrategy(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseConflictStrategy(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseConflictStrategy(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+// TestStorage_Import tests each import outcome
+func TestStorage_Import(t *testing.T) {
+	newStorage := func() *Storage {
+		return &Storage{
+			Version: 1,
+			Services: []Service{
+				{Name: "GitHub", Identifier: "old@example.com", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		}
+	}
+	incoming := Service{Name: "github", Identifier: "new@example.com", Secret: "GEZDGNBVGY3TQOJQ", CreatedAt: time.Now()}
+
+	tests := []struct {
+		name           string
+		service        Service
+		strategy       ConflictStrategy
+		wantOutcome    ImportOutcome
+		wantCount      int
+		wantIdentifier string
+	}{
+		{"New service", Service{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}, ConflictSkip, ImportAdded, 2, "old@example.com"},
+		{"Conflict skipped", incoming, ConflictSkip, ImportSkipped, 1, "old@example.com"},
+		{"Conflict replaced", incoming, ConflictReplace, ImportReplaced, 1, "new@example.com"},
+		{"Conflict renamed", incoming, ConflictRename, ImportAdded, 2, "old@example.com"},
+		{"Invalid new service", Service{Name: "AWS", Secret: "bad!"}, ConflictSkip, ImportFailed, 1, "old@example.com"},
+		{"Invalid replacement", Service{Name: "GitHub", Secret: "bad!"}, ConflictReplace, ImportFailed, 1, "old@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := newStorage()
+			result := storage.Import(tt.service, tt.strategy)
+
+			if result.Outcome != tt.wantOutcome {
+				t.Errorf("Outcome = %s, want %s (err: %v)", result.Outcome, tt.wantOutcome, result.Err)
+			}
+			if (result.Err != nil) != (tt.wantOutcome == ImportFailed) {
+				t.Errorf("Err = %v, want error only for failures", result.Err)
+			}
+			if len(storage.Services) != tt.wantCount {
+				t.Errorf("Service count = %d, want %d", len(storage.Services), tt.wantCount)
+			}
+			if storage.Services[0].Identifier != tt.wantIdentifier {
+				t.Errorf("Existing identifier = %q, want %q", storage.Services[0].Identifier, tt.wantIdentifier)
+			}
+		})
+	}
+}
+
+// TestStorage_ImportReplaceKeepsUsage tests a replaced service keeps its
+// LastUsed and UseCount
+func TestStorage_ImportReplaceKeepsUsage(t *testing.T) {
+	lastUsed := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	storage := &Storage{
+		Version: 1,
+		Services: []Service{
+			{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(), LastUsed: &lastUsed, UseCount: 7},
+		},
+	}
+
+	result := storage.Import(Service{Name: "GitHub", Secret: "GEZDGNBVGY3TQOJQ", CreatedAt: time.Now()}, ConflictReplace)
+	if result.Outcome != ImportReplaced {
+		t.Fatalf("Outcome = %s (err: %v), want replaced", result.Outcome, result.Err)
+	}
+
+	replaced := storage.Services[0]
+	if replaced.Secret != "GEZDGNBVGY3TQOJQ" {
+		t.Errorf("Secret = %q, want the imported one", replaced.Secret)
+	}
+	if replaced.UseCount != 7 || replaced.LastUsed == nil || !replaced.LastUsed.Equal(lastUsed) {
+		t.Errorf("UseCount %d LastUsed %v, want 7 and %v", replaced.UseCount, replaced.LastUsed, lastUsed)
+	}
+}
+
+// TestStorage_ImportRename tests colliding imports land under distinct,
+// valid names: identifier first, then numeric suffixes, truncated to fit
+func TestStorage_ImportRename(t *testing.T) {
+	long := strings.Repeat("Ä", 25) // 50 bytes, the maximum
+	storage := &Storage{
+		Version: 1,
+		Services: []Service{
+			{Name: "GitHub", Identifier: "alice", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			{Name: long, Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		},
+	}
+
+	imports := []struct {
+		service  Service
+		wantName string
+	}{
+		{Service{Name: "GitHub", Identifier: "bob", Secret: "GEZDGNBVGY3TQOJQ"}, "GitHub (bob)"},
+		{Service{Name: "github", Identifier: "bob", Secret: "MFRGGZDFMZTWQ2LK"}, "github 2"},
+		{Service{Name: "GitHub", Secret: "MFRGGZDFMZTWQ2LK"}, "GitHub 3"},
+		{Service{Name: long, Secret: "GEZDGNBVGY3TQOJQ"}, strings.Repeat("Ä", 24) + " 2"},
+	}
+
+	for _, imp := range imports {
+		result := storage.Import(imp.service, ConflictRename)
+		if result.Outcome != ImportAdded {
+			t.Fatalf("Import(%q) outcome = %s (err: %v), want added", imp.service.Name, result.Outcome, result.Err)
+		}
+		if result.Name != imp.wantName || result.RenamedFrom != imp.service.Name {
+			t.Errorf("Import(%q) = %q renamed from %q, want %q", imp.service.Name, result.Name, result.RenamedFrom, imp.wantName)
+		}
+		if err := ValidateServiceName(result.Name); err != nil {
+			t.Errorf("Generated name %q is invalid: %v", result.Name, err)
+		}
+	}
+
+	if len(storage.Services) != 6 {
+		t.Errorf("Service count = %d, want 6", len(storage.Services))
+	}
+}
+
+// TestStorage_Import_FutureCreatedAt tests a far-future CreatedAt is clamped
+// to about now with a warning, while a past timestamp is kept
+func TestStorage_Import_FutureCreatedAt(t *testing.T) {
+	storage := &Storage{Version: 1}
+	past := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	result := storage.Import(Service{Name: "Future", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now().AddDate(50, 0, 0)}, ConflictSkip)
+	if result.Outcome != ImportAdded || result.Warning == "" {
+		t.Errorf("Import() = %+v, want added with a warning", result)
+	}
+	if got := storage.Services[0].CreatedAt; time.Since(got).Abs() > time.Minute {
+		t.Errorf("CreatedAt = %v, want about now", got)
+	}
+
+	result = storage.Import(Service{Name: "Past", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: past}, ConflictSkip)
+	if result.Warning != "" {
+		t.Errorf("Import() warning = %q, want none for a past timestamp", result.Warning)
+	}
+	if got := storage.Services[1].CreatedAt; !got.Equal(past) {
+		t.Errorf("CreatedAt = %v, want %v", got, past)
+	}
+}
@@ -2,10 +2,24 @@ package storage
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/pavanprakash21/totp-manager-go/internal/totp"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Code types
+const (
+	// TypeTOTP is a standard RFC 6238 TOTP service (the default)
+	TypeTOTP = "totp"
+
+	// TypeSteam is a Steam Guard service producing 5-character codes
+	TypeSteam = "steam"
 )
 
 // Service represents a single TOTP service configuration
@@ -16,6 +30,17 @@ type Service struct {
 	// Identifier is an optional additional identifier (e.g., email, username)
 	Identifier string `json:"identifier,omitempty"`
 
+	// Issuer is the original issuer from an imported otpauth URI, kept
+	// separately from Name so renaming does not lose provenance
+	Issuer string `json:"issuer,omitempty"`
+
+	// Label is the raw label from an imported otpauth URI (e.g., "GitHub:alice")
+	Label string `json:"label,omitempty"`
+
+	// URL is the optional login page for the account (http or https),
+	// used to match accounts to sites
+	URL string `json:"url,omitempty"`
+
 	// Secret is the Base32-encoded shared secret
 	Secret string `json:"secret"`
 
@@ -24,6 +49,91 @@ type Service struct {
 
 	// LastUsed is updated when TOTP code is copied
 	LastUsed *time.Time `json:"last_used,omitempty"`
+
+	// UseCount is incremented each time a code is copied
+	UseCount int `json:"use_count,omitempty"`
+
+	// Pinned keeps the service at the top of lists regardless of sort order
+	Pinned bool `json:"pinned,omitempty"`
+
+	// Type is the code type (TypeTOTP or TypeSteam); empty means TypeTOTP
+	Type string `json:"type,omitempty"`
+
+	// Recovery holds optional backup/recovery codes (shown only on explicit reveal)
+	Recovery []string `json:"recovery,omitempty"`
+
+	// Algorithm is the HMAC algorithm (SHA1, SHA256, SHA512); empty means SHA1
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// Digits is the code length; zero means 6
+	Digits int `json:"digits,omitempty"`
+
+	// Period is the code lifetime in seconds; zero means 30
+	Period int `json:"period,omitempty"`
+
+	// AllowWeakSecret records that the user accepted a secret shorter than
+	// the 16-character minimum (--allow-weak-secret); it must still be Base32
+	AllowWeakSecret bool `json:"allow_weak_secret,omitempty"`
+}
+
+// Recovery code limits
+const (
+	maxRecoveryCodes      = 32
+	maxRecoveryCodeLength = 128
+)
+
+// Clone returns a deep copy of the service: LastUsed and Recovery are
+// copied rather than shared, so changing either copy never affects the other
+func (s Service) Clone() Service {
+	clone := s
+	if s.LastUsed != nil {
+		lastUsed := *s.LastUsed
+		clone.LastUsed = &lastUsed
+	}
+	if s.Recovery != nil {
+		clone.Recovery = append([]string(nil), s.Recovery...)
+	}
+	return clone
+}
+
+// Code generates the service's code for the given time
+func (s *Service) Code(t time.Time) (string, error) {
+	if s.Type == TypeSteam {
+		return totp.GenerateSteamCode(s.Secret, t)
+	}
+	return totp.GenerateCodeCached(s.Secret, t, s.EffectiveAlgorithm(), s.EffectiveDigits(), s.EffectivePeriod())
+}
+
+// CodeLength returns the number of characters in the service's codes
+func (s *Service) CodeLength() int {
+	if s.Type == TypeSteam {
+		return totp.SteamCodeLength
+	}
+	return s.EffectiveDigits()
+}
+
+// EffectiveAlgorithm returns the HMAC algorithm with the default applied
+func (s *Service) EffectiveAlgorithm() string {
+	if s.Algorithm == "" {
+		return totp.DefaultAlgorithm
+	}
+	return strings.ToUpper(s.Algorithm)
+}
+
+// EffectiveDigits returns the code length with the default applied
+func (s *Service) EffectiveDigits() int {
+	if s.Digits == 0 {
+		return totp.DefaultDigits
+	}
+	return s.Digits
+}
+
+// EffectivePeriod returns the period in seconds with the default applied
+func (s *Service) EffectivePeriod() int {
+	if s.Period == 0 {
+		return totp.DefaultPeriod
+	}
+	return s.Period
 }
 
 // Validate validates the Service struct
@@ -33,52 +143,235 @@ func (s *Service) Validate() error {
 		return err
 	}
 
+	// Free-text fields are rendered in the TUI; invalid bytes (e.g. from a
+	// bad paste) would garble it
+	for _, field := range []struct{ name, value string }{
+		{"identifier", s.Identifier},
+		{"issuer", s.Issuer},
+		{"label", s.Label},
+	} {
+		if !utf8.ValidString(field.value) {
+			return fmt.Errorf("service %s is not valid UTF-8 (check for a bad paste)", field.name)
+		}
+	}
+
+	// Validate login URL
+	if err := ValidateURL(s.URL); err != nil {
+		return err
+	}
+
 	// Validate secret
-	if err := totp.ValidateSecret(s.Secret); err != nil {
+	validateSecret := totp.ValidateSecret
+	if s.AllowWeakSecret {
+		validateSecret = totp.ValidateSecretEncoding
+	}
+	if err := validateSecret(totp.NormalizeSecret(s.Secret)); err != nil {
 		return fmt.Errorf("invalid secret: %w", err)
 	}
 
+	// Validate recovery codes
+	if err := ValidateRecoveryCodes(s.Recovery); err != nil {
+		return err
+	}
+
+	// Validate code type and parameters (zero values fall back to defaults)
+	if err := ValidateType(s.Type); err != nil {
+		return err
+	}
+	if err := totp.ValidateParams(s.EffectiveAlgorithm(), s.EffectiveDigits(), s.EffectivePeriod()); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 // Storage encapsulates encrypted service data and metadata
 type Storage struct {
-	// Version for future format migrations (current: 1)
+	// Version for future format migrations (1 = plain JSON, 2 = gzip-compressed JSON,
+	// 3 = cipher-tagged header, 4 = cipher- and KDF-tagged header)
 	Version int `json:"version"`
 
 	// Services is the list of configured TOTP services
 	Services []Service `json:"services"`
 
+	// SavesSinceRekey counts saves under the current salt
+	SavesSinceRekey int `json:"saves_since_rekey,omitempty"`
+
 	// Salt for Argon2id key derivation (stored separately in file)
 	Salt []byte `json:"-"`
 
 	// Nonce for AES-GCM encryption (stored separately in file)
 	Nonce []byte `json:"-"`
+
+	// Settings holds user preferences stored with the encrypted data
+	Settings Settings `json:"settings"`
 }
 
-// AddService adds a new service to storage
+// DefaultRekeyAfterSaves is the salt rotation interval when
+// Settings.RekeyAfterSaves is unset
+const DefaultRekeyAfterSaves = 1000
+
+// Settings holds user preferences persisted inside the encrypted storage
+type Settings struct {
+	// MaxServices caps the number of stored services (0 = unlimited)
+	MaxServices int `json:"max_services,omitempty"`
+
+	// BarRefreshMillis refreshes the TUI countdown bar faster than once a
+	// second (e.g. 250); 0 keeps the default one-second refresh
+	BarRefreshMillis int `json:"bar_refresh_ms,omitempty"`
+
+	// Compress gzips the JSON before encryption (useful for large vaults)
+	Compress bool `json:"compress,omitempty"`
+
+	// RekeyAfterSaves rotates the salt (and so the encryption key) after this
+	// many saves to bound random-nonce reuse; 0 uses DefaultRekeyAfterSaves
+	RekeyAfterSaves int `json:"rekey_after_saves,omitempty"`
+
+	// Cipher selects the storage cipher by crypto.ParseCipher name; empty
+	// means AES-256-GCM
+	Cipher string `json:"cipher,omitempty"`
+
+	// KDF selects the key derivation function by crypto.ParseKDF name;
+	// empty means crypto.DefaultKDF (Argon2id unless built with -tags noargon2)
+	KDF string `json:"kdf,omitempty"`
+
+	// SearchSortRecent orders TUI search results by last-used (most recent first)
+	SearchSortRecent bool `json:"search_sort_recent,omitempty"`
+
+	// VerifyClipboard reads the clipboard back after copying and warns on a
+	// mismatch (opt-in: reading the clipboard may need extra permissions)
+	VerifyClipboard bool `json:"verify_clipboard,omitempty"`
+
+	// HideIdentifiers leaves identifiers out of the TUI list rows; the
+	// details pane still shows them
+	HideIdentifiers bool `json:"hide_identifiers,omitempty"`
+
+	// ShowExpiresAt shows the wall-clock time the selected code expires
+	// instead of the TUI countdown ('t' toggles it per session)
+	ShowExpiresAt bool `json:"show_expires_at,omitempty"`
+
+	// ErrorPlaceholder is shown instead of a code that fails to generate;
+	// empty means DefaultErrorPlaceholder
+	ErrorPlaceholder string `json:"error_placeholder,omitempty"`
+
+	// Clipboard is the clipboard backend: auto, system or osc52 (empty = auto)
+	Clipboard string `json:"clipboard,omitempty"`
+}
+
+// DefaultErrorPlaceholder is shown for codes that fail to generate
+const DefaultErrorPlaceholder = "ERROR"
+
+// MaxErrorPlaceholderLength keeps the placeholder within the code column
+const MaxErrorPlaceholderLength = 10
+
+// CodeErrorPlaceholder returns the placeholder for codes that fail to generate
+func (s Settings) CodeErrorPlaceholder() string {
+	if s.ErrorPlaceholder == "" {
+		return DefaultErrorPlaceholder
+	}
+	return s.ErrorPlaceholder
+}
+
+// rekeyThreshold returns the number of saves after which the salt rotates
+func (s Settings) rekeyThreshold() int {
+	if s.RekeyAfterSaves > 0 {
+		return s.RekeyAfterSaves
+	}
+	return DefaultRekeyAfterSaves
+}
+
+// maxCreatedAtSkew tolerates clock differences between machines before a
+// CreatedAt counts as being in the future
+const maxCreatedAtSkew = 5 * time.Minute
+
+// ClampCreatedAt resets a CreatedAt more than maxCreatedAtSkew after now to
+// now (imported data sometimes carries bogus timestamps), reporting whether
+// it changed
+func (s *Service) ClampCreatedAt(now time.Time) bool {
+	if !s.CreatedAt.After(now.Add(maxCreatedAtSkew)) {
+		return false
+	}
+	s.CreatedAt = now
+	return true
+}
+
+// AddService adds a new service to storage. A CreatedAt in the future is
+// clamped to now; callers that want to warn check ClampCreatedAt first.
 func (s *Storage) AddService(service Service) error {
 	// Validate service
 	if err := service.Validate(); err != nil {
 		return err
 	}
+	service.ClampCreatedAt(time.Now())
 
 	// Check for duplicate name (case-insensitive)
 	for _, existing := range s.Services {
-		if strings.EqualFold(existing.Name, service.Name) {
+		if SameName(existing.Name, service.Name) {
 			return fmt.Errorf("service '%s' already exists", service.Name)
 		}
 	}
 
+	// Enforce the optional service limit (guards against runaway imports)
+	if s.Settings.MaxServices > 0 && len(s.Services) >= s.Settings.MaxServices {
+		return fmt.Errorf("service limit reached: storage is capped at %d services (change with 'totp config --max-services')", s.Settings.MaxServices)
+	}
+
 	// Add service
 	s.Services = append(s.Services, service)
 	return nil
 }
 
-// GetService retrieves a service by name (case-insensitive)
-func (s *Storage) GetService(name string) (*Service, error) {
+// Validate checks every service and returns all problems found
+// (invalid fields and case-insensitive duplicate names); nil means valid
+func (s *Storage) Validate() []error {
+	var errs []error
+	seen := make(map[string]string)
+
 	for i := range s.Services {
-		if strings.EqualFold(s.Services[i].Name, name) {
+		service := &s.Services[i]
+		if err := service.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("service #%d '%s': %w", i+1, service.Name, err))
+		}
+
+		key := NameKey(service.Name)
+		if first, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("service #%d '%s': duplicate of '%s'", i+1, service.Name, first))
+			continue
+		}
+		seen[key] = service.Name
+	}
+
+	return errs
+}
+
+// NameKey returns the form of a service name used for duplicate detection
+// and lookup: trimmed, NFC-normalized and fully case-folded, so "Straße"
+// matches "STRASSE " and composed and decomposed accents match. Folding is not
+// locale-specific: "İ" folds to "i̇", not "i".
+func NameKey(name string) string {
+	return norm.NFC.String(cases.Fold().String(strings.TrimSpace(name)))
+}
+
+// SameName reports whether two service names refer to the same service
+func SameName(a, b string) bool {
+	return a == b || NameKey(a) == NameKey(b)
+}
+
+// GetServiceCopy retrieves a copy of a service by name (case-insensitive);
+// changing the copy never touches stored data
+func (s *Storage) GetServiceCopy(name string) (Service, error) {
+	service, err := s.getService(name)
+	if err != nil {
+		return Service{}, err
+	}
+	return service.Clone(), nil
+}
+
+// getService retrieves a service by name (case-insensitive), pointing into
+// Services so callers in this package can update it in place
+func (s *Storage) getService(name string) (*Service, error) {
+	for i := range s.Services {
+		if SameName(s.Services[i].Name, name) {
 			return &s.Services[i], nil
 		}
 	}
@@ -87,18 +380,110 @@ func (s *Storage) GetService(name string) (*Service, error) {
 
 // UpdateLastUsed updates the LastUsed timestamp for a service
 func (s *Storage) UpdateLastUsed(name string) error {
+	service, err := s.getService(name)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	service.LastUsed = &now
+	return nil
+}
+
+// RecordUse marks a service as used: updates LastUsed and increments UseCount
+func (s *Storage) RecordUse(name string) error {
+	if err := s.UpdateLastUsed(name); err != nil {
+		return err
+	}
+	service, _ := s.getService(name)
+	service.UseCount++
+	return nil
+}
+
+// ResetStats zeroes the usage count of the named service, or of every
+// service when name is empty, optionally clearing LastUsed as well. It
+// returns how many services were reset.
+func (s *Storage) ResetStats(name string, clearLastUsed bool) (int, error) {
+	reset := 0
 	for i := range s.Services {
-		if strings.EqualFold(s.Services[i].Name, name) {
-			now := time.Now()
-			s.Services[i].LastUsed = &now
-			return nil
+		if name != "" && !SameName(s.Services[i].Name, name) {
+			continue
 		}
+		s.Services[i].UseCount = 0
+		if clearLastUsed {
+			s.Services[i].LastUsed = nil
+		}
+		reset++
 	}
-	return fmt.Errorf("service '%s' not found", name)
+
+	if name != "" && reset == 0 {
+		return 0, fmt.Errorf("service '%s' not found", name)
+	}
+	return reset, nil
+}
+
+// TogglePinned flips a service's Pinned flag and returns the new value
+func (s *Storage) TogglePinned(name string) (bool, error) {
+	for i := range s.Services {
+		if SameName(s.Services[i].Name, name) {
+			s.Services[i].Pinned = !s.Services[i].Pinned
+			return s.Services[i].Pinned, nil
+		}
+	}
+	return false, fmt.Errorf("service '%s' not found", name)
+}
+
+// UpdateService replaces the service named name (case-insensitive) with
+// updated, which may carry a new name. The update is validated first, may
+// not take another service's name, and keeps the stored CreatedAt.
+func (s *Storage) UpdateService(name string, updated Service) error {
+	index := -1
+	for i := range s.Services {
+		if SameName(s.Services[i].Name, name) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("service '%s' not found", name)
+	}
+
+	if err := updated.Validate(); err != nil {
+		return err
+	}
+
+	// Changing only the case of a name is allowed
+	for i := range s.Services {
+		if i != index && SameName(s.Services[i].Name, updated.Name) {
+			return fmt.Errorf("service '%s' already exists", updated.Name)
+		}
+	}
+
+	updated.CreatedAt = s.Services[index].CreatedAt
+	s.Services[index] = updated
+	return nil
 }
 
+// RemoveService deletes a service by name (case-insensitive) and returns it
+func (s *Storage) RemoveService(name string) (Service, error) {
+	for i := range s.Services {
+		if SameName(s.Services[i].Name, name) {
+			removed := s.Services[i]
+			s.Services = append(s.Services[:i], s.Services[i+1:]...)
+			return removed, nil
+		}
+	}
+	return Service{}, fmt.Errorf("service '%s' not found", name)
+}
+
+// maxServiceNameLength is the longest service name in bytes
+const maxServiceNameLength = 50
+
 // ValidateServiceName validates a service name
 func ValidateServiceName(name string) error {
+	if !utf8.ValidString(name) {
+		return fmt.Errorf("service name is not valid UTF-8 (check for a bad paste)")
+	}
+
 	// Trim whitespace for validation
 	trimmed := strings.TrimSpace(name)
 
@@ -108,8 +493,8 @@ func ValidateServiceName(name string) error {
 	}
 
 	// Check length (1-50 characters)
-	if len(trimmed) > 50 {
-		return fmt.Errorf("service name too long: max 50 characters, got %d", len(trimmed))
+	if len(trimmed) > maxServiceNameLength {
+		return fmt.Errorf("service name too long: max %d characters, got %d", maxServiceNameLength, len(trimmed))
 	}
 
 	// Check for control characters and path separators
@@ -124,3 +509,85 @@ func ValidateServiceName(name string) error {
 
 	return nil
 }
+
+// strictNamePunctuation lists the punctuation allowed by ValidateStrictServiceName
+const strictNamePunctuation = " -_.@+()"
+
+// ValidateStrictServiceName applies ValidateServiceName and then limits the
+// name to letters, digits, spaces and -_.@+() so it survives shell
+// completion and CSV tools unquoted (add --strict-names)
+func ValidateStrictServiceName(name string) error {
+	if err := ValidateServiceName(name); err != nil {
+		return err
+	}
+
+	for _, c := range strings.TrimSpace(name) {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && !strings.ContainsRune(strictNamePunctuation, c) {
+			return fmt.Errorf("service name contains %q; strict names allow only letters, digits, spaces and %s", c, strings.TrimSpace(strictNamePunctuation))
+		}
+	}
+	return nil
+}
+
+// ValidateType validates a service's code type; empty means TypeTOTP
+func ValidateType(t string) error {
+	switch t {
+	case "", TypeTOTP, TypeSteam:
+		return nil
+	default:
+		return fmt.Errorf("unknown code type %q (use %s or %s)", t, TypeTOTP, TypeSteam)
+	}
+}
+
+// maxURLLength is the longest login URL in bytes
+const maxURLLength = 2048
+
+// ValidateURL validates a service's login URL; empty means none
+func ValidateURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	if len(raw) > maxURLLength {
+		return fmt.Errorf("url too long: max %d characters, got %d", maxURLLength, len(raw))
+	}
+	for _, c := range raw {
+		if c <= 32 || c == 127 {
+			return fmt.Errorf("url contains whitespace or control character")
+		}
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url must start with http:// or https://")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("url has no host")
+	}
+	return nil
+}
+
+// ValidateRecoveryCodes validates a service's recovery codes
+func ValidateRecoveryCodes(codes []string) error {
+	if len(codes) > maxRecoveryCodes {
+		return fmt.Errorf("too many recovery codes: max %d, got %d", maxRecoveryCodes, len(codes))
+	}
+
+	for i, code := range codes {
+		if strings.TrimSpace(code) == "" {
+			return fmt.Errorf("recovery code %d is empty", i+1)
+		}
+		if len(code) > maxRecoveryCodeLength {
+			return fmt.Errorf("recovery code %d too long: max %d characters", i+1, maxRecoveryCodeLength)
+		}
+		for _, c := range code {
+			if c < 32 || c == 127 {
+				return fmt.Errorf("recovery code %d contains control character", i+1)
+			}
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -107,6 +110,62 @@ func TestService_Validation(t *testing.T) {
 	}
 }
 
+// TestService_ValidateWeakSecret tests AllowWeakSecret relaxes only the length rule
+func TestService_ValidateWeakSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		service Service
+		wantErr bool
+	}{
+		{"Short secret rejected", Service{Name: "Legacy", Secret: "JBSWY3DPEH"}, true},
+		{"Short secret allowed", Service{Name: "Legacy", Secret: "JBSWY3DPEH", AllowWeakSecret: true}, false},
+		{"Invalid Base32 still rejected", Service{Name: "Legacy", Secret: "JBSWY3DP1!", AllowWeakSecret: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.service.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestService_ValidateUTF8 tests invalid UTF-8 in text fields is rejected
+func TestService_ValidateUTF8(t *testing.T) {
+	const secret = "JBSWY3DPEHPK3PXP"
+	invalid := "Git\xffHub" // 0xFF never appears in UTF-8
+
+	tests := []struct {
+		name    string
+		service Service
+		field   string
+	}{
+		{"Name", Service{Name: invalid, Secret: secret}, "name"},
+		{"Identifier", Service{Name: "GitHub", Identifier: "user\xc3@example.com", Secret: secret}, "identifier"},
+		{"Issuer", Service{Name: "GitHub", Issuer: invalid, Secret: secret}, "issuer"},
+		{"Label", Service{Name: "GitHub", Label: "GitHub:\xe2\x82", Secret: secret}, "label"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.service.Validate()
+			if err == nil {
+				t.Fatal("Validate() should reject invalid UTF-8")
+			}
+			if !strings.Contains(err.Error(), tt.field) || !strings.Contains(err.Error(), "UTF-8") {
+				t.Errorf("Validate() error = %q, want it to name the %s and UTF-8", err, tt.field)
+			}
+		})
+	}
+
+	valid := Service{Name: "Gïthub 日本", Identifier: "usér@example.com", Secret: secret}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() of valid multibyte text error = %v", err)
+	}
+}
+
 // TestValidateServiceName tests service name validation
 func TestValidateServiceName(t *testing.T) {
 	tests := []struct {
@@ -181,6 +240,30 @@ func TestValidateServiceName(t *testing.T) {
 	}
 }
 
+// TestValidateStrictServiceName tests only the safe character set passes
+func TestValidateStrictServiceName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"GitHub", false},
+		{"GitHub (work) alice@example.com", false},
+		{"Café-Prod_2.0+", false},
+		{"Acme, Inc", true},
+		{`Say "hi"`, true},
+		{"rm;ls", true},
+		{"$HOME", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateStrictServiceName(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateStrictServiceName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
 // TestStorage_AddService tests adding services to storage
 func TestStorage_AddService(t *testing.T) {
 	storage := &Storage{
@@ -227,6 +310,167 @@ func TestStorage_AddService(t *testing.T) {
 	}
 }
 
+// TestValidateRecoveryCodes tests recovery code limits
+func TestValidateRecoveryCodes(t *testing.T) {
+	tooMany := make([]string, maxRecoveryCodes+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("code-%d", i)
+	}
+
+	tests := []struct {
+		name    string
+		codes   []string
+		wantErr bool
+	}{
+		{"None", nil, false},
+		{"Valid", []string{"abcd-efgh", "1234 5678"}, false},
+		{"Empty code", []string{"abcd", "  "}, true},
+		{"Too long", []string{strings.Repeat("a", maxRecoveryCodeLength+1)}, true},
+		{"Control character", []string{"abc\ndef"}, true},
+		{"Too many", tooMany, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRecoveryCodes(tt.codes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRecoveryCodes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestValidateURL tests login URL validation
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"None", "", false},
+		{"HTTPS", "https://github.com/login", false},
+		{"HTTP with port", "http://localhost:8080/auth", false},
+		{"Other scheme", "ftp://example.com", true},
+		{"Script", "javascript:alert(1)", true},
+		{"No scheme", "github.com/login", true},
+		{"No host", "https:///login", true},
+		{"Whitespace", "https://example.com/a b", true},
+		{"Control character", "https://example.com/\n", true},
+		{"Too long", "https://example.com/" + strings.Repeat("a", maxURLLength), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestService_ValidateURL tests that Validate rejects a bad login URL
+func TestService_ValidateURL(t *testing.T) {
+	service := Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", URL: "mailto:a@example.com"}
+	if err := service.Validate(); err == nil {
+		t.Error("Validate() should reject a non-http(s) URL")
+	}
+}
+
+// TestValidateType tests accepted code types
+func TestValidateType(t *testing.T) {
+	for _, valid := range []string{"", TypeTOTP, TypeSteam} {
+		if err := ValidateType(valid); err != nil {
+			t.Errorf("ValidateType(%q) error = %v", valid, err)
+		}
+	}
+
+	service := Service{Name: "Counter", Secret: "JBSWY3DPEHPK3PXP", Type: "hotp"}
+	if err := service.Validate(); err == nil {
+		t.Error("Validate() should reject an unknown code type")
+	}
+}
+
+// TestStorage_AddService_MaxServices tests the optional service limit
+func TestStorage_AddService_MaxServices(t *testing.T) {
+	storage := &Storage{
+		Version:  1,
+		Services: []Service{},
+		Settings: Settings{MaxServices: 2},
+	}
+
+	for _, name := range []string{"GitHub", "GitLab"} {
+		if err := storage.AddService(Service{Name: name, Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("AddService(%s) error = %v", name, err)
+		}
+	}
+
+	err := storage.AddService(Service{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()})
+	if err == nil {
+		t.Fatal("Expected error adding past the service limit")
+	}
+	if !strings.Contains(err.Error(), "service limit reached") {
+		t.Errorf("Expected service limit error, got %v", err)
+	}
+	if len(storage.Services) != 2 {
+		t.Errorf("Expected 2 services after rejected add, got %d", len(storage.Services))
+	}
+}
+
+// TestStorage_AddService_Unlimited tests MaxServices 0 disables the limit
+func TestStorage_AddService_Unlimited(t *testing.T) {
+	storage := &Storage{
+		Version:  1,
+		Services: []Service{},
+	}
+
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprintf("Service%d", i)
+		if err := storage.AddService(Service{Name: name, Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("AddService(%s) error = %v", name, err)
+		}
+	}
+}
+
+// TestStorage_Validate tests aggregated validation over the whole store
+func TestStorage_Validate(t *testing.T) {
+	storage := &Storage{
+		Version: 1,
+		Services: []Service{
+			{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			{Name: "Broken", Secret: "INVALID!!!", CreatedAt: time.Now()},
+			{Name: "github", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		},
+	}
+
+	errs := storage.Validate()
+	if len(errs) != 2 {
+		t.Fatalf("Validate() returned %d errors, want 2: %v", len(errs), errs)
+	}
+
+	if !strings.Contains(errs[0].Error(), "'Broken'") || !strings.Contains(errs[0].Error(), "invalid secret") {
+		t.Errorf("Expected invalid secret error for 'Broken', got %v", errs[0])
+	}
+	if !strings.Contains(errs[1].Error(), "duplicate of 'GitHub'") {
+		t.Errorf("Expected duplicate error for 'github', got %v", errs[1])
+	}
+}
+
+// TestStorage_Validate_Valid tests a valid store returns no errors
+func TestStorage_Validate_Valid(t *testing.T) {
+	storage := &Storage{
+		Version: 1,
+		Services: []Service{
+			{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			{Name: "GitLab", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		},
+	}
+
+	if errs := storage.Validate(); len(errs) != 0 {
+		t.Errorf("Validate() = %v, want no errors", errs)
+	}
+}
+
 // TestStorage_GetService tests retrieving services
 func TestStorage_GetService(t *testing.T) {
 	storage := &Storage{
@@ -246,27 +490,55 @@ func TestStorage_GetService(t *testing.T) {
 	}
 
 	// Test existing service
-	service, err := storage.GetService("GitHub")
+	service, err := storage.getService("GitHub")
 	if err != nil {
-		t.Fatalf("GetService() error = %v", err)
+		t.Fatalf("getService() error = %v", err)
 	}
 	if service.Name != "GitHub" {
-		t.Errorf("GetService() name = %s, want GitHub", service.Name)
+		t.Errorf("getService() name = %s, want GitHub", service.Name)
 	}
 
 	// Test case-insensitive lookup
-	service, err = storage.GetService("github")
+	service, err = storage.getService("github")
 	if err != nil {
-		t.Fatalf("GetService() case-insensitive error = %v", err)
+		t.Fatalf("getService() case-insensitive error = %v", err)
 	}
 	if service.Name != "GitHub" {
-		t.Errorf("GetService() case-insensitive name = %s, want GitHub", service.Name)
+		t.Errorf("getService() case-insensitive name = %s, want GitHub", service.Name)
 	}
 
 	// Test non-existent service
-	_, err = storage.GetService("NonExistent")
+	_, err = storage.getService("NonExistent")
 	if err == nil {
-		t.Error("GetService() expected error for non-existent service, got nil")
+		t.Error("getService() expected error for non-existent service, got nil")
+	}
+}
+
+// TestStorage_GetServiceCopy tests mutating the returned copy leaves stored data alone
+func TestStorage_GetServiceCopy(t *testing.T) {
+	used := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage := &Storage{
+		Version: 1,
+		Services: []Service{
+			{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", LastUsed: &used, Recovery: []string{"aaaa-bbbb"}},
+		},
+	}
+
+	service, err := storage.GetServiceCopy("github")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	service.Secret = "GEZDGNBVGY3TQOJQ"
+	*service.LastUsed = used.Add(time.Hour)
+	service.Recovery[0] = "changed"
+
+	stored := storage.Services[0]
+	if stored.Secret != "JBSWY3DPEHPK3PXP" || !stored.LastUsed.Equal(used) || stored.Recovery[0] != "aaaa-bbbb" {
+		t.Errorf("stored service = %+v, want it unchanged", stored)
+	}
+
+	if _, err := storage.GetServiceCopy("Missing"); err == nil {
+		t.Error("GetServiceCopy() should fail for a missing service")
 	}
 }
 
@@ -306,7 +578,373 @@ func TestStorage_UpdateLastUsed(t *testing.T) {
 	}
 }
 
+// TestStorage_RecordUse tests recording a use updates LastUsed and UseCount
+func TestStorage_RecordUse(t *testing.T) {
+	storage := &Storage{
+		Version: 1,
+		Services: []Service{
+			{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := storage.RecordUse("github"); err != nil {
+			t.Fatalf("RecordUse() error = %v", err)
+		}
+	}
+
+	if storage.Services[0].UseCount != 3 {
+		t.Errorf("UseCount = %d, want 3", storage.Services[0].UseCount)
+	}
+	if storage.Services[0].LastUsed == nil {
+		t.Error("LastUsed should be set after RecordUse")
+	}
+
+	if err := storage.RecordUse("Missing"); err == nil {
+		t.Error("RecordUse() should fail for a missing service")
+	}
+}
+
+// TestService_ClampCreatedAt tests only timestamps beyond the allowed skew are clamped
+func TestService_ClampCreatedAt(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		want      time.Time
+		clamped   bool
+	}{
+		{"far future", now.AddDate(100, 0, 0), now, true},
+		{"just past the skew", now.Add(maxCreatedAtSkew + time.Second), now, true},
+		{"within the skew", now.Add(time.Minute), now.Add(time.Minute), false},
+		{"past", now.AddDate(-3, 0, 0), now.AddDate(-3, 0, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := Service{CreatedAt: tt.createdAt}
+			if got := service.ClampCreatedAt(now); got != tt.clamped {
+				t.Errorf("ClampCreatedAt() = %v, want %v", got, tt.clamped)
+			}
+			if !service.CreatedAt.Equal(tt.want) {
+				t.Errorf("CreatedAt = %v, want %v", service.CreatedAt, tt.want)
+			}
+		})
+	}
+}
+
+// TestStorage_AddService_FutureCreatedAt tests AddService clamps a future CreatedAt
+func TestStorage_AddService_FutureCreatedAt(t *testing.T) {
+	storage := &Storage{Version: 1}
+	if err := storage.AddService(Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now().AddDate(10, 0, 0)}); err != nil {
+		t.Fatalf("AddService() error = %v", err)
+	}
+	if got := storage.Services[0].CreatedAt; time.Since(got).Abs() > time.Minute {
+		t.Errorf("CreatedAt = %v, want about now", got)
+	}
+}
+
+// TestStorage_ResetStats tests resetting one service or all, with and
+// without clearing LastUsed
+func TestStorage_ResetStats(t *testing.T) {
+	newStorage := func() *Storage {
+		used := time.Now()
+		return &Storage{
+			Version: 1,
+			Services: []Service{
+				{Name: "GitHub", UseCount: 3, LastUsed: &used},
+				{Name: "AWS", UseCount: 5, LastUsed: &used},
+			},
+		}
+	}
+
+	storage := newStorage()
+	if n, err := storage.ResetStats("github", false); err != nil || n != 1 {
+		t.Fatalf("ResetStats(github) = %d, %v; want 1, nil", n, err)
+	}
+	if storage.Services[0].UseCount != 0 || storage.Services[0].LastUsed == nil {
+		t.Errorf("GitHub = %+v, want count 0 with LastUsed kept", storage.Services[0])
+	}
+	if storage.Services[1].UseCount != 5 {
+		t.Errorf("AWS UseCount = %d, want 5 (untouched)", storage.Services[1].UseCount)
+	}
+
+	storage = newStorage()
+	if n, err := storage.ResetStats("", true); err != nil || n != 2 {
+		t.Fatalf("ResetStats(all) = %d, %v; want 2, nil", n, err)
+	}
+	for _, service := range storage.Services {
+		if service.UseCount != 0 || service.LastUsed != nil {
+			t.Errorf("%s = %+v, want count 0 and no LastUsed", service.Name, service)
+		}
+	}
+
+	if _, err := storage.ResetStats("Missing", false); err == nil {
+		t.Error("ResetStats() should fail for a missing service")
+	}
+}
+
+// TestStorage_TogglePinned tests pinning flips the flag and survives a save
+func TestStorage_TogglePinned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	store, err := Create(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := store.AddService(Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddService() error = %v", err)
+	}
+
+	pinned, err := store.TogglePinned("github")
+	if err != nil || !pinned {
+		t.Fatalf("TogglePinned() = %v, %v; want true, nil", pinned, err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !loaded.Services[0].Pinned {
+		t.Error("Pinned should persist across save and load")
+	}
+
+	if pinned, _ := loaded.TogglePinned("GitHub"); pinned {
+		t.Error("Second TogglePinned() should unpin")
+	}
+	if _, err := loaded.TogglePinned("Missing"); err == nil {
+		t.Error("TogglePinned() should fail for a missing service")
+	}
+}
+
+// TestStorage_RemoveService tests case-insensitive removal keeps the order
+// of the remaining services
+func TestStorage_RemoveService(t *testing.T) {
+	storage := &Storage{
+		Version: 1,
+		Services: []Service{
+			{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP"},
+			{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP"},
+			{Name: "Slack", Secret: "JBSWY3DPEHPK3PXP"},
+		},
+	}
+
+	removed, err := storage.RemoveService("aws")
+	if err != nil {
+		t.Fatalf("RemoveService() error = %v", err)
+	}
+	if removed.Name != "AWS" {
+		t.Errorf("RemoveService() returned %q, want AWS", removed.Name)
+	}
+	if len(storage.Services) != 2 || storage.Services[0].Name != "GitHub" || storage.Services[1].Name != "Slack" {
+		t.Errorf("Services = %+v, want GitHub then Slack", storage.Services)
+	}
+
+	if _, err := storage.RemoveService("AWS"); err == nil {
+		t.Error("RemoveService() should fail for a missing service")
+	}
+}
+
+// TestStorage_UpdateService tests updates keep CreatedAt, are validated and
+// cannot take another service's name
+func TestStorage_UpdateService(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	storage := &Storage{
+		Version: 1,
+		Services: []Service{
+			{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: created},
+			{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: created},
+		},
+	}
+
+	updated := Service{Name: "GitHub Work", Identifier: "octocat", Secret: "GEZDGNBVGY3TQOJQ", Period: 60}
+	if err := storage.UpdateService("github", updated); err != nil {
+		t.Fatalf("UpdateService() error = %v", err)
+	}
+	got := storage.Services[0]
+	if got.Name != "GitHub Work" || got.Identifier != "octocat" || got.Secret != "GEZDGNBVGY3TQOJQ" || got.Period != 60 {
+		t.Errorf("Services[0] = %+v, want the update applied", got)
+	}
+	if !got.CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v, want %v kept", got.CreatedAt, created)
+	}
+
+	// Changing only the case of the name is fine
+	updated.Name = "github work"
+	if err := storage.UpdateService("GitHub Work", updated); err != nil {
+		t.Errorf("UpdateService() case-only rename error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		service Service
+	}{
+		{"Missing service", "Slack", Service{Name: "Slack", Secret: "JBSWY3DPEHPK3PXP"}},
+		{"Name taken", "AWS", Service{Name: "GITHUB WORK", Secret: "JBSWY3DPEHPK3PXP"}},
+		{"Invalid secret", "AWS", Service{Name: "AWS", Secret: "not base32!"}},
+		{"Invalid period", "AWS", Service{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", Period: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := storage.UpdateService(tt.target, tt.service); err == nil {
+				t.Error("UpdateService() expected error")
+			}
+		})
+	}
+	if storage.Services[1].Name != "AWS" || storage.Services[1].Secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Services[1] = %+v, want failed updates to leave it unchanged", storage.Services[1])
+	}
+}
+
+// TestService_Clone tests a clone shares no LastUsed time or recovery codes
+func TestService_Clone(t *testing.T) {
+	lastUsed := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	original := Service{
+		Name:     "GitHub",
+		Secret:   "JBSWY3DPEHPK3PXP",
+		LastUsed: &lastUsed,
+		Recovery: []string{"aaaa-bbbb", "cccc-dddd"},
+	}
+
+	clone := original.Clone()
+	*clone.LastUsed = clone.LastUsed.Add(time.Hour)
+	clone.Recovery[0] = "changed"
+	clone.Recovery = append(clone.Recovery, "extra")
+
+	if !original.LastUsed.Equal(lastUsed) {
+		t.Errorf("original LastUsed = %v, want %v", *original.LastUsed, lastUsed)
+	}
+	if len(original.Recovery) != 2 || original.Recovery[0] != "aaaa-bbbb" {
+		t.Errorf("original Recovery = %v, want unchanged", original.Recovery)
+	}
+
+	// Nil fields stay nil
+	if empty := (Service{Name: "AWS"}).Clone(); empty.LastUsed != nil || empty.Recovery != nil {
+		t.Errorf("Clone() of empty fields = %+v, want nil LastUsed and Recovery", empty)
+	}
+}
+
+// TestService_Code tests code generation dispatches on the service type
+func TestService_Code(t *testing.T) {
+	at := time.Unix(1111111109, 0)
+
+	standard := Service{Name: "RFC", Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"}
+	code, err := standard.Code(at)
+	if err != nil {
+		t.Fatalf("Code() error = %v", err)
+	}
+	if code != "081804" {
+		t.Errorf("TOTP code = %q, want %q", code, "081804")
+	}
+	if standard.CodeLength() != 6 {
+		t.Errorf("TOTP CodeLength() = %d, want 6", standard.CodeLength())
+	}
+
+	steam := Service{Name: "Steam", Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", Type: TypeSteam}
+	code, err = steam.Code(at)
+	if err != nil {
+		t.Fatalf("Code() error = %v", err)
+	}
+	if len(code) != 5 {
+		t.Errorf("Steam code = %q, want 5 characters", code)
+	}
+	if steam.CodeLength() != 5 {
+		t.Errorf("Steam CodeLength() = %d, want 5", steam.CodeLength())
+	}
+}
+
+// TestService_CodeParams tests non-default algorithm, digits and period
+func TestService_CodeParams(t *testing.T) {
+	at := time.Unix(1111111109, 0)
+
+	service := Service{
+		Name:      "Enterprise",
+		Secret:    "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQGEZA",
+		Algorithm: "sha256",
+		Digits:    8,
+	}
+	code, err := service.Code(at)
+	if err != nil {
+		t.Fatalf("Code() error = %v", err)
+	}
+	if code != "68084774" {
+		t.Errorf("SHA256 8-digit code = %q, want %q", code, "68084774")
+	}
+	if service.CodeLength() != 8 {
+		t.Errorf("CodeLength() = %d, want 8", service.CodeLength())
+	}
+	if service.EffectiveAlgorithm() != "SHA256" || service.EffectivePeriod() != 30 {
+		t.Errorf("Effective params = %s/%ds, want SHA256/30s", service.EffectiveAlgorithm(), service.EffectivePeriod())
+	}
+
+	defaults := Service{}
+	if defaults.EffectiveAlgorithm() != "SHA1" || defaults.EffectiveDigits() != 6 || defaults.EffectivePeriod() != 30 {
+		t.Errorf("Defaults = %s/%d/%d, want SHA1/6/30",
+			defaults.EffectiveAlgorithm(), defaults.EffectiveDigits(), defaults.EffectivePeriod())
+	}
+}
+
 // Helper function to create time pointer
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
+
+// TestNameKey_UnicodeDedupeAndLookup tests tricky Unicode and whitespace
+// pairs dedupe, look up and validate consistently
+func TestNameKey_UnicodeDedupeAndLookup(t *testing.T) {
+	const secret = "JBSWY3DPEHPK3PXP"
+
+	tests := []struct {
+		name     string
+		stored   string
+		other    string
+		wantSame bool
+	}{
+		{"ß matches SS", "Straße", "STRASSE", true},
+		{"ß matches ss", "Straße", "strasse", true},
+		{"Composed matches decomposed accent", "Caf\u00e9", "Cafe\u0301", true},
+		{"Plain case difference", "GitHub", "GITHUB", true},
+		{"İ matches its uppercase form", "İstanbul", "İSTANBUL", true},
+		{"İ is not i (no Turkish locale folding)", "İstanbul", "istanbul", false},
+		{"Dotless ı is not i", "ıdea", "idea", false},
+		{"Edge whitespace is ignored", "GitHub", " github ", true},
+		{"Inner whitespace is kept", "Git Hub", "GitHub", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SameName(tt.stored, tt.other); got != tt.wantSame {
+				t.Fatalf("SameName(%q, %q) = %v, want %v", tt.stored, tt.other, got, tt.wantSame)
+			}
+
+			s := &Storage{}
+			if err := s.AddService(Service{Name: tt.stored, Secret: secret}); err != nil {
+				t.Fatalf("AddService(%q) error = %v", tt.stored, err)
+			}
+
+			// Dedupe and lookup agree with SameName
+			addErr := s.AddService(Service{Name: tt.other, Secret: secret})
+			if (addErr != nil) != tt.wantSame {
+				t.Errorf("AddService(%q) error = %v, want duplicate = %v", tt.other, addErr, tt.wantSame)
+			}
+
+			found, err := s.getService(tt.other)
+			if tt.wantSame && (err != nil || found.Name != tt.stored) {
+				t.Errorf("getService(%q) = %v, %v; want %q", tt.other, found, err, tt.stored)
+			}
+			if !tt.wantSame && (err != nil || found.Name != tt.other) {
+				t.Errorf("getService(%q) = %v, %v; want the separately added service", tt.other, found, err)
+			}
+
+			// Validate (doctor) flags exactly the pairs the store rejects
+			both := &Storage{Services: []Service{{Name: tt.stored, Secret: secret}, {Name: tt.other, Secret: secret}}}
+			if errs := both.Validate(); (len(errs) > 0) != tt.wantSame {
+				t.Errorf("Validate() = %v, want duplicate = %v", errs, tt.wantSame)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LessFunc reports whether service a sorts before service b
+type LessFunc func(a, b Service) bool
+
+// ByName orders services by name, case-insensitively
+func ByName(a, b Service) bool {
+	return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+}
+
+// ByCreated orders services oldest first
+func ByCreated(a, b Service) bool {
+	return a.CreatedAt.Before(b.CreatedAt)
+}
+
+// ByLastUsed orders services most recently used first; never-used services last
+func ByLastUsed(a, b Service) bool {
+	if a.LastUsed == nil {
+		return false
+	}
+	if b.LastUsed == nil {
+		return true
+	}
+	return a.LastUsed.After(*b.LastUsed)
+}
+
+// ParseSortOrder maps a sort option (name, created, used) to its comparator
+func ParseSortOrder(order string) (LessFunc, error) {
+	switch order {
+	case "name":
+		return ByName, nil
+	case "created":
+		return ByCreated, nil
+	case "used":
+		return ByLastUsed, nil
+	default:
+		return nil, fmt.Errorf("unknown sort order %q (use name, created or used)", order)
+	}
+}
+
+// SortedServices returns a stably sorted copy, leaving the stored order
+// untouched. Pinned services always come first; a nil less keeps the stored
+// order within the pinned and unpinned groups.
+func SortedServices(services []Service, less LessFunc) []Service {
+	sorted := make([]Service, len(services))
+	copy(sorted, services)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Pinned != sorted[j].Pinned {
+			return sorted[i].Pinned
+		}
+		return less != nil && less(sorted[i], sorted[j])
+	})
+	return sorted
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+// sortTestServices returns services whose name, created and used orders all differ
+func sortTestServices() []Service {
+	used := func(unix int64) *time.Time {
+		t := time.Unix(unix, 0)
+		return &t
+	}
+	return []Service{
+		{Name: "gitHub", CreatedAt: time.Unix(300, 0), LastUsed: used(1000)},
+		{Name: "AWS", CreatedAt: time.Unix(200, 0)},
+		{Name: "Slack", CreatedAt: time.Unix(100, 0), LastUsed: used(2000)},
+	}
+}
+
+// TestSortedServices tests each sort order and that the input is untouched
+func TestSortedServices(t *testing.T) {
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"name", []string{"AWS", "gitHub", "Slack"}},
+		{"created", []string{"Slack", "AWS", "gitHub"}},
+		{"used", []string{"Slack", "gitHub", "AWS"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			less, err := ParseSortOrder(tt.order)
+			if err != nil {
+				t.Fatalf("ParseSortOrder() error = %v", err)
+			}
+
+			services := sortTestServices()
+			sorted := SortedServices(services, less)
+			for i, name := range tt.want {
+				if sorted[i].Name != name {
+					t.Errorf("sorted[%d] = %s, want %s", i, sorted[i].Name, name)
+				}
+			}
+			if services[0].Name != "gitHub" {
+				t.Error("SortedServices must not reorder its input")
+			}
+		})
+	}
+}
+
+// TestParseSortOrder_Unknown tests unknown orders are rejected
+func TestParseSortOrder_Unknown(t *testing.T) {
+	if _, err := ParseSortOrder("size"); err == nil {
+		t.Error("Expected error for unknown sort order")
+	}
+}
+
+// TestSortedServices_PinnedFirst tests pinned services lead in their relative order
+func TestSortedServices_PinnedFirst(t *testing.T) {
+	services := []Service{
+		{Name: "Zeta", Pinned: true},
+		{Name: "alpha"},
+		{Name: "Beta", Pinned: true},
+		{Name: "gamma"},
+	}
+
+	byName := SortedServices(services, ByName)
+	want := []string{"Beta", "Zeta", "alpha", "gamma"}
+	for i, name := range want {
+		if byName[i].Name != name {
+			t.Errorf("by name: sorted[%d] = %s, want %s", i, byName[i].Name, name)
+		}
+	}
+
+	stored := SortedServices(services, nil)
+	want = []string{"Zeta", "Beta", "alpha", "gamma"}
+	for i, name := range want {
+		if stored[i].Name != name {
+			t.Errorf("stored order: sorted[%d] = %s, want %s", i, stored[i].Name, name)
+		}
+	}
+}
@@ -1,30 +1,252 @@
 package storage
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/pavanprakash21/totp-manager-go/internal/crypto"
 )
 
+// File format versions (the 4-byte header field)
+const (
+	// formatVersionPlain stores the JSON as-is before encryption
+	formatVersionPlain = 1
+
+	// formatVersionGzip gzip-compresses the JSON before encryption
+	formatVersionGzip = 2
+
+	// formatVersionCipher adds a cipher byte and a flags byte after the
+	// version so ciphers other than AES-256-GCM (with their own nonce
+	// size) can be used; AES-GCM stores keep writing versions 1 and 2
+	formatVersionCipher = 3
+
+	// formatVersionKDF adds a KDF byte after the version 3 fields so stores
+	// can use a KDF other than Argon2id; Argon2id stores keep writing
+	// versions 1 to 3
+	formatVersionKDF = 4
+)
+
+// flagGzip marks a gzip-compressed payload in the version 3 flags byte
+const flagGzip = 1 << 0
+
+// saltLength is the Argon2id salt size stored in every header
+const saltLength = 16
+
+// authTagSize is the AEAD tag every payload ends with (both ciphers use 16)
+const authTagSize = 16
+
+// ErrNewerVersion is returned when a file's format version is newer than
+// this build understands, i.e. it was written by a newer release
+var ErrNewerVersion = errors.New("storage file was created by a newer version of totp-manager")
+
+// ErrCorrupted is returned when a file's header does not match the layout
+// of its declared format version (e.g. truncated, or an unknown flag,
+// cipher or KDF byte), so retrying the passphrase cannot help
+var ErrCorrupted = errors.New("storage file is corrupted")
+
+// SupportedFormatVersions returns the file format versions Load can read
+func SupportedFormatVersions() []int {
+	return []int{formatVersionPlain, formatVersionGzip, formatVersionCipher, formatVersionKDF}
+}
+
+// Header is the unencrypted file header; reading it needs no passphrase
+type Header struct {
+	Version       uint32
+	Cipher        crypto.Cipher
+	KDF           crypto.KDF
+	SaltLength    int
+	NonceLength   int
+	PayloadLength int // ciphertext including the 16-byte auth tag
+
+	compressed bool
+	saltOffset int
+}
+
+// Compressed reports whether the encrypted payload is gzip-compressed JSON
+func (h Header) Compressed() bool {
+	return h.compressed
+}
+
+// ReadHeader reads a storage file's header without decrypting it
+func ReadHeader(path string) (Header, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Header{}, fmt.Errorf("failed to read storage file: %w", err)
+	}
+	return parseHeader(data)
+}
+
+// parseHeader validates the file layout. Versions 1 and 2 (AES-256-GCM):
+// [4 bytes: Version]
+// [16 bytes: Salt]
+// [12 bytes: Nonce]
+// [N bytes: Encrypted JSON + Auth Tag] (gzip-compressed JSON for version 2)
+// Version 3 inserts [1 byte: Cipher] [1 byte: Flags] after the version and
+// uses the cipher's nonce size. Version 4 adds [1 byte: KDF] after the flags.
+func parseHeader(data []byte) (Header, error) {
+	if len(data) < 4 {
+		return Header{}, fmt.Errorf("%w: %d bytes is too short for a header", ErrCorrupted, len(data))
+	}
+
+	header := Header{
+		Version:    binary.LittleEndian.Uint32(data[0:4]),
+		Cipher:     crypto.CipherAESGCM,
+		KDF:        crypto.KDFArgon2id,
+		SaltLength: saltLength,
+		saltOffset: 4,
+	}
+
+	switch header.Version {
+	case formatVersionPlain:
+	case formatVersionGzip:
+		header.compressed = true
+	case formatVersionCipher, formatVersionKDF:
+		fixed := 6
+		if header.Version == formatVersionKDF {
+			fixed = 7
+		}
+		if len(data) < fixed {
+			return Header{}, fmt.Errorf("%w: %d bytes is too short for a format version %d header", ErrCorrupted, len(data), header.Version)
+		}
+		if flags := data[5]; flags&^flagGzip != 0 {
+			return Header{}, fmt.Errorf("%w: unknown header flags %#02x", ErrCorrupted, flags)
+		}
+		header.Cipher = crypto.Cipher(data[4])
+		header.compressed = data[5]&flagGzip != 0
+		if header.Version == formatVersionKDF {
+			header.KDF = crypto.KDF(data[6])
+			if !header.KDF.Known() {
+				return Header{}, fmt.Errorf("%w: unsupported storage kdf: %s", ErrCorrupted, header.KDF)
+			}
+		}
+		header.saltOffset = fixed
+	default:
+		if header.Version > formatVersionKDF {
+			return Header{}, fmt.Errorf("%w (format version %d, this build reads up to %d): upgrade to open it",
+				ErrNewerVersion, header.Version, formatVersionKDF)
+		}
+		return Header{}, fmt.Errorf("%w: unsupported storage version: %d", ErrCorrupted, header.Version)
+	}
+
+	header.NonceLength = header.Cipher.NonceSize()
+	if header.NonceLength == 0 {
+		return Header{}, fmt.Errorf("%w: unsupported storage cipher: %s", ErrCorrupted, header.Cipher)
+	}
+
+	// The payload holds at least the 16-byte auth tag
+	payloadOffset := header.saltOffset + header.SaltLength + header.NonceLength
+	if minimum := payloadOffset + authTagSize; len(data) < minimum {
+		return Header{}, fmt.Errorf("%w: %d bytes is too short for format version %d with %s (needs at least %d)",
+			ErrCorrupted, len(data), header.Version, header.Cipher, minimum)
+	}
+	header.PayloadLength = len(data) - payloadOffset
+
+	return header, nil
+}
+
+// split returns the salt, nonce and ciphertext of data, checking the salt,
+// the cipher's nonce and the auth tag all fit so nothing is misread
+func (h Header) split(data []byte) (salt, nonce, ciphertext []byte, err error) {
+	nonceOffset := h.saltOffset + h.SaltLength
+	payloadOffset := nonceOffset + h.NonceLength
+	if h.saltOffset < 4 || h.SaltLength <= 0 || h.NonceLength != h.Cipher.NonceSize() || len(data) < payloadOffset+authTagSize {
+		return nil, nil, nil, fmt.Errorf("%w: header layout does not fit the file (%d bytes)", ErrCorrupted, len(data))
+	}
+	return data[h.saltOffset:nonceOffset], data[nonceOffset:payloadOffset], data[payloadOffset:], nil
+}
+
+// encodeHeader builds the header bytes for the given cipher, KDF and
+// compression, returning them along with the format version they use
+func encodeHeader(c crypto.Cipher, k crypto.KDF, compressed bool) ([]byte, int) {
+	if c == crypto.CipherAESGCM && k == crypto.KDFArgon2id {
+		version := formatVersionPlain
+		if compressed {
+			version = formatVersionGzip
+		}
+		header := make([]byte, 4)
+		binary.LittleEndian.PutUint32(header, uint32(version))
+		return header, version
+	}
+
+	var flags byte
+	if compressed {
+		flags |= flagGzip
+	}
+	if k != crypto.KDFArgon2id {
+		header := make([]byte, 7)
+		binary.LittleEndian.PutUint32(header[0:4], formatVersionKDF)
+		header[4] = byte(c)
+		header[5] = flags
+		header[6] = byte(k)
+		return header, formatVersionKDF
+	}
+
+	header := make([]byte, 6)
+	binary.LittleEndian.PutUint32(header[0:4], formatVersionCipher)
+	header[4] = byte(c)
+	header[5] = flags
+	return header, formatVersionCipher
+}
+
 // Store manages encrypted TOTP service storage
 type Store struct {
 	path       string
 	passphrase string
+	noAtomic   bool
 	*Storage
 }
 
+// staleTempAge is how old a leftover *.tmp file must be before Load removes
+// it; younger files may belong to a Save running in another process
+const staleTempAge = 5 * time.Minute
+
+// rename is os.Rename, replaceable in tests to simulate filesystems
+// where renaming over an existing file fails
+var rename = os.Rename
+
+// writeFile is os.WriteFile, replaceable in tests to simulate directories
+// the user cannot write to
+var writeFile = os.WriteFile
+
+// ErrDirNotWritable is returned when the storage directory does not allow
+// creating files (wrong permissions or a read-only filesystem)
+var ErrDirNotWritable = errors.New("storage directory is not writable")
+
+// writeProbeName is the file Create writes (and removes) to check the
+// directory is writable before anything is encrypted
+const writeProbeName = ".write-check.tmp"
+
 // Create creates a new encrypted storage file
 func Create(path, passphrase string) (*Store, error) {
 	// Ensure directory exists
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0700); err != nil {
+		if isPermissionError(err) {
+			return nil, notWritableError(dir)
+		}
 		return nil, fmt.Errorf("failed to create directory: %w", err)
 	}
 
+	// Fail now rather than on the first Save
+	probePath := filepath.Join(dir, writeProbeName)
+	if err := writeFile(probePath, nil, 0600); err != nil {
+		if isPermissionError(err) {
+			return nil, notWritableError(dir)
+		}
+		return nil, fmt.Errorf("failed to write to storage directory: %w", err)
+	}
+	os.Remove(probePath)
+
 	// Generate salt for key derivation
 	salt, err := crypto.GenerateSalt()
 	if err != nil {
@@ -35,7 +257,7 @@ func Create(path, passphrase string) (*Store, error) {
 		path:       path,
 		passphrase: passphrase,
 		Storage: &Storage{
-			Version:  1,
+			Version:  formatVersionPlain,
 			Services: []Service{},
 			Salt:     salt,
 		},
@@ -46,51 +268,57 @@ func Create(path, passphrase string) (*Store, error) {
 
 // Load loads and decrypts an existing storage file
 func Load(path, passphrase string) (*Store, error) {
+	// A process killed between writing and renaming leaves its temp file behind
+	removeStaleTemps(path, staleTempAge)
+
 	// Read file
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read storage file: %w", err)
 	}
 
-	// Parse file format:
-	// [4 bytes: Version]
-	// [16 bytes: Salt]
-	// [12 bytes: Nonce]
-	// [N bytes: Encrypted JSON + Auth Tag]
-
-	if len(data) < 4+16+12+16 {
-		return nil, fmt.Errorf("invalid storage file: too short")
+	header, err := parseHeader(data)
+	if err != nil {
+		return nil, err
 	}
 
-	// Read version
-	version := binary.LittleEndian.Uint32(data[0:4])
-	if version != 1 {
-		return nil, fmt.Errorf("unsupported storage version: %d", version)
+	salt, nonce, ciphertext, err := header.split(data)
+	if err != nil {
+		return nil, err
 	}
 
-	// Read salt and nonce
-	salt := data[4:20]
-	nonce := data[20:32]
-	ciphertext := data[32:]
-
-	// Derive key from passphrase
-	key, err := crypto.DeriveKey(passphrase, salt)
+	// Derive key from passphrase with the KDF the file was written with
+	key, err := crypto.DeriveKeyWith(header.KDF, passphrase, salt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to derive key: %w", err)
 	}
 
 	// Decrypt
-	plaintext, err := crypto.Decrypt(ciphertext, key, nonce)
+	plaintext, err := crypto.Decrypt(header.Cipher, ciphertext, key, nonce)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt storage (wrong passphrase?): %w", err)
 	}
 
+	if header.Compressed() {
+		plaintext, err = decompress(plaintext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress storage: %w", err)
+		}
+	}
+
 	// Unmarshal JSON
 	var storage Storage
 	if err := json.Unmarshal(plaintext, &storage); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal storage: %w", err)
 	}
 
+	// Keep a non-default KDF on re-save, so a store from a build without
+	// Argon2id stays readable there
+	if storage.Settings.KDF == "" && header.KDF != crypto.DefaultKDF {
+		storage.Settings.KDF = header.KDF.String()
+	}
+
+	storage.Version = int(header.Version)
 	storage.Salt = salt
 	storage.Nonce = nonce
 
@@ -103,78 +331,305 @@ func Load(path, passphrase string) (*Store, error) {
 	return store, nil
 }
 
-// Save encrypts and saves storage to disk (atomic write)
+// Save encrypts and saves storage to disk (atomic write). Every
+// Settings.RekeyAfterSaves saves a fresh salt is generated, so no single
+// key encrypts enough payloads for random nonces to risk colliding.
 func (s *Store) Save() error {
+	salt := s.Salt
+	saves := s.SavesSinceRekey + 1
+	if saves >= s.Settings.rekeyThreshold() {
+		newSalt, err := crypto.GenerateSalt()
+		if err != nil {
+			return fmt.Errorf("failed to generate new salt: %w", err)
+		}
+		salt = newSalt
+		saves = 0
+	}
+	return s.saveWith(salt, saves)
+}
+
+// saveWith encrypts storage under salt with the given rekey counter and
+// writes it. The counter is part of the encrypted payload, so it is set for
+// the write but restored if the write fails; otherwise a failed save right
+// after the rekey decision would postpone the rotation another N saves.
+func (s *Store) saveWith(salt []byte, saves int) error {
+	previous := s.SavesSinceRekey
+	s.SavesSinceRekey = saves
+
+	fileData, nonce, err := encryptStorage(s.Storage, s.passphrase, salt)
+	if err == nil {
+		err = s.write(fileData)
+	}
+	if err != nil {
+		s.SavesSinceRekey = previous
+		return err
+	}
+
+	// Update salt and nonce in memory
+	s.Salt = salt
+	s.Nonce = nonce
+	return nil
+}
+
+// write stores fileData at the store's path: atomically through a temp
+// file, or in place after a backup with noAtomic
+func (s *Store) write(fileData []byte) error {
+	if s.noAtomic {
+		return writeInPlace(s.path, fileData)
+	}
+
+	// Atomic write: write to temp file, then rename
+	tmpPath := s.path + ".tmp"
+
+	// Write temp file with 0600 permissions
+	if err := writeFile(tmpPath, fileData, 0600); err != nil {
+		if isPermissionError(err) {
+			return notWritableError(filepath.Dir(s.path))
+		}
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	// Rename temp file to actual file (atomic on Unix)
+	if err := rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath) // Clean up temp file on error
+		return fmt.Errorf("failed to rename temp file (if this filesystem does not support atomic renames, retry with --no-atomic): %w", err)
+	}
+
+	return nil
+}
+
+// encryptStorage builds the file content for st: the header, salt, nonce
+// and the encrypted (optionally compressed) JSON, keyed from passphrase and
+// salt. It sets st.Version to the format version written.
+func encryptStorage(st *Storage, passphrase string, salt []byte) (fileData, nonce []byte, err error) {
+	k, err := crypto.ParseKDF(st.Settings.KDF)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Derive key from passphrase
-	key, err := crypto.DeriveKey(s.passphrase, s.Salt)
+	key, err := crypto.DeriveKeyWith(k, passphrase, salt)
 	if err != nil {
-		return fmt.Errorf("failed to derive key: %w", err)
+		return nil, nil, fmt.Errorf("failed to derive key: %w", err)
 	}
 
+	c, err := crypto.ParseCipher(st.Settings.Cipher)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The header version records the cipher, KDF and whether the JSON is compressed
+	header, version := encodeHeader(c, k, st.Settings.Compress)
+	st.Version = version
+
 	// Marshal storage to JSON
-	jsonData, err := json.Marshal(s.Storage)
+	jsonData, err := json.Marshal(st)
 	if err != nil {
-		return fmt.Errorf("failed to marshal storage: %w", err)
+		return nil, nil, fmt.Errorf("failed to marshal storage: %w", err)
+	}
+
+	if st.Settings.Compress {
+		jsonData, err = compress(jsonData)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to compress storage: %w", err)
+		}
 	}
 
 	// Encrypt
-	ciphertext, nonce, err := crypto.Encrypt(jsonData, key)
+	ciphertext, nonce, err := crypto.Encrypt(c, jsonData, key)
 	if err != nil {
-		return fmt.Errorf("failed to encrypt storage: %w", err)
+		return nil, nil, fmt.Errorf("failed to encrypt storage: %w", err)
 	}
 
 	// Build file content
-	// [4 bytes: Version] [16 bytes: Salt] [12 bytes: Nonce] [N bytes: Ciphertext + Auth Tag]
-	fileData := make([]byte, 4+16+12+len(ciphertext))
-	binary.LittleEndian.PutUint32(fileData[0:4], uint32(s.Version))
-	copy(fileData[4:20], s.Salt)
-	copy(fileData[20:32], nonce)
-	copy(fileData[32:], ciphertext)
+	// [Header] [16 bytes: Salt] [Nonce] [N bytes: Ciphertext + Auth Tag]
+	fileData = make([]byte, 0, len(header)+len(salt)+len(nonce)+len(ciphertext))
+	fileData = append(fileData, header...)
+	fileData = append(fileData, salt...)
+	fileData = append(fileData, nonce...)
+	fileData = append(fileData, ciphertext...)
+
+	return fileData, nonce, nil
+}
 
-	// Atomic write: write to temp file, then rename
-	tmpPath := s.path + ".tmp"
+// EncryptedBackup returns an encrypted backup of the store, keyed from
+// passphrase under a fresh salt and nonce. The backup uses the regular
+// storage format, so Load opens it like any store file. An empty passphrase
+// reuses the store's own.
+func (s *Store) EncryptedBackup(passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		passphrase = s.passphrase
+	}
 
-	// Write temp file with 0600 permissions
-	if err := os.WriteFile(tmpPath, fileData, 0600); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
+	salt, err := crypto.GenerateSalt()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
 	}
 
-	// Rename temp file to actual file (atomic on Unix)
-	if err := os.Rename(tmpPath, s.path); err != nil {
-		os.Remove(tmpPath) // Clean up temp file on error
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	backup := *s.Storage
+	backup.Services = make([]Service, len(s.Services))
+	for i := range s.Services {
+		backup.Services[i] = s.Services[i].Clone()
 	}
+	backup.SavesSinceRekey = 0
 
-	// Update nonce in memory
-	s.Nonce = nonce
+	fileData, _, err := encryptStorage(&backup, passphrase, salt)
+	return fileData, err
+}
+
+// isPermissionError reports whether err means the process may not write
+// there: permission denied or a read-only filesystem
+func isPermissionError(err error) bool {
+	return errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EROFS)
+}
 
+// notWritableError explains how to recover from an unwritable storage directory
+func notWritableError(dir string) error {
+	return fmt.Errorf("%w: %s (fix its permissions, e.g. chmod u+w %q, or choose a writable directory with --storage or %s)",
+		ErrDirNotWritable, dir, dir, StorageDirEnvVar)
+}
+
+// removeStaleTemps deletes the store's own temp files (its atomic write
+// temp and the Create write probe) last modified more than maxAge ago. Other
+// *.tmp files are left alone: the directory may be shared or the cwd.
+// Cleanup is best-effort: errors are ignored so loading is never blocked.
+func removeStaleTemps(path string, maxAge time.Duration) {
+	for _, tmpPath := range []string{path + ".tmp", filepath.Join(filepath.Dir(path), writeProbeName)} {
+		info, err := os.Lstat(tmpPath)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if time.Since(info.ModTime()) > maxAge {
+			os.Remove(tmpPath)
+		}
+	}
+}
+
+// Path returns the storage file path
+func (s *Store) Path() string {
+	return s.path
+}
+
+// SetNoAtomic makes Save overwrite the file in place (after backing it up)
+// instead of renaming a temp file over it. Less safe: a crash mid-write can
+// leave a truncated file, recoverable only from the backup.
+func (s *Store) SetNoAtomic(noAtomic bool) {
+	s.noAtomic = noAtomic
+}
+
+// writeInPlace backs up the existing file to path+".bak", overwrites path,
+// and removes the backup once the new data is synced to disk
+func writeInPlace(path string, data []byte) error {
+	backupPath := path + ".bak"
+
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := writeFile(backupPath, existing, 0600); err != nil {
+			if isPermissionError(err) {
+				return notWritableError(filepath.Dir(path))
+			}
+			return fmt.Errorf("failed to write backup file: %w", err)
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("failed to read storage file for backup: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open storage file: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write storage file (backup kept at %s): %w", backupPath, err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync storage file (backup kept at %s): %w", backupPath, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close storage file (backup kept at %s): %w", backupPath, err)
+	}
+
+	os.Remove(backupPath)
 	return nil
 }
 
-// ChangePassphrase re-encrypts storage with a new passphrase
+// compress gzips data
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress gunzips data
+func decompress(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// ChangePassphrase re-encrypts storage with a new passphrase. If the write
+// fails the old passphrase stays in use, so later saves from a long-lived
+// process still match the file.
 func (s *Store) ChangePassphrase(newPassphrase string) error {
+	oldPassphrase := s.passphrase
+	s.passphrase = newPassphrase
+	if err := s.Reencrypt(); err != nil {
+		s.passphrase = oldPassphrase
+		return err
+	}
+	return nil
+}
+
+// Reencrypt rewrites the file under a fresh salt using the current format,
+// cipher and key derivation, even if it is already up to date
+func (s *Store) Reencrypt() error {
 	// Generate new salt
 	newSalt, err := crypto.GenerateSalt()
 	if err != nil {
 		return fmt.Errorf("failed to generate new salt: %w", err)
 	}
 
-	// Update passphrase and salt
-	s.passphrase = newPassphrase
-	s.Salt = newSalt
-
-	// Save with new passphrase (atomic)
-	return s.Save()
+	// Save with the current passphrase, starting a new rekey count
+	return s.saveWith(newSalt, 0)
 }
 
+const (
+	// StorageDirEnvVar overrides the directory holding secrets.enc
+	StorageDirEnvVar = "TOTP_STORAGE_DIR"
+
+	// AllowCwdStorageEnvVar opts in to using the current directory when
+	// no home or config directory is available (e.g., minimal containers)
+	AllowCwdStorageEnvVar = "TOTP_ALLOW_CWD_STORAGE"
+)
+
 // GetDefaultStoragePath returns the default storage path
 func GetDefaultStoragePath() (string, error) {
+	// Explicit override wins
+	if dir := os.Getenv(StorageDirEnvVar); dir != "" {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s: %w", StorageDirEnvVar, err)
+		}
+		return filepath.Join(absDir, "secrets.enc"), nil
+	}
+
 	// Use XDG_CONFIG_HOME or ~/.config
 	configDir := os.Getenv("XDG_CONFIG_HOME")
 	if configDir == "" {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return "", fmt.Errorf("failed to get home directory: %w", err)
+			return fallbackStoragePath(err)
 		}
 		configDir = filepath.Join(homeDir, ".config")
 	}
@@ -184,3 +639,19 @@ func GetDefaultStoragePath() (string, error) {
 
 	return storagePath, nil
 }
+
+// fallbackStoragePath uses the current directory when explicitly allowed,
+// otherwise explains how to choose a storage location
+func fallbackStoragePath(homeErr error) (string, error) {
+	if os.Getenv(AllowCwdStorageEnvVar) != "1" {
+		return "", fmt.Errorf("cannot determine storage location (%v): neither HOME nor XDG_CONFIG_HOME is set; "+
+			"choose a directory for secrets.enc with --storage or %s, or set %s=1 to use the current directory",
+			homeErr, StorageDirEnvVar, AllowCwdStorageEnvVar)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current directory: %w", err)
+	}
+	return filepath.Join(cwd, "secrets.enc"), nil
+}
@@ -1,10 +1,19 @@
 package storage
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/crypto"
 )
 
 // TestStore_CreateAndLoad tests creating and loading encrypted storage
@@ -160,6 +169,266 @@ func TestStore_AtomicWrite(t *testing.T) {
 	}
 }
 
+// failRename stubs rename to fail like a filesystem without atomic renames
+func failRename(t *testing.T) {
+	t.Helper()
+	orig := rename
+	rename = func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: errors.New("operation not supported")}
+	}
+	t.Cleanup(func() { rename = orig })
+}
+
+// denyWrites makes every file write fail as in a read-only directory
+func denyWrites(t *testing.T) {
+	t.Helper()
+	orig := writeFile
+	writeFile = func(name string, _ []byte, _ os.FileMode) error {
+		return &os.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+	}
+	t.Cleanup(func() { writeFile = orig })
+}
+
+// TestStore_ReadOnlyDirectory tests create and save report an actionable
+// error instead of the raw OS error when the directory is not writable
+func TestStore_ReadOnlyDirectory(t *testing.T) {
+	checkErr := func(t *testing.T, err error) {
+		t.Helper()
+		if !errors.Is(err, ErrDirNotWritable) {
+			t.Fatalf("error = %v, want ErrDirNotWritable", err)
+		}
+		if !strings.Contains(err.Error(), StorageDirEnvVar) || !strings.Contains(err.Error(), "--storage") || strings.Contains(err.Error(), "permission denied") {
+			t.Errorf("error = %q, want guidance without the raw OS error", err)
+		}
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		denyWrites(t)
+		_, err := Create(filepath.Join(t.TempDir(), "test-secrets.enc"), "test-passphrase")
+		checkErr(t, err)
+	})
+
+	for _, noAtomic := range []bool{false, true} {
+		t.Run(fmt.Sprintf("Save noAtomic=%v", noAtomic), func(t *testing.T) {
+			storePath := filepath.Join(t.TempDir(), "test-secrets.enc")
+			store := manyServiceStore(t, storePath, 1, false)
+			store.SetNoAtomic(noAtomic)
+
+			denyWrites(t)
+			checkErr(t, store.Save())
+		})
+	}
+}
+
+// TestStore_ReadOnlyDirectory_Chmod tests the check against real permissions
+func TestStore_ReadOnlyDirectory_Chmod(t *testing.T) {
+	if os.Geteuid() == 0 || runtime.GOOS == "windows" {
+		t.Skip("directory permissions are not enforced for root or on Windows")
+	}
+
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0500); err != nil {
+		t.Fatalf("Chmod() error = %v", err)
+	}
+	t.Cleanup(func() { os.Chmod(dir, 0700) })
+
+	if _, err := Create(filepath.Join(dir, "test-secrets.enc"), "test-passphrase"); !errors.Is(err, ErrDirNotWritable) {
+		t.Errorf("Create() error = %v, want ErrDirNotWritable", err)
+	}
+}
+
+// TestStore_RenameFailureSuggestsNoAtomic tests a failed rename points at --no-atomic
+func TestStore_RenameFailureSuggestsNoAtomic(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "test-secrets.enc")
+	failRename(t)
+
+	store, err := Create(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	err = store.Save()
+	if err == nil {
+		t.Fatal("Save() should fail when rename fails")
+	}
+	if !contains(err.Error(), "--no-atomic") {
+		t.Errorf("Error should suggest --no-atomic, got: %v", err)
+	}
+	if _, statErr := os.Stat(storePath + ".tmp"); !os.IsNotExist(statErr) {
+		t.Error("Temp file should be cleaned up after a failed rename")
+	}
+}
+
+// TestStore_NoAtomicSave tests in-place saving persists data when rename fails
+func TestStore_NoAtomicSave(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "test-secrets.enc")
+	failRename(t)
+
+	store, err := Create(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	store.SetNoAtomic(true)
+
+	// First save creates the file, second overwrites it in place
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	err = store.AddService(Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("AddService() error = %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.Services) != 1 || loaded.Services[0].Name != "GitHub" {
+		t.Errorf("Loaded services = %v, want [GitHub]", loaded.Services)
+	}
+
+	info, err := os.Stat(storePath)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("File permissions = %o, want 0600", perm)
+	}
+
+	// The backup is removed once the write succeeds
+	if _, err := os.Stat(storePath + ".bak"); !os.IsNotExist(err) {
+		t.Error("Backup file should be removed after a successful save")
+	}
+}
+
+// TestStore_LoadRemovesStaleTemps tests that Load deletes temp files left by
+// an interrupted Save but keeps a fresh one that may belong to a running
+// Save, and never touches other *.tmp files
+func TestStore_LoadRemovesStaleTemps(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "secrets.enc")
+
+	store, err := Create(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	stalePath := storePath + ".tmp"
+	freshPath := filepath.Join(tmpDir, writeProbeName)
+	unrelatedPath := filepath.Join(tmpDir, "notes.tmp")
+	for _, p := range []string{stalePath, freshPath, unrelatedPath} {
+		if err := os.WriteFile(p, []byte("partial"), 0600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	old := time.Now().Add(-staleTempAge - time.Minute)
+	for _, p := range []string{stalePath, unrelatedPath} {
+		if err := os.Chtimes(p, old, old); err != nil {
+			t.Fatalf("Chtimes() error = %v", err)
+		}
+	}
+
+	if _, err := Load(storePath, "test-passphrase"); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if _, err := os.Stat(stalePath); !os.IsNotExist(err) {
+		t.Errorf("Stale temp file should be removed, stat error = %v", err)
+	}
+	if _, err := os.Stat(freshPath); err != nil {
+		t.Errorf("Fresh temp file should be kept, stat error = %v", err)
+	}
+	if _, err := os.Stat(unrelatedPath); err != nil {
+		t.Errorf("Temp files the store did not write should be kept, stat error = %v", err)
+	}
+}
+
+// TestStore_RekeyAfterSaves tests the salt rotates once the save threshold
+// is reached and the store still opens with the same passphrase
+func TestStore_RekeyAfterSaves(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "test-secrets.enc")
+	store, err := Create(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	store.Settings.RekeyAfterSaves = 3
+	originalSalt := append([]byte(nil), store.Salt...)
+
+	for i := 1; i <= 2; i++ {
+		if err := store.Save(); err != nil {
+			t.Fatalf("Save() #%d error = %v", i, err)
+		}
+		if !bytes.Equal(store.Salt, originalSalt) {
+			t.Fatalf("Salt changed after %d saves, threshold is 3", i)
+		}
+	}
+
+	loaded, err := Load(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.SavesSinceRekey != 2 {
+		t.Errorf("SavesSinceRekey = %d, want 2 (persisted)", loaded.SavesSinceRekey)
+	}
+
+	if err := loaded.Save(); err != nil {
+		t.Fatalf("Save() #3 error = %v", err)
+	}
+	if bytes.Equal(loaded.Salt, originalSalt) {
+		t.Error("Salt should rotate on the third save")
+	}
+
+	rekeyed, err := Load(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() after rekey error = %v", err)
+	}
+	if !bytes.Equal(rekeyed.Salt, loaded.Salt) || rekeyed.SavesSinceRekey != 0 {
+		t.Errorf("After rekey: salt persisted %t, SavesSinceRekey = %d, want true and 0",
+			bytes.Equal(rekeyed.Salt, loaded.Salt), rekeyed.SavesSinceRekey)
+	}
+}
+
+// TestStore_RekeyAfterFailedSave tests a failed save leaves the rekey
+// counter and salt alone, so the next save still rotates the salt
+func TestStore_RekeyAfterFailedSave(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "test-secrets.enc")
+	store, err := Create(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	store.Settings.RekeyAfterSaves = 2
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	originalSalt := append([]byte(nil), store.Salt...)
+
+	t.Run("failed save", func(t *testing.T) {
+		failRename(t)
+		if err := store.Save(); err == nil {
+			t.Fatal("Save() should fail")
+		}
+	})
+	if store.SavesSinceRekey != 1 || !bytes.Equal(store.Salt, originalSalt) {
+		t.Fatalf("After a failed save: SavesSinceRekey = %d, salt changed %t, want 1 and false",
+			store.SavesSinceRekey, !bytes.Equal(store.Salt, originalSalt))
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if bytes.Equal(store.Salt, originalSalt) || store.SavesSinceRekey != 0 {
+		t.Error("The save after the failure should rotate the salt")
+	}
+}
+
 // TestStore_EncryptedContent tests that file content is encrypted
 func TestStore_EncryptedContent(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -309,6 +578,506 @@ func TestStore_MultipleServices(t *testing.T) {
 	}
 }
 
+// TestStore_RecoveryCodesRoundTrip tests recovery codes persist through save/load
+func TestStore_RecoveryCodesRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "test-secrets.enc")
+
+	store, err := Create(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	recovery := []string{"abcd-efgh", "ijkl-mnop"}
+	err = store.AddService(Service{
+		Name:      "GitHub",
+		Secret:    "JBSWY3DPEHPK3PXP",
+		CreatedAt: time.Now(),
+		Recovery:  recovery,
+	})
+	if err != nil {
+		t.Fatalf("AddService() error = %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Recovery codes are encrypted like everything else
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("Failed to read storage file: %v", err)
+	}
+	if contains(string(data), "abcd-efgh") {
+		t.Error("Recovery codes should not appear in plaintext on disk")
+	}
+
+	loaded, err := Load(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	got := loaded.Services[0].Recovery
+	if len(got) != len(recovery) || got[0] != recovery[0] || got[1] != recovery[1] {
+		t.Errorf("Recovery = %v, want %v", got, recovery)
+	}
+}
+
+// TestStore_URLRoundTrip tests the login URL survives save and load
+func TestStore_URLRoundTrip(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "test-secrets.enc")
+
+	store, err := Create(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	err = store.AddService(Service{
+		Name:      "GitHub",
+		Secret:    "JBSWY3DPEHPK3PXP",
+		CreatedAt: time.Now(),
+		URL:       "https://github.com/login",
+	})
+	if err != nil {
+		t.Fatalf("AddService() error = %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded.Services[0].URL; got != "https://github.com/login" {
+		t.Errorf("URL = %q, want https://github.com/login", got)
+	}
+}
+
+// requireArgon2Default skips tests asserting the version 1-3 header layouts,
+// which builds defaulting to scrypt (-tags noargon2) never write
+func requireArgon2Default(t *testing.T) {
+	t.Helper()
+	if crypto.DefaultKDF != crypto.KDFArgon2id {
+		t.Skipf("header layout assumes the Argon2id default (this build uses %s)", crypto.DefaultKDF)
+	}
+}
+
+// TestStore_EncryptedBackup tests the backup loads with its own passphrase
+// under a fresh salt, and an empty passphrase reuses the vault's
+func TestStore_EncryptedBackup(t *testing.T) {
+	dir := t.TempDir()
+	store := manyServiceStore(t, filepath.Join(dir, "secrets.enc"), 3, false)
+
+	data, err := store.EncryptedBackup("backup-passphrase")
+	if err != nil {
+		t.Fatalf("EncryptedBackup() error = %v", err)
+	}
+	backupPath := filepath.Join(dir, "backup.enc")
+	if err := os.WriteFile(backupPath, data, 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	backup, err := Load(backupPath, "backup-passphrase")
+	if err != nil {
+		t.Fatalf("Load(backup) error = %v", err)
+	}
+	if len(backup.Services) != 3 || backup.Services[0].Secret != store.Services[0].Secret {
+		t.Errorf("Backup has %d services, want 3 with matching secrets", len(backup.Services))
+	}
+	if bytes.Equal(backup.Salt, store.Salt) {
+		t.Error("Backup should use its own salt")
+	}
+	if _, err := Load(backupPath, "test-passphrase"); err == nil {
+		t.Error("Backup should not open with the vault passphrase")
+	}
+
+	data, err = store.EncryptedBackup("")
+	if err != nil {
+		t.Fatalf("EncryptedBackup(same passphrase) error = %v", err)
+	}
+	samePath := filepath.Join(dir, "same.enc")
+	if err := os.WriteFile(samePath, data, 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := Load(samePath, "test-passphrase"); err != nil {
+		t.Errorf("Empty passphrase should reuse the vault passphrase: %v", err)
+	}
+}
+
+// manyServiceStore creates a store at path holding n services
+func manyServiceStore(t *testing.T, path string, n int, compress bool) *Store {
+	t.Helper()
+
+	store, err := Create(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	store.Settings.Compress = compress
+
+	for i := 0; i < n; i++ {
+		err := store.AddService(Service{
+			Name:       fmt.Sprintf("Service %03d", i),
+			Identifier: fmt.Sprintf("user%03d@example.com", i),
+			Secret:     "JBSWY3DPEHPK3PXP",
+			CreatedAt:  time.Unix(1700000000, 0),
+		})
+		if err != nil {
+			t.Fatalf("AddService() error = %v", err)
+		}
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	return store
+}
+
+// TestStore_CompressedRoundTrip tests a gzip-compressed store loads transparently
+func TestStore_CompressedRoundTrip(t *testing.T) {
+	requireArgon2Default(t)
+
+	storePath := filepath.Join(t.TempDir(), "test-secrets.enc")
+	manyServiceStore(t, storePath, 10, true)
+
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("Failed to read storage file: %v", err)
+	}
+	if version := binary.LittleEndian.Uint32(data[0:4]); version != formatVersionGzip {
+		t.Errorf("Header version = %d, want %d", version, formatVersionGzip)
+	}
+
+	loaded, err := Load(storePath, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.Services) != 10 || loaded.Services[9].Name != "Service 009" {
+		t.Errorf("Loaded %d services, want 10 ending with 'Service 009'", len(loaded.Services))
+	}
+	if !loaded.Settings.Compress {
+		t.Error("Compress setting should persist")
+	}
+
+	// Turning compression off rewrites the plain format
+	loaded.Settings.Compress = false
+	if err := loaded.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	data, _ = os.ReadFile(storePath)
+	if version := binary.LittleEndian.Uint32(data[0:4]); version != formatVersionPlain {
+		t.Errorf("Header version = %d, want %d", version, formatVersionPlain)
+	}
+	if _, err := Load(storePath, "test-passphrase"); err != nil {
+		t.Fatalf("Load() after disabling compression error = %v", err)
+	}
+}
+
+// TestStore_CompressedIsSmaller tests compression shrinks a large vault on disk
+func TestStore_CompressedIsSmaller(t *testing.T) {
+	tmpDir := t.TempDir()
+	plainPath := filepath.Join(tmpDir, "plain.enc")
+	gzipPath := filepath.Join(tmpDir, "gzip.enc")
+
+	manyServiceStore(t, plainPath, 200, false)
+	manyServiceStore(t, gzipPath, 200, true)
+
+	plainInfo, err := os.Stat(plainPath)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	gzipInfo, err := os.Stat(gzipPath)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	if gzipInfo.Size() >= plainInfo.Size() {
+		t.Errorf("Compressed size = %d, want smaller than plain size %d", gzipInfo.Size(), plainInfo.Size())
+	}
+}
+
+// TestStore_XChaChaRoundTrip tests a store written with XChaCha20-Poly1305
+// (with and without compression) loads, and switching back writes AES-GCM
+func TestStore_XChaChaRoundTrip(t *testing.T) {
+	requireArgon2Default(t)
+
+	for _, compress := range []bool{false, true} {
+		t.Run(fmt.Sprintf("compress=%t", compress), func(t *testing.T) {
+			storePath := filepath.Join(t.TempDir(), "test-secrets.enc")
+			store := manyServiceStore(t, storePath, 3, compress)
+			store.Settings.Cipher = crypto.CipherXChaCha20Poly1305.String()
+			if err := store.Save(); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			header, err := ReadHeader(storePath)
+			if err != nil {
+				t.Fatalf("ReadHeader() error = %v", err)
+			}
+			if header.Version != formatVersionCipher || header.Cipher != crypto.CipherXChaCha20Poly1305 {
+				t.Errorf("Header = version %d cipher %s, want %d and xchacha20-poly1305", header.Version, header.Cipher, formatVersionCipher)
+			}
+			if header.NonceLength != 24 || header.Compressed() != compress {
+				t.Errorf("Header nonce %d compressed %t, want 24 and %t", header.NonceLength, header.Compressed(), compress)
+			}
+
+			loaded, err := Load(storePath, "test-passphrase")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(loaded.Services) != 3 || loaded.Services[2].Name != "Service 002" {
+				t.Errorf("Loaded %d services, want 3 ending with 'Service 002'", len(loaded.Services))
+			}
+			if _, err := Load(storePath, "wrong-passphrase"); err == nil {
+				t.Error("Load() with wrong passphrase should fail")
+			}
+
+			// Switching back to the default cipher writes a version 1/2 AES file
+			loaded.Settings.Cipher = ""
+			if err := loaded.Save(); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			header, err = ReadHeader(storePath)
+			if err != nil {
+				t.Fatalf("ReadHeader() error = %v", err)
+			}
+			if header.Cipher != crypto.CipherAESGCM || header.Version == formatVersionCipher {
+				t.Errorf("Header = version %d cipher %s, want AES-GCM in version 1 or 2", header.Version, header.Cipher)
+			}
+			if _, err := Load(storePath, "test-passphrase"); err != nil {
+				t.Fatalf("Load() AES store error = %v", err)
+			}
+		})
+	}
+}
+
+// TestStore_UnknownCipher tests that an unknown cipher byte is rejected
+func TestStore_UnknownCipher(t *testing.T) {
+	data := make([]byte, 64)
+	binary.LittleEndian.PutUint32(data[0:4], formatVersionCipher)
+	data[4] = 0xEE
+
+	if _, err := parseHeader(data); err == nil {
+		t.Error("parseHeader() should reject an unknown cipher")
+	}
+}
+
+// TestStore_ScryptRoundTrip tests a store written with scrypt records the
+// KDF in a version 4 header, loads, and keeps scrypt on re-save
+func TestStore_ScryptRoundTrip(t *testing.T) {
+	for _, cipher := range []crypto.Cipher{crypto.CipherAESGCM, crypto.CipherXChaCha20Poly1305} {
+		t.Run(cipher.String(), func(t *testing.T) {
+			storePath := filepath.Join(t.TempDir(), "test-secrets.enc")
+			store := manyServiceStore(t, storePath, 2, false)
+			store.Settings.Cipher = cipher.String()
+			store.Settings.KDF = crypto.KDFScrypt.String()
+			if err := store.Save(); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			header, err := ReadHeader(storePath)
+			if err != nil {
+				t.Fatalf("ReadHeader() error = %v", err)
+			}
+			if header.Version != formatVersionKDF || header.KDF != crypto.KDFScrypt || header.Cipher != cipher {
+				t.Errorf("Header = version %d kdf %s cipher %s, want %d, scrypt and %s", header.Version, header.KDF, header.Cipher, formatVersionKDF, cipher)
+			}
+
+			loaded, err := Load(storePath, "test-passphrase")
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(loaded.Services) != 2 {
+				t.Errorf("Loaded %d services, want 2", len(loaded.Services))
+			}
+			if _, err := Load(storePath, "wrong-passphrase"); err == nil {
+				t.Error("Load() with wrong passphrase should fail")
+			}
+
+			if err := loaded.Save(); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+			if header, _ := ReadHeader(storePath); header.KDF != crypto.KDFScrypt {
+				t.Errorf("Re-saved header kdf = %s, want scrypt", header.KDF)
+			}
+		})
+	}
+}
+
+// TestLoad_HonorsHeaderKDF tests the key is derived with the header's KDF:
+// relabelling an scrypt file as Argon2id makes decryption fail
+func TestLoad_HonorsHeaderKDF(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "test-secrets.enc")
+	store := manyServiceStore(t, storePath, 1, false)
+	store.Settings.KDF = crypto.KDFScrypt.String()
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	data[6] = byte(crypto.KDFArgon2id)
+	if err := os.WriteFile(storePath, data, 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if _, err := Load(storePath, "test-passphrase"); err == nil {
+		t.Error("Load() should derive with the header's KDF and fail after relabelling")
+	}
+}
+
+// TestStore_UnknownKDF tests that an unknown KDF byte is rejected
+func TestStore_UnknownKDF(t *testing.T) {
+	data := make([]byte, 64)
+	binary.LittleEndian.PutUint32(data[0:4], formatVersionKDF)
+	data[6] = 0xEE
+
+	if _, err := parseHeader(data); err == nil {
+		t.Error("parseHeader() should reject an unknown kdf")
+	}
+}
+
+// TestLoad_NewerVersion tests a future format version yields ErrNewerVersion
+// with upgrade guidance, while version 0 stays a plain unsupported error
+func TestLoad_NewerVersion(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "test-secrets.enc")
+	manyServiceStore(t, storePath, 1, false)
+
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	binary.LittleEndian.PutUint32(data[0:4], formatVersionKDF+1)
+	if err := os.WriteFile(storePath, data, 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	_, err = Load(storePath, "test-passphrase")
+	if !errors.Is(err, ErrNewerVersion) {
+		t.Fatalf("Load() error = %v, want ErrNewerVersion", err)
+	}
+	if !strings.Contains(err.Error(), "upgrade") || !strings.Contains(err.Error(), "format version 5") {
+		t.Errorf("Error = %q, want the version and upgrade guidance", err)
+	}
+
+	binary.LittleEndian.PutUint32(data[0:4], 0)
+	if _, err := parseHeader(data); err == nil || errors.Is(err, ErrNewerVersion) {
+		t.Errorf("parseHeader(version 0) error = %v, want a non-ErrNewerVersion error", err)
+	}
+}
+
+// TestLoad_CorruptedHeader tests headers whose declared layout does not fit
+// the file yield ErrCorrupted instead of misreading bytes
+func TestLoad_CorruptedHeader(t *testing.T) {
+	// A valid version 1 file: 4 + 16 salt + 12 nonce + 16 tag = 48 bytes minimum
+	v1 := make([]byte, 50)
+	binary.LittleEndian.PutUint32(v1[0:4], formatVersionPlain)
+
+	tests := []struct {
+		name string
+		data func() []byte
+	}{
+		{"shorter than the version field", func() []byte { return []byte{1, 0} }},
+		{"version 1 truncated", func() []byte { return v1[:40] }},
+		{"version 3 without cipher byte", func() []byte {
+			data := make([]byte, 5)
+			binary.LittleEndian.PutUint32(data[0:4], formatVersionCipher)
+			return data
+		}},
+		{"version 3 XChaCha layout larger than the file", func() []byte {
+			// Fits the 48-byte AES-GCM layout but not 6 + 16 + 24 + 16 = 62
+			data := append([]byte(nil), v1...)
+			binary.LittleEndian.PutUint32(data[0:4], formatVersionCipher)
+			data[4] = byte(crypto.CipherXChaCha20Poly1305)
+			return data
+		}},
+		{"version 3 unknown flags", func() []byte {
+			data := make([]byte, 80)
+			binary.LittleEndian.PutUint32(data[0:4], formatVersionCipher)
+			data[5] = 0x80
+			return data
+		}},
+		{"version 3 unknown cipher", func() []byte {
+			data := make([]byte, 80)
+			binary.LittleEndian.PutUint32(data[0:4], formatVersionCipher)
+			data[4] = 0xee
+			return data
+		}},
+		{"version 4 unknown kdf", func() []byte {
+			data := make([]byte, 80)
+			binary.LittleEndian.PutUint32(data[0:4], formatVersionKDF)
+			data[6] = 0xee
+			return data
+		}},
+		{"version 0", func() []byte { return make([]byte, 80) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storePath := filepath.Join(t.TempDir(), "secrets.enc")
+			if err := os.WriteFile(storePath, tt.data(), 0600); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+
+			if _, err := Load(storePath, "test-passphrase"); !errors.Is(err, ErrCorrupted) {
+				t.Errorf("Load() error = %v, want ErrCorrupted", err)
+			}
+		})
+	}
+}
+
+// TestHeaderSplit_LayoutMismatch tests split rejects a header that does not
+// describe the data it is applied to
+func TestHeaderSplit_LayoutMismatch(t *testing.T) {
+	data := make([]byte, 60)
+	binary.LittleEndian.PutUint32(data[0:4], formatVersionPlain)
+
+	header, err := parseHeader(data)
+	if err != nil {
+		t.Fatalf("parseHeader() error = %v", err)
+	}
+	salt, nonce, ciphertext, err := header.split(data)
+	if err != nil {
+		t.Fatalf("split() error = %v", err)
+	}
+	if len(salt) != 16 || len(nonce) != 12 || len(ciphertext) != 28 {
+		t.Errorf("split() lengths = %d/%d/%d, want 16/12/28", len(salt), len(nonce), len(ciphertext))
+	}
+
+	// 4 + 16 + 12 leaves room for the nonce but not the 16-byte tag
+	if _, _, _, err := header.split(data[:40]); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("split(truncated) error = %v, want ErrCorrupted", err)
+	}
+
+	header.NonceLength = 24
+	if _, _, _, err := header.split(data); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("split(wrong nonce size) error = %v, want ErrCorrupted", err)
+	}
+}
+
+// TestReadHeader tests the header is read without a passphrase
+func TestReadHeader(t *testing.T) {
+	requireArgon2Default(t)
+
+	storePath := filepath.Join(t.TempDir(), "test-secrets.enc")
+	manyServiceStore(t, storePath, 1, true)
+
+	header, err := ReadHeader(storePath)
+	if err != nil {
+		t.Fatalf("ReadHeader() error = %v", err)
+	}
+	if header.Version != formatVersionGzip || !header.Compressed() {
+		t.Errorf("Version = %d, Compressed = %v; want %d and true", header.Version, header.Compressed(), formatVersionGzip)
+	}
+	if header.SaltLength != 16 || header.NonceLength != 12 {
+		t.Errorf("Salt/nonce lengths = %d/%d, want 16/12", header.SaltLength, header.NonceLength)
+	}
+
+	info, _ := os.Stat(storePath)
+	if header.PayloadLength != int(info.Size())-32 {
+		t.Errorf("PayloadLength = %d, want %d", header.PayloadLength, info.Size()-32)
+	}
+}
+
 // TestGetDefaultStoragePath tests default storage path generation
 func TestGetDefaultStoragePath(t *testing.T) {
 	path, err := GetDefaultStoragePath()
@@ -331,6 +1100,59 @@ func TestGetDefaultStoragePath(t *testing.T) {
 	}
 }
 
+// TestGetDefaultStoragePath_NoHome tests the actionable error when HOME and XDG are unset
+func TestGetDefaultStoragePath_NoHome(t *testing.T) {
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(StorageDirEnvVar, "")
+	t.Setenv(AllowCwdStorageEnvVar, "")
+
+	_, err := GetDefaultStoragePath()
+	if err == nil {
+		t.Fatal("Expected error when HOME and XDG_CONFIG_HOME are unset")
+	}
+	if !contains(err.Error(), StorageDirEnvVar) || !contains(err.Error(), "--storage") || !contains(err.Error(), AllowCwdStorageEnvVar) {
+		t.Errorf("Error should suggest %s, --storage and %s, got: %v", StorageDirEnvVar, AllowCwdStorageEnvVar, err)
+	}
+}
+
+// TestGetDefaultStoragePath_CwdOptIn tests the current-directory fallback requires opt-in
+func TestGetDefaultStoragePath_CwdOptIn(t *testing.T) {
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(StorageDirEnvVar, "")
+	t.Setenv(AllowCwdStorageEnvVar, "1")
+
+	path, err := GetDefaultStoragePath()
+	if err != nil {
+		t.Fatalf("GetDefaultStoragePath() error = %v", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+	if path != filepath.Join(cwd, "secrets.enc") {
+		t.Errorf("Path = %s, want secrets.enc in %s", path, cwd)
+	}
+}
+
+// TestGetDefaultStoragePath_StorageDirOverride tests TOTP_STORAGE_DIR takes precedence
+func TestGetDefaultStoragePath_StorageDirOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(StorageDirEnvVar, dir)
+
+	path, err := GetDefaultStoragePath()
+	if err != nil {
+		t.Fatalf("GetDefaultStoragePath() error = %v", err)
+	}
+	if path != filepath.Join(dir, "secrets.enc") {
+		t.Errorf("Path = %s, want secrets.enc in %s", path, dir)
+	}
+}
+
 // TestStore_SaveEmptyStorage tests saving empty storage
 func TestStore_SaveEmptyStorage(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -148,3 +148,30 @@ func TestStore_ChangePassphrase(t *testing.T) {
 		t.Error("Old password should not work after change")
 	}
 }
+
+// TestStore_ChangePassphraseFailedSave tests a failed re-encrypt keeps the
+// old passphrase for later saves
+func TestStore_ChangePassphraseFailedSave(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "test.enc")
+	store, err := Create(storePath, "old-password")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	t.Run("failed change", func(t *testing.T) {
+		failRename(t)
+		if err := store.ChangePassphrase("new-password"); err == nil {
+			t.Fatal("ChangePassphrase() should fail")
+		}
+	})
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := Load(storePath, "old-password"); err != nil {
+		t.Errorf("Load() with the old passphrase error = %v, want the later save to keep it", err)
+	}
+}
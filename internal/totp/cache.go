@@ -0,0 +1,108 @@
+package totp
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// maxCacheEntries bounds the cache; a full cache drops expired codes first
+// and starts over if that frees nothing
+const maxCacheEntries = 256
+
+// cacheKey identifies a code sequence; the step is stored with the entry so
+// each sequence holds only its current code. Secrets are keyed by their
+// SHA-256 so the cache never holds one in plaintext.
+type cacheKey struct {
+	secret    [sha256.Size]byte
+	algorithm string
+	digits    int
+	period    int
+}
+
+type cacheEntry struct {
+	step uint64
+	code string
+}
+
+// codeCache memoizes the code for the current step of each sequence.
+// Entries are replaced as soon as a later step is requested.
+type codeCache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+
+	// onCompute is called, under mu, whenever a code is computed rather
+	// than served from the cache (test hook)
+	onCompute func()
+}
+
+// defaultCache backs GenerateCodeCached
+var defaultCache = &codeCache{entries: make(map[cacheKey]cacheEntry)}
+
+// GenerateCodeCached is GenerateCodeCustom with the result cached for the
+// rest of its time step, so repeated generations within a period are free.
+// Safe for concurrent use.
+func GenerateCodeCached(secret string, t time.Time, algorithm string, digits, period int) (string, error) {
+	return defaultCache.code(secret, t, algorithm, digits, period)
+}
+
+// Purge drops every cached code. Long-running processes call it when they
+// lock a store, so codes for its secrets are not kept after the lock.
+func Purge() {
+	defaultCache.purge()
+}
+
+func (c *codeCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[cacheKey]cacheEntry)
+}
+
+func (c *codeCache) code(secret string, t time.Time, algorithm string, digits, period int) (string, error) {
+	if period <= 0 {
+		// Let GenerateCodeCustom report the invalid period
+		return GenerateCodeCustom(secret, t, algorithm, digits, period)
+	}
+
+	key := cacheKey{secret: sha256.Sum256([]byte(secret)), algorithm: algorithm, digits: digits, period: period}
+	step := uint64(t.Unix()) / uint64(period)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok || entry.step != step {
+		if c.onCompute != nil {
+			c.onCompute()
+		}
+	}
+	c.mu.Unlock()
+	if ok && entry.step == step {
+		return entry.code, nil
+	}
+
+	code, err := GenerateCodeCustom(secret, t, algorithm, digits, period)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= maxCacheEntries {
+		c.evict(t)
+	}
+	c.entries[key] = cacheEntry{step: step, code: code}
+	c.mu.Unlock()
+
+	return code, nil
+}
+
+// evict makes room in a full cache: codes whose step has passed at t go
+// first, and if none have, the cache starts over. Callers hold mu.
+func (c *codeCache) evict(t time.Time) {
+	for key, entry := range c.entries {
+		if entry.step < uint64(t.Unix())/uint64(key.period) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) >= maxCacheEntries {
+		c.entries = make(map[cacheKey]cacheEntry)
+	}
+}
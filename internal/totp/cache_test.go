@@ -0,0 +1,190 @@
+package totp
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countComputes installs a fresh cache and counts computed (uncached) codes
+func countComputes(t *testing.T) *int {
+	t.Helper()
+
+	oldCache := defaultCache
+	count := 0
+	defaultCache = &codeCache{entries: make(map[cacheKey]cacheEntry), onCompute: func() { count++ }}
+	t.Cleanup(func() { defaultCache = oldCache })
+
+	return &count
+}
+
+// TestGenerateCodeCached_SameStep tests repeated generations within a step hit the cache
+func TestGenerateCodeCached_SameStep(t *testing.T) {
+	computes := countComputes(t)
+	secret := rfcKey("12345678901234567890")
+
+	first, err := GenerateCodeCached(secret, time.Unix(1111111080, 0), "SHA1", 8, 30)
+	if err != nil {
+		t.Fatalf("GenerateCodeCached() error = %v", err)
+	}
+	second, err := GenerateCodeCached(secret, time.Unix(1111111109, 0), "SHA1", 8, 30)
+	if err != nil {
+		t.Fatalf("GenerateCodeCached() error = %v", err)
+	}
+
+	if first != "07081804" || second != first {
+		t.Errorf("Codes = %s, %s; want 07081804 twice", first, second)
+	}
+	if *computes != 1 {
+		t.Errorf("Computed %d times, want 1", *computes)
+	}
+}
+
+// TestGenerateCodeCached_NextStep tests the cache refreshes when the step advances
+func TestGenerateCodeCached_NextStep(t *testing.T) {
+	computes := countComputes(t)
+	secret := rfcKey("12345678901234567890")
+
+	first, _ := GenerateCodeCached(secret, time.Unix(1111111109, 0), "SHA1", 8, 30)
+	next, _ := GenerateCodeCached(secret, time.Unix(1111111111, 0), "SHA1", 8, 30)
+
+	if next != "14050471" {
+		t.Errorf("Next step code = %s, want 14050471", next)
+	}
+	if first == next || *computes != 2 {
+		t.Errorf("Expected a recompute for the new step, computed %d times", *computes)
+	}
+}
+
+// TestGenerateCodeCached_ParamsAreKeys tests different parameters don't share entries
+func TestGenerateCodeCached_ParamsAreKeys(t *testing.T) {
+	computes := countComputes(t)
+	secret := rfcKey("12345678901234567890")
+	at := time.Unix(1111111109, 0)
+
+	six, _ := GenerateCodeCached(secret, at, "SHA1", 6, 30)
+	eight, _ := GenerateCodeCached(secret, at, "SHA1", 8, 30)
+
+	if six != "081804" || eight != "07081804" {
+		t.Errorf("Codes = %s, %s; want 081804 and 07081804", six, eight)
+	}
+	if *computes != 2 {
+		t.Errorf("Computed %d times, want 2", *computes)
+	}
+}
+
+// TestGenerateCodeCached_Concurrent tests concurrent use (run with -race)
+func TestGenerateCodeCached_Concurrent(t *testing.T) {
+	countComputes(t)
+	secret := rfcKey("12345678901234567890")
+	at := time.Unix(1111111109, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, err := GenerateCodeCached(secret, at, "SHA1", 8, 30)
+			if err != nil || code != "07081804" {
+				t.Errorf("GenerateCodeCached() = %s, %v; want 07081804", code, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// TestGenerateCodeCached_NoPlaintextSecrets tests entries are keyed by a
+// hash of the secret
+func TestGenerateCodeCached_NoPlaintextSecrets(t *testing.T) {
+	countComputes(t)
+	secret := rfcKey("12345678901234567890")
+	if _, err := GenerateCodeCached(secret, time.Unix(1111111109, 0), "SHA1", 8, 30); err != nil {
+		t.Fatalf("GenerateCodeCached() error = %v", err)
+	}
+
+	for key := range defaultCache.entries {
+		if strings.Contains(string(key.secret[:]), secret) {
+			t.Error("Cache key holds the plaintext secret")
+		}
+	}
+}
+
+// TestGenerateCodeCached_Bounded tests the cache never grows past
+// maxCacheEntries, dropping expired codes first
+func TestGenerateCodeCached_Bounded(t *testing.T) {
+	countComputes(t)
+	secret := rfcKey("12345678901234567890")
+	at := time.Unix(1111111109, 0)
+
+	// fill caches one 30s code plus daily codes of distinct secrets, which
+	// stay current for hours after 'at'
+	fill := func() {
+		GenerateCodeCached(secret, at, "SHA1", 6, 30)
+		for i := 0; len(defaultCache.entries) < maxCacheEntries; i++ {
+			if _, err := GenerateCodeCached(rfcKey(fmt.Sprintf("%020d", i)), at, "SHA1", 6, 86400); err != nil {
+				t.Fatalf("GenerateCodeCached() error = %v", err)
+			}
+		}
+	}
+
+	// Two minutes on only the 30s code has expired, so it makes room
+	fill()
+	GenerateCodeCached(secret, at.Add(2*time.Minute), "SHA1", 8, 30)
+	if count := len(defaultCache.entries); count != maxCacheEntries {
+		t.Errorf("Cache holds %d entries, want %d after evicting the expired code", count, maxCacheEntries)
+	}
+
+	// With nothing expired the cache starts over
+	defaultCache.purge()
+	fill()
+	GenerateCodeCached(secret, at, "SHA1", 7, 30)
+	if count := len(defaultCache.entries); count != 1 {
+		t.Errorf("Cache holds %d entries, want 1 after starting over", count)
+	}
+}
+
+// TestPurge tests Purge drops every cached code
+func TestPurge(t *testing.T) {
+	computes := countComputes(t)
+	secret := rfcKey("12345678901234567890")
+	at := time.Unix(1111111109, 0)
+
+	GenerateCodeCached(secret, at, "SHA1", 8, 30)
+	Purge()
+	if len(defaultCache.entries) != 0 {
+		t.Errorf("Cache holds %d entries after Purge, want 0", len(defaultCache.entries))
+	}
+	GenerateCodeCached(secret, at, "SHA1", 8, 30)
+	if *computes != 2 {
+		t.Errorf("Computed %d times, want a recompute after Purge", *computes)
+	}
+}
+
+// TestGenerateCodeCached_Invalid tests errors are returned and not cached
+func TestGenerateCodeCached_Invalid(t *testing.T) {
+	countComputes(t)
+	if _, err := GenerateCodeCached("not base32!", time.Now(), "SHA1", 6, 30); err == nil {
+		t.Error("Expected error for invalid secret")
+	}
+	if _, err := GenerateCodeCached(rfcKey("12345678901234567890"), time.Now(), "SHA1", 6, 0); err == nil {
+		t.Error("Expected error for zero period")
+	}
+}
+
+func BenchmarkGenerateCodeCustom(b *testing.B) {
+	secret := rfcKey("12345678901234567890")
+	at := time.Unix(1111111109, 0)
+	for i := 0; i < b.N; i++ {
+		GenerateCodeCustom(secret, at, "SHA1", 6, 30)
+	}
+}
+
+func BenchmarkGenerateCodeCached(b *testing.B) {
+	secret := rfcKey("12345678901234567890")
+	at := time.Unix(1111111109, 0)
+	for i := 0; i < b.N; i++ {
+		GenerateCodeCached(secret, at, "SHA1", 6, 30)
+	}
+}
@@ -0,0 +1,11 @@
+package totp
+
+import "crypto/subtle"
+
+// ConstantTimeEqual reports whether two codes are equal without leaking,
+// through timing, how many leading characters match. Use it whenever a
+// user-supplied code is checked against a generated one. Only the lengths
+// are compared in variable time, and code lengths are not secret.
+func ConstantTimeEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
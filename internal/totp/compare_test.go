@@ -0,0 +1,48 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// TestConstantTimeEqual tests equal and unequal codes, including unequal
+// codes of the same length (the constant-time path)
+func TestConstantTimeEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"Equal", "287082", "287082", true},
+		{"Equal 8 digits", "94287082", "94287082", true},
+		{"Same length, first digit differs", "187082", "287082", false},
+		{"Same length, last digit differs", "287083", "287082", false},
+		{"Prefix", "28708", "287082", false},
+		{"Longer", "2870821", "287082", false},
+		{"Empty vs code", "", "287082", false},
+		{"Both empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConstantTimeEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("ConstantTimeEqual(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestConstantTimeEqual_GeneratedCode tests comparison against a generated code
+func TestConstantTimeEqual_GeneratedCode(t *testing.T) {
+	code, err := GenerateCodeCustom(rfcKey("12345678901234567890"), time.Unix(59, 0), "SHA1", 6, 30)
+	if err != nil {
+		t.Fatalf("GenerateCodeCustom() error = %v", err)
+	}
+
+	if !ConstantTimeEqual("287082", code) {
+		t.Errorf("ConstantTimeEqual(287082, %s) = false, want true", code)
+	}
+	if ConstantTimeEqual("000000", code) {
+		t.Error("ConstantTimeEqual(000000, ...) = true, want false")
+	}
+}
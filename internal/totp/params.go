@@ -0,0 +1,105 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+)
+
+const (
+	// RFC 6238 defaults
+	DefaultAlgorithm = "SHA1"
+	DefaultDigits    = 6
+	DefaultPeriod    = 30
+
+	// Supported code lengths
+	MinDigits = 6
+	MaxDigits = 8
+)
+
+// ValidateParams validates a TOTP algorithm, digit count and period. It is
+// the single definition of the supported parameters, shared by add, the
+// importers and otpauth URI parsing.
+func ValidateParams(algorithm string, digits, period int) error {
+	switch strings.ToUpper(algorithm) {
+	case "SHA1", "SHA256", "SHA512":
+	default:
+		return fmt.Errorf("unsupported algorithm %q: use SHA1, SHA256 or SHA512", algorithm)
+	}
+
+	if digits < MinDigits || digits > MaxDigits {
+		return fmt.Errorf("invalid digits %d: must be %d-%d", digits, MinDigits, MaxDigits)
+	}
+
+	if period <= 0 {
+		return fmt.Errorf("invalid period %d: must be positive", period)
+	}
+
+	return nil
+}
+
+// GenerateCodeCustom generates a TOTP code with explicit algorithm, digits and period
+func GenerateCodeCustom(secret string, t time.Time, algorithm string, digits, period int) (string, error) {
+	if digits < 1 || digits > 9 {
+		return "", fmt.Errorf("invalid digits: %d", digits)
+	}
+	if period <= 0 {
+		return "", fmt.Errorf("invalid period: %d", period)
+	}
+
+	value, err := truncatedValue(secret, uint64(t.Unix())/uint64(period), algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	modulus := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulus *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%modulus), nil
+}
+
+// hashFunc returns the HMAC hash constructor for an algorithm name
+func hashFunc(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToUpper(algorithm) {
+	case "", "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %q", algorithm)
+	}
+}
+
+// truncatedValue computes the RFC 4226 dynamically truncated 31-bit value
+// of HMAC(secret, counter) using the given algorithm
+func truncatedValue(secret string, counter uint64, algorithm string) (uint32, error) {
+	newHash, err := hashFunc(algorithm)
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, err
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(newHash, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	return binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff, nil
+}
@@ -0,0 +1,120 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// rfcKey returns the Base32 encoding of an ASCII RFC 6238 test key
+func rfcKey(ascii string) string {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(ascii))
+}
+
+// TestGenerateCodeCustom_RFC6238 tests the RFC 6238 appendix B vectors
+func TestGenerateCodeCustom_RFC6238(t *testing.T) {
+	sha1Key := rfcKey("12345678901234567890")
+	sha256Key := rfcKey("12345678901234567890123456789012")
+	sha512Key := rfcKey("1234567890123456789012345678901234567890123456789012345678901234")
+
+	tests := []struct {
+		unix      int64
+		algorithm string
+		key       string
+		want      string
+	}{
+		{59, "SHA1", sha1Key, "94287082"},
+		{59, "SHA256", sha256Key, "46119246"},
+		{59, "SHA512", sha512Key, "90693936"},
+		{1111111109, "SHA1", sha1Key, "07081804"},
+		{1111111109, "SHA256", sha256Key, "68084774"},
+		{1111111109, "SHA512", sha512Key, "25091201"},
+		{2000000000, "SHA1", sha1Key, "69279037"},
+		{20000000000, "SHA512", sha512Key, "47863826"},
+	}
+
+	for _, tt := range tests {
+		got, err := GenerateCodeCustom(tt.key, time.Unix(tt.unix, 0), tt.algorithm, 8, 30)
+		if err != nil {
+			t.Fatalf("GenerateCodeCustom(%d, %s) error = %v", tt.unix, tt.algorithm, err)
+		}
+		if got != tt.want {
+			t.Errorf("GenerateCodeCustom(%d, %s) = %s, want %s", tt.unix, tt.algorithm, got, tt.want)
+		}
+	}
+}
+
+// TestGenerateCodeCustom_DigitsAndPeriod tests truncation length and period stepping
+func TestGenerateCodeCustom_DigitsAndPeriod(t *testing.T) {
+	key := rfcKey("12345678901234567890")
+
+	code, err := GenerateCodeCustom(key, time.Unix(59, 0), DefaultAlgorithm, DefaultDigits, DefaultPeriod)
+	if err != nil {
+		t.Fatalf("GenerateCodeCustom() error = %v", err)
+	}
+	if code != "287082" {
+		t.Errorf("6-digit code = %s, want 287082", code)
+	}
+
+	// With a 60s period, T=59 and T=0 fall in the same step
+	code59, _ := GenerateCodeCustom(key, time.Unix(59, 0), "SHA1", 6, 60)
+	code0, _ := GenerateCodeCustom(key, time.Unix(0, 0), "SHA1", 6, 60)
+	if code59 != code0 {
+		t.Errorf("Codes in the same 60s period differ: %s vs %s", code59, code0)
+	}
+}
+
+// TestGenerateCodeCustom_Invalid tests invalid parameters are rejected
+func TestGenerateCodeCustom_Invalid(t *testing.T) {
+	key := rfcKey("12345678901234567890")
+
+	tests := []struct {
+		name      string
+		algorithm string
+		digits    int
+		period    int
+	}{
+		{"Unknown algorithm", "MD5", 6, 30},
+		{"Zero digits", "SHA1", 0, 30},
+		{"Zero period", "SHA1", 6, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateCodeCustom(key, time.Now(), tt.algorithm, tt.digits, tt.period); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+// TestValidateParams tests algorithm, digits and period validation
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		digits    int
+		period    int
+		wantErr   bool
+	}{
+		{"Defaults", "SHA1", 6, 30, false},
+		{"SHA512 8 digits 60s", "SHA512", 8, 60, false},
+		{"Lowercase algorithm", "sha256", 7, 30, false},
+		{"Unknown algorithm", "MD5", 6, 30, true},
+		{"Too few digits", "SHA1", 5, 30, true},
+		{"Too many digits", "SHA1", 9, 30, true},
+		{"Zero period", "SHA1", 6, 0, true},
+		{"Negative period", "SHA1", 6, -30, true},
+		{"Empty algorithm", "", 6, 30, true},
+		{"SHA256 8 digits 15s", "SHA256", 8, 15, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateParams(tt.algorithm, tt.digits, tt.period)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package totp
+
+import (
+	"encoding/base32"
+	"fmt"
+	"strings"
+)
+
+// NormalizeSecret returns the canonical form of a Base32 secret: whitespace
+// (e.g., grouped "ABCD EFGH" display) and trailing "=" padding removed,
+// uppercased. Padded and unpadded forms of a secret normalize identically.
+func NormalizeSecret(secret string) string {
+	return strings.TrimRight(strings.ToUpper(strings.Join(strings.Fields(secret), "")), "=")
+}
+
+// decodeSecret normalizes a Base32 secret and decodes it
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(NormalizeSecret(secret))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Base32 secret: %w", err)
+	}
+	return key, nil
+}
+
+// ValidateSecretEncoding checks a secret is non-empty valid Base32 without
+// enforcing the minimum length, for legacy providers that issue short secrets
+func ValidateSecretEncoding(secret string) error {
+	if strings.TrimSpace(secret) == "" {
+		return fmt.Errorf("secret cannot be empty")
+	}
+	_, err := decodeSecret(secret)
+	return err
+}
@@ -0,0 +1,51 @@
+package totp
+
+import "testing"
+
+// TestValidateSecretEncoding tests short secrets pass while invalid Base32 fails
+func TestValidateSecretEncoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{"Standard secret", "JBSWY3DPEHPK3PXP", false},
+		{"Short 10-char secret", "JBSWY3DPEH", false},
+		{"Lowercase with spaces", "jbsw y3dp eh", false},
+		{"Padded", "JBSWY3DPEH======", false},
+		{"Invalid characters", "JBSWY3DP1!", true},
+		{"Empty", "", true},
+		{"Whitespace only", "   ", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSecretEncoding(tt.secret)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSecretEncoding(%q) error = %v, wantErr %v", tt.secret, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestNormalizeSecret tests padded, spaced and lowercase forms normalize alike
+func TestNormalizeSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		want   string
+	}{
+		{"Unpadded", "JBSWY3DPEHPK3PXPJBSW", "JBSWY3DPEHPK3PXPJBSW"},
+		{"Padded", "JBSWY3DPEHPK3PXPJBSW====", "JBSWY3DPEHPK3PXPJBSW"},
+		{"Lowercase grouped", "jbsw y3dp ehpk 3pxp", "JBSWY3DPEHPK3PXP"},
+		{"Tabs and newline", "JBSW\tY3DP\nEHPK3PXP", "JBSWY3DPEHPK3PXP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeSecret(tt.secret); got != tt.want {
+				t.Errorf("NormalizeSecret(%q) = %q, want %q", tt.secret, got, tt.want)
+			}
+		})
+	}
+}
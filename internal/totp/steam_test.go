@@ -0,0 +1,83 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGenerateSteamCode tests Steam code format
+func TestGenerateSteamCode(t *testing.T) {
+	code, err := GenerateSteamCode("JBSWY3DPEHPK3PXP", time.Now())
+	if err != nil {
+		t.Fatalf("GenerateSteamCode() error = %v", err)
+	}
+
+	if len(code) != SteamCodeLength {
+		t.Errorf("Expected %d characters, got %q", SteamCodeLength, code)
+	}
+
+	for _, c := range code {
+		if !strings.ContainsRune(steamAlphabet, c) {
+			t.Errorf("Code %q contains character %q outside the Steam alphabet", code, c)
+		}
+	}
+}
+
+// TestSteamSecretFromBase64 tests converting a Steam shared_secret to Base32
+func TestSteamSecretFromBase64(t *testing.T) {
+	secret, err := SteamSecretFromBase64(" SGVsbG8h3q2+7w== ")
+	if err != nil {
+		t.Fatalf("SteamSecretFromBase64() error = %v", err)
+	}
+	if secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("SteamSecretFromBase64() = %q, want JBSWY3DPEHPK3PXP", secret)
+	}
+
+	for _, bad := range []string{"", "not base64!"} {
+		if _, err := SteamSecretFromBase64(bad); err == nil {
+			t.Errorf("SteamSecretFromBase64(%q) expected error", bad)
+		}
+	}
+}
+
+// TestGenerateSteamCode_Deterministic tests codes are stable within a period
+func TestGenerateSteamCode_Deterministic(t *testing.T) {
+	start := time.Unix(1700000010, 0)
+
+	code1, err := GenerateSteamCode("JBSWY3DPEHPK3PXP", start)
+	if err != nil {
+		t.Fatalf("GenerateSteamCode() error = %v", err)
+	}
+	code2, err := GenerateSteamCode("JBSWY3DPEHPK3PXP", start.Add(15*time.Second))
+	if err != nil {
+		t.Fatalf("GenerateSteamCode() error = %v", err)
+	}
+
+	if code1 != code2 {
+		t.Errorf("Codes within the same period differ: %q vs %q", code1, code2)
+	}
+}
+
+// TestTruncatedValue_MatchesTOTP tests the truncation agrees with standard 6-digit TOTP
+func TestTruncatedValue_MatchesTOTP(t *testing.T) {
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	at := time.Unix(1111111109, 0)
+
+	value, err := truncatedValue(secret, uint64(at.Unix())/30, "SHA1")
+	if err != nil {
+		t.Fatalf("truncatedValue() error = %v", err)
+	}
+
+	// RFC 6238 SHA1 vector for T=1111111109 is 07081804
+	if got := value % 1000000; got != 81804 {
+		t.Errorf("truncated value mod 10^6 = %d, want 81804", got)
+	}
+}
+
+// TestGenerateSteamCode_InvalidSecret tests invalid Base32 is rejected
+func TestGenerateSteamCode_InvalidSecret(t *testing.T) {
+	if _, err := GenerateSteamCode("INVALID!!!", time.Now()); err == nil {
+		t.Error("Expected error for invalid secret")
+	}
+}
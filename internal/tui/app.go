@@ -1,12 +1,13 @@
 package tui
 
 import (
+	"sort"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanprakash21/totp-manager-go/internal/clipboard"
 	"github.com/pavanprakash21/totp-manager-go/internal/storage"
-	"github.com/pavanprakash21/totp-manager-go/internal/totp"
 )
 
 // Model represents the Bubbletea TUI model
@@ -18,14 +19,35 @@ type Model struct {
 	cursor          int
 	viewportOffset  int               // first visible item index for scrolling
 	totpCodes       map[string]string // service name -> current TOTP code
+	codeErrors      map[string]bool   // service name -> code failed to generate
+	errorText       string            // placeholder shown for failed codes
 	remainingTime   int               // seconds remaining until refresh
 	lastUpdate      time.Time
 	copyStatus      string // Status message for clipboard operations
 	copyStatusTime  time.Time
 	width           int
 	height          int
-	searchMode      bool   // whether in search mode
-	searchQuery     string // current search query
+	searchMode      bool             // whether in search mode
+	searchQuery     string           // current search query
+	frameInterval   time.Duration    // sub-second countdown bar refresh (0 = per-second only)
+	barFraction     float64          // fraction of the current period remaining (for the bar)
+	now             func() time.Time // clock (overridable in tests)
+	showDetails     bool             // whether the details pane is open
+	showHelp        bool             // whether the keybinding overlay is open
+	revealRecovery  bool             // whether recovery codes are revealed in the details pane
+	sortByRecent    bool             // order search results by last-used, most recent first
+	verifyClipboard bool             // read the clipboard back after copying
+	hideIdentifiers bool             // leave identifiers out of list rows
+	showExpiresAt   bool             // show the expiry clock time instead of the countdown
+	nerdFonts       bool             // prefix service names with Nerd Fonts brand glyphs
+	clipClear       int              // seconds after a copy to clear the clipboard (0 = never)
+	noUseSave       bool             // copies don't record LastUsed or save (read-only media)
+	paused          bool             // terminal lost focus; ticks and frames are not rescheduled
+	tickIdle        bool             // the tick loop stopped while paused and must be restarted
+	frameIdle       bool             // the frame loop stopped while paused and must be restarted
+	pendingClear    *clipboard.ScheduledClear
+	form            *serviceForm // open add/edit form (nil when closed)
+	confirmDelete   bool         // waiting for 'y' to delete the selected service
 }
 
 // tickMsg is sent every second for countdown updates
@@ -34,6 +56,17 @@ type tickMsg time.Time
 // refreshMsg is sent when TOTP codes should refresh
 type refreshMsg time.Time
 
+// frameMsg is sent at the sub-second frame interval to animate the countdown bar
+type frameMsg time.Time
+
+const (
+	// totpPeriod is the code refresh period in seconds
+	totpPeriod = 30
+
+	// minFrameInterval bounds the bar refresh rate to keep CPU usage low
+	minFrameInterval = 100 * time.Millisecond
+)
+
 // NewModel creates a new TUI model with storage
 func NewModel(store *storage.Store) Model {
 	// Initialize with all services visible
@@ -42,16 +75,80 @@ func NewModel(store *storage.Store) Model {
 		filteredIndices[i] = i
 	}
 
-	return Model{
+	m := Model{
 		store:           store,
 		services:        store.Services,
 		filteredIndices: filteredIndices,
 		totpCodes:       make(map[string]string),
+		codeErrors:      make(map[string]bool),
+		errorText:       store.Settings.CodeErrorPlaceholder(),
 		lastUpdate:      time.Now(),
 		remainingTime:   calculateRemainingSeconds(),
 		searchMode:      false,
 		searchQuery:     "",
+		frameInterval:   frameInterval(store.Settings.BarRefreshMillis),
+		barFraction:     periodFraction(time.Now()),
+		now:             time.Now,
+		sortByRecent:    store.Settings.SearchSortRecent,
+		verifyClipboard: store.Settings.VerifyClipboard,
+		hideIdentifiers: store.Settings.HideIdentifiers,
+		showExpiresAt:   store.Settings.ShowExpiresAt,
+	}
+	m.orderFiltered()
+	return m
+}
+
+// WithNerdFonts enables brand glyphs next to service names (--nerd-fonts);
+// requires a Nerd Fonts patched terminal font
+func (m Model) WithNerdFonts(enabled bool) Model {
+	m.nerdFonts = enabled
+	return m
+}
+
+// WithClipClear clears the clipboard the given number of seconds after each
+// copy if it still holds the code (--clip-clear-seconds; 0 = never)
+func (m Model) WithClipClear(seconds int) Model {
+	m.clipClear = seconds
+	return m
+}
+
+// WithNoLastUsedSave stops copies from recording LastUsed and usage counts,
+// so the session never rewrites the store for them (--no-last-used-save)
+func (m Model) WithNoLastUsedSave(enabled bool) Model {
+	m.noUseSave = enabled
+	return m
+}
+
+// frameInterval converts the bar refresh setting to a tick interval.
+// Values below minFrameInterval are clamped; 0 or >= 1s disables frames
+// since the regular one-second tick already covers that rate.
+func frameInterval(millis int) time.Duration {
+	interval := time.Duration(millis) * time.Millisecond
+	if interval <= 0 || interval >= time.Second {
+		return 0
+	}
+	if interval < minFrameInterval {
+		return minFrameInterval
 	}
+	return interval
+}
+
+// periodFraction returns the fraction of the current period remaining at t
+func periodFraction(t time.Time) float64 {
+	periodMillis := int64(totpPeriod * 1000)
+	elapsed := t.UnixMilli() % periodMillis
+	return float64(periodMillis-elapsed) / float64(periodMillis)
+}
+
+// expiresAt returns when the code valid at t expires: t rounded up to the
+// next multiple of period seconds (a code starting exactly at t lasts the
+// whole period)
+func expiresAt(t time.Time, period int) time.Time {
+	if period <= 0 {
+		period = totpPeriod
+	}
+	p := int64(period)
+	return time.Unix((t.Unix()/p+1)*p, 0).In(t.Location())
 }
 
 // calculateRemainingSeconds calculates seconds until next 30s interval
@@ -67,25 +164,49 @@ func (m Model) Init() tea.Cmd {
 	m.generateAllCodes()
 
 	// Start ticker for countdown updates
-	return tea.Batch(
-		tickCmd(),
-		tea.WindowSize(),
-	)
+	cmds := []tea.Cmd{tickCmd(), tea.WindowSize()}
+	if m.frameInterval > 0 {
+		cmds = append(cmds, frameCmd(m.frameInterval))
+	}
+	return tea.Batch(cmds...)
 }
 
 // generateAllCodes generates TOTP codes for all services
 func (m *Model) generateAllCodes() {
 	now := time.Now()
+	for i := range m.services {
+		m.generateCode(&m.services[i], now)
+	}
+	m.remainingTime = calculateRemainingSeconds()
+	m.lastUpdate = now
+}
+
+// refreshOffPeriodCodes regenerates the codes of services with a period
+// other than 30s once their own period has rolled over since the last full
+// refresh, which the 30s countdown alone would miss. Codes are cached per
+// period, so repeating this every tick is cheap.
+func (m *Model) refreshOffPeriodCodes(now time.Time) {
 	for i := range m.services {
 		service := &m.services[i]
-		code, err := totp.GenerateCode(service.Secret, now)
-		if err != nil {
-			m.totpCodes[service.Name] = "ERROR"
+		period := int64(service.EffectivePeriod())
+		if period == totpPeriod || now.Unix()/period == m.lastUpdate.Unix()/period {
 			continue
 		}
-		m.totpCodes[service.Name] = code
+		m.generateCode(service, now)
 	}
-	m.remainingTime = calculateRemainingSeconds()
+}
+
+// generateCode stores the service's code for now, or marks it as failed
+func (m *Model) generateCode(service *storage.Service, now time.Time) {
+	code, err := service.Code(now)
+	if err != nil {
+		// No code to copy; the row shows the error placeholder
+		delete(m.totpCodes, service.Name)
+		m.codeErrors[service.Name] = true
+		return
+	}
+	delete(m.codeErrors, service.Name)
+	m.totpCodes[service.Name] = code
 }
 
 // filterServices performs fuzzy search on services
@@ -96,7 +217,9 @@ func (m *Model) filterServices() {
 		for i := range m.filteredIndices {
 			m.filteredIndices[i] = i
 		}
+		m.orderFiltered()
 		m.cursor = 0
+		m.viewportOffset = 0
 		return
 	}
 
@@ -112,6 +235,8 @@ func (m *Model) filterServices() {
 		}
 	}
 
+	m.orderFiltered()
+
 	// Reset cursor to first result
 	if m.cursor >= len(m.filteredIndices) {
 		m.cursor = 0
@@ -120,6 +245,28 @@ func (m *Model) filterServices() {
 	m.viewportOffset = 0
 }
 
+// orderFiltered puts pinned services first, keeping their relative order.
+// Search matches are unscored (all tied), so they are optionally ranked by
+// recency within each group.
+func (m *Model) orderFiltered() {
+	byRecent := m.sortByRecent && m.searchQuery != ""
+	sort.SliceStable(m.filteredIndices, func(a, b int) bool {
+		first, second := m.services[m.filteredIndices[a]], m.services[m.filteredIndices[b]]
+		if first.Pinned != second.Pinned {
+			return first.Pinned
+		}
+		return byRecent && storage.ByLastUsed(first, second)
+	})
+}
+
+// selectedService returns the service under the cursor, if any
+func (m Model) selectedService() (storage.Service, bool) {
+	if len(m.filteredIndices) == 0 || m.cursor >= len(m.filteredIndices) {
+		return storage.Service{}, false
+	}
+	return m.services[m.filteredIndices[m.cursor]], true
+}
+
 // fuzzyMatch checks if all characters in query appear in text in order
 func fuzzyMatch(text, query string) bool {
 	queryIdx := 0
@@ -138,6 +285,34 @@ func tickCmd() tea.Cmd {
 	})
 }
 
+// frameCmd returns a command that ticks at the sub-second frame interval
+func frameCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return frameMsg(t)
+	})
+}
+
+// resume recomputes codes and the countdown after a focus loss or suspend
+// and restarts whichever loops stopped while paused. A loop whose message
+// was still in flight keeps running, so resuming never doubles the ticks.
+func (m Model) resume() (tea.Model, tea.Cmd) {
+	m.paused = false
+	m.remainingTime = calculateRemainingSeconds()
+	m.barFraction = periodFraction(time.Now())
+	m.generateAllCodes()
+
+	var cmds []tea.Cmd
+	if m.tickIdle {
+		m.tickIdle = false
+		cmds = append(cmds, tickCmd())
+	}
+	if m.frameIdle && m.frameInterval > 0 {
+		m.frameIdle = false
+		cmds = append(cmds, frameCmd(m.frameInterval))
+	}
+	return m, tea.Batch(cmds...)
+}
+
 // Update implements tea.Model interface
 // (T043: Update method with keyboard message handling)
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -150,13 +325,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 
+	case tea.BlurMsg:
+		m.paused = true
+		return m, nil
+
+	case tea.FocusMsg, tea.ResumeMsg:
+		return m.resume()
+
 	case tickMsg:
+		if m.paused {
+			m.tickIdle = true
+			return m, nil
+		}
 		// T049: Update countdown every second
 		m.remainingTime--
 		if m.remainingTime <= 0 {
 			// T050: Refresh TOTP codes every 30 seconds
 			m.remainingTime = 30
 			m.generateAllCodes()
+		} else {
+			m.refreshOffPeriodCodes(time.Time(msg))
 		}
 
 		// Clear copy status after 2 seconds
@@ -170,6 +358,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case refreshMsg:
 		m.generateAllCodes()
 		return m, nil
+
+	case frameMsg:
+		if m.paused {
+			m.frameIdle = true
+			return m, nil
+		}
+		// Only the bar moves between seconds; codes refresh on tickMsg
+		m.barFraction = periodFraction(time.Time(msg))
+		return m, frameCmd(m.frameInterval)
 	}
 
 	return m, nil
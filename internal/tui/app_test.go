@@ -1,9 +1,14 @@
 package tui
 
 import (
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/pavanprakash21/totp-manager-go/internal/storage"
 )
 
@@ -123,9 +128,59 @@ func TestGenerateAllCodes_InvalidSecret(t *testing.T) {
 	model := NewModel(store)
 	model.generateAllCodes()
 
-	code := model.totpCodes["Invalid"]
-	if code != "ERROR" {
-		t.Errorf("Expected 'ERROR' for invalid secret, got %s", code)
+	// The failure is tracked separately so there is no code to copy
+	if code, ok := model.totpCodes["Invalid"]; ok {
+		t.Errorf("Expected no code for invalid secret, got %s", code)
+	}
+	if !model.codeErrors["Invalid"] {
+		t.Error("Expected the invalid secret to be flagged as an error")
+	}
+}
+
+// TestView_ErrorPlaceholder tests a failed code renders the placeholder in
+// the warning style while a valid service renders its code
+func TestView_ErrorPlaceholder(t *testing.T) {
+	for _, tt := range []struct {
+		setting string
+		want    string
+	}{
+		{"", storage.DefaultErrorPlaceholder},
+		{"n/a", "n/a"},
+	} {
+		store := &storage.Store{
+			Storage: &storage.Storage{
+				Version: 1,
+				Services: []storage.Service{
+					{Name: "Invalid", Secret: "INVALID!!!", CreatedAt: time.Now()},
+					{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+				},
+				Settings: storage.Settings{ErrorPlaceholder: tt.setting},
+			},
+		}
+
+		model := NewModel(store)
+		model.height = 40
+		model.generateAllCodes()
+		view := model.View()
+
+		if !containsString(view, tt.want) {
+			t.Errorf("Placeholder %q missing from view", tt.want)
+		}
+		if code := model.totpCodes["GitHub"]; code == "" || !containsString(view, code) {
+			t.Errorf("Valid service code %q missing from view", code)
+		}
+		if containsString(view, "------") {
+			t.Error("Failed codes should not use the not-yet-generated dashes")
+		}
+	}
+
+	if got := rowCodeStyle(false, true).GetForeground(); got != colorWarning {
+		t.Errorf("Failed code color = %v, want warning %v", got, colorWarning)
+	}
+	for _, selected := range []bool{false, true} {
+		if rowCodeStyle(selected, false).GetForeground() == colorWarning {
+			t.Errorf("Valid code (selected=%v) should not use the warning color", selected)
+		}
 	}
 }
 
@@ -321,6 +376,288 @@ func TestModelView_NoResults(t *testing.T) {
 	}
 }
 
+// TestSearchStatusLine_States tests the header for every search mode × query combination
+func TestSearchStatusLine_States(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+				{Name: "Google", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		searchMode   bool
+		query        string
+		wantContains string
+		wantEmpty    bool
+		wantVisible  int
+	}{
+		{"Search on, empty query", true, "", "Search: _  (2 results)", false, 2},
+		{"Search on, query", true, "git", "Search: git_  (1 results)", false, 1},
+		{"Search off, query", false, "git", "Filter: git  (1/2 services)", false, 1},
+		{"Search off, empty query", false, "", "", true, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewModel(store)
+			model.searchMode = tt.searchMode
+			model.searchQuery = tt.query
+			model.filterServices()
+
+			line := model.searchStatusLine()
+			if tt.wantEmpty && line != "" {
+				t.Errorf("Expected empty status line, got %q", line)
+			}
+			if !tt.wantEmpty && !containsString(line, tt.wantContains) {
+				t.Errorf("Expected status line to contain %q, got %q", tt.wantContains, line)
+			}
+			if len(model.filteredIndices) != tt.wantVisible {
+				t.Errorf("Expected %d visible services, got %d", tt.wantVisible, len(model.filteredIndices))
+			}
+		})
+	}
+}
+
+// TestFilterServices_SortByRecent tests equally matching results order by last-used
+func TestFilterServices_SortByRecent(t *testing.T) {
+	older := time.Unix(1700000000, 0)
+	newer := time.Unix(1700005000, 0)
+	newStore := func(sortRecent bool) *storage.Store {
+		return &storage.Store{
+			Storage: &storage.Storage{
+				Version: 1,
+				Services: []storage.Service{
+					{Name: "GitHub Work", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(), LastUsed: &older},
+					{Name: "GitHub Never", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+					{Name: "GitHub Personal", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(), LastUsed: &newer},
+					{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(), LastUsed: &newer},
+				},
+				Settings: storage.Settings{SearchSortRecent: sortRecent},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		sortRecent bool
+		want       []string
+	}{
+		{"Storage order by default", false, []string{"GitHub Work", "GitHub Never", "GitHub Personal"}},
+		{"Most recent first", true, []string{"GitHub Personal", "GitHub Work", "GitHub Never"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewModel(newStore(tt.sortRecent))
+			model.searchQuery = "github"
+			model.filterServices()
+
+			if len(model.filteredIndices) != len(tt.want) {
+				t.Fatalf("Expected %d results, got %d", len(tt.want), len(model.filteredIndices))
+			}
+			for i, name := range tt.want {
+				if got := model.services[model.filteredIndices[i]].Name; got != name {
+					t.Errorf("Result %d = %s, want %s", i, got, name)
+				}
+			}
+		})
+	}
+}
+
+// TestSearchStatusLine_LongQuery tests long queries are truncated for display only
+func TestSearchStatusLine_LongQuery(t *testing.T) {
+	longName := strings.Repeat("Enterprise", 6) // 60 characters
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+				{Name: longName, Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		wantVisible int
+	}{
+		{"200-char query matches nothing", strings.Repeat("x", 200), 0},
+		{"Long query matching a long name", longName, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewModel(store)
+			model.searchMode = true
+			model.searchQuery = tt.query
+			model.filterServices()
+
+			line := model.searchStatusLine()
+			if !containsString(line, "Search: …") {
+				t.Errorf("Expected truncated prompt, got %q", line)
+			}
+			if containsString(line, tt.query) {
+				t.Errorf("Full query should not be rendered, got %q", line)
+			}
+			if model.searchQuery != tt.query {
+				t.Error("The full query must be kept for matching")
+			}
+			if len(model.filteredIndices) != tt.wantVisible {
+				t.Errorf("Expected %d visible services, got %d", tt.wantVisible, len(model.filteredIndices))
+			}
+		})
+	}
+}
+
+// TestTruncateQuery tests rune-aware truncation keeps the end of the query
+func TestTruncateQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		max   int
+		want  string
+	}{
+		{"github", 10, "github"},
+		{"abcdefghij", 10, "abcdefghij"},
+		{"abcdefghijk", 10, "…cdefghijk"},
+		{"日本語のサービス名", 5, "…ービス名"},
+	}
+
+	for _, tt := range tests {
+		got := truncateQuery(tt.query, tt.max)
+		if got != tt.want {
+			t.Errorf("truncateQuery(%q, %d) = %q, want %q", tt.query, tt.max, got, tt.want)
+		}
+		if n := utf8.RuneCountInString(got); n > tt.max {
+			t.Errorf("truncateQuery(%q, %d) has %d runes, want at most %d", tt.query, tt.max, n, tt.max)
+		}
+	}
+}
+
+// TestTruncateWidth tests truncation counts display cells and never splits a rune
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"GitHub", 10, "GitHub"},
+		{"abcdefghijk", 10, "abcdefg..."},
+		{"★ Straße Bank", 10, "★ Straß..."},
+		{"日本語のサービス名", 10, "日本語..."},
+	}
+
+	for _, tt := range tests {
+		got := truncateWidth(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("truncateWidth(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if !utf8.ValidString(got) || lipgloss.Width(got) > tt.width {
+			t.Errorf("truncateWidth(%q, %d) = %q is %d cells or invalid UTF-8", tt.s, tt.width, got, lipgloss.Width(got))
+		}
+	}
+}
+
+// TestSearchStatusLine_Transitions tests the header stays in sync across key transitions
+func TestSearchStatusLine_Transitions(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+				{Name: "Google", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	model := NewModel(store)
+	press := func(msg tea.KeyMsg) {
+		newModel, _ := model.handleKeyPress(msg)
+		model = newModel.(Model)
+	}
+
+	// Search for "git", then leave search mode: filter stays active
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("git")})
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if !containsString(model.searchStatusLine(), "Filter: git  (1/2 services)") {
+		t.Errorf("Expected active filter header, got %q", model.searchStatusLine())
+	}
+
+	// Starting a new search clears the query, so all services must be shown again
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if !containsString(model.searchStatusLine(), "Search: _  (2 results)") {
+		t.Errorf("Expected fresh search header with all results, got %q", model.searchStatusLine())
+	}
+
+	// Leaving search with an empty query shows no header and all services
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.searchStatusLine() != "" {
+		t.Errorf("Expected no header after empty search, got %q", model.searchStatusLine())
+	}
+	if len(model.filteredIndices) != 2 {
+		t.Errorf("Expected 2 visible services, got %d", len(model.filteredIndices))
+	}
+}
+
+// TestView_RecoveryCodesHiddenFromList tests recovery codes only show on explicit reveal
+func TestView_RecoveryCodesHiddenFromList(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{
+					Name:      "GitHub",
+					Secret:    "JBSWY3DPEHPK3PXP",
+					CreatedAt: time.Now(),
+					Recovery:  []string{"backup-code-one", "backup-code-two"},
+				},
+			},
+		},
+	}
+
+	model := NewModel(store)
+	model.height = 40
+	model.generateAllCodes()
+
+	if containsString(model.View(), "backup-code-one") {
+		t.Fatal("List view must not show recovery codes")
+	}
+
+	press := func(key rune) {
+		newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		model = newModel.(Model)
+	}
+
+	// Details pane shows only a count until revealed
+	press('i')
+	view := model.View()
+	if !containsString(view, "2 codes") || containsString(view, "backup-code-one") {
+		t.Error("Details pane should show the recovery count without the codes")
+	}
+
+	press('r')
+	view = model.View()
+	if !containsString(view, "backup-code-one") || !containsString(view, "backup-code-two") {
+		t.Error("Details pane should show recovery codes after reveal")
+	}
+
+	// Closing the pane hides them again
+	newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	model = newModel.(Model)
+	if model.showDetails || model.revealRecovery {
+		t.Error("Esc should close the details pane and reset the reveal")
+	}
+	if containsString(model.View(), "backup-code-one") {
+		t.Error("List view must not show recovery codes after closing details")
+	}
+}
+
 // Helper function to check if string contains substring
 func containsString(s, substr string) bool {
 	return len(s) > 0 && len(substr) > 0 && (s == substr || len(s) >= len(substr) && findSubstring(s, substr))
@@ -334,3 +671,39 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+// TestView_Footer tests the footer shows the vault name and service counts,
+// and is hidden on short terminals
+func TestView_Footer(t *testing.T) {
+	store, err := storage.Create(filepath.Join(t.TempDir(), "work.enc"), "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	store.Services = []storage.Service{
+		{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		{Name: "GitLab", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+	}
+
+	model := NewModel(store)
+	model.height = 40
+
+	view := model.View()
+	if !containsString(view, "vault: work • 3 services") {
+		t.Errorf("Footer should name the vault and count services, got %q", view)
+	}
+	if containsString(view, "shown") {
+		t.Error("Unfiltered footer should not show a filtered count")
+	}
+
+	model.searchQuery = "git"
+	model.filterServices()
+	if view := model.View(); !containsString(view, "vault: work • 3 services • 2 shown") {
+		t.Errorf("Filtered footer should include the shown count, got %q", view)
+	}
+
+	model.height = minFooterHeight - 1
+	if containsString(model.View(), "vault: work") {
+		t.Error("Footer should be hidden on short terminals")
+	}
+}
@@ -0,0 +1,260 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanprakash21/totp-manager-go/internal/otpauth"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+	"github.com/pavanprakash21/totp-manager-go/internal/totp"
+)
+
+// Service form fields, in tab order
+const (
+	fieldName = iota
+	fieldIdentifier
+	fieldSecret
+	formFieldCount
+)
+
+// formLabels are the field labels, indexed by field
+var formLabels = [formFieldCount]string{"Name", "Identifier", "Secret"}
+
+// serviceForm is the add ('a') and edit ('e') form. Fields are edited like
+// the search query: typed runes append, backspace removes the last rune.
+// An otpauth-migration:// link entered while adding switches the form to a
+// confirmation list of its accounts.
+type serviceForm struct {
+	editing   string                 // name of the service being edited; empty when adding
+	values    [formFieldCount]string // field contents
+	focus     int                    // focused field
+	err       string                 // validation or save error from the last submit
+	migration *migrationImport       // decoded migration link awaiting confirmation
+}
+
+// openAddForm opens an empty form for a new service
+func (m *Model) openAddForm() {
+	m.form = &serviceForm{}
+}
+
+// openEditForm opens the form for the selected service, prefilled with its
+// name and identifier; the secret is left blank to keep the current one
+func (m *Model) openEditForm() {
+	service, ok := m.selectedService()
+	if !ok {
+		return
+	}
+	m.form = &serviceForm{editing: service.Name}
+	m.form.values[fieldName] = service.Name
+	m.form.values[fieldIdentifier] = service.Identifier
+}
+
+// handleFormKey handles keys while the service form is open. Every rune is
+// input, so shortcuts such as 'q' don't fire while typing.
+func (m Model) handleFormKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Copy the form so the previous model's form is never mutated
+	form := *m.form
+	m.form = &form
+
+	if form.migration != nil {
+		return m.handleMigrationKey(msg)
+	}
+
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m.quit()
+
+	case tea.KeyEsc:
+		m.form = nil
+
+	case tea.KeyTab, tea.KeyDown:
+		form.focus = (form.focus + 1) % formFieldCount
+
+	case tea.KeyShiftTab, tea.KeyUp:
+		form.focus = (form.focus + formFieldCount - 1) % formFieldCount
+
+	case tea.KeyEnter:
+		// Enter moves through the fields and saves from the last one
+		if form.focus < formFieldCount-1 {
+			form.focus++
+		} else {
+			m.submitForm()
+		}
+
+	case tea.KeyBackspace:
+		// Remove last rune (not byte) so multibyte input stays valid UTF-8
+		if value := form.values[form.focus]; value != "" {
+			_, size := utf8.DecodeLastRuneInString(value)
+			form.values[form.focus] = value[:len(value)-size]
+		}
+
+	case tea.KeyCtrlU:
+		form.values[form.focus] = ""
+
+	case tea.KeySpace:
+		form.values[form.focus] += " "
+
+	case tea.KeyRunes:
+		form.values[form.focus] += string(msg.Runes)
+	}
+
+	return m, nil
+}
+
+// submitForm adds or updates the service and saves immediately. Invalid
+// input, duplicate names and save failures keep the form open with the
+// error, leaving the store as it was. The store edits services in place,
+// so a failed save restores the snapshot and reloads the list from it.
+func (m *Model) submitForm() {
+	form := m.form
+	selected, _ := m.selectedService()
+	name := strings.TrimSpace(form.values[fieldName])
+	identifier := strings.TrimSpace(form.values[fieldIdentifier])
+	secret := totp.NormalizeSecret(form.values[fieldSecret])
+
+	// A migration link typed or pasted into any field imports its accounts
+	if form.editing == "" {
+		for _, value := range form.values {
+			if otpauth.IsMigrationURI(value) {
+				m.openMigrationImport(value)
+				return
+			}
+		}
+	}
+
+	previous := append([]storage.Service(nil), m.store.Services...)
+
+	var status string
+	if form.editing == "" {
+		if secret == "" {
+			form.err = "a secret is required"
+			form.focus = fieldSecret
+			return
+		}
+		service := storage.Service{Name: name, Identifier: identifier, Secret: secret, CreatedAt: m.now()}
+		if err := m.store.AddService(service); err != nil {
+			form.err = err.Error()
+			return
+		}
+		status = "✓ Added " + name
+	} else {
+		service, err := m.store.GetServiceCopy(form.editing)
+		if err != nil {
+			form.err = err.Error()
+			return
+		}
+		service.Name = name
+		service.Identifier = identifier
+		// A blank secret keeps the current one
+		if secret != "" {
+			service.Secret = secret
+			service.AllowWeakSecret = false
+		}
+		if err := m.store.UpdateService(form.editing, service); err != nil {
+			form.err = err.Error()
+			return
+		}
+		status = "✓ Updated " + name
+	}
+
+	if err := saveStore(m.store); err != nil {
+		m.store.Services = previous
+		m.reloadServices(selected.Name)
+		form.err = "not saved: " + err.Error()
+		return
+	}
+
+	m.form = nil
+	m.copyStatus = status
+	m.copyStatusTime = m.now()
+	m.reloadServices(name)
+}
+
+// deleteSelected removes the selected service and saves; the deletion is
+// undone if saving fails
+func (m *Model) deleteSelected() {
+	service, ok := m.selectedService()
+	if !ok {
+		return
+	}
+
+	m.copyStatusTime = m.now()
+	previous := append([]storage.Service(nil), m.store.Services...)
+	if _, err := m.store.RemoveService(service.Name); err != nil {
+		m.copyStatus = "⚠ " + err.Error()
+		return
+	}
+	if err := saveStore(m.store); err != nil {
+		m.store.Services = previous
+		m.reloadServices(service.Name)
+		m.copyStatus = "⚠ Not deleted: " + err.Error()
+		return
+	}
+	m.copyStatus = "✓ Deleted " + service.Name
+
+	cursor := m.cursor
+	m.reloadServices("")
+	if cursor < len(m.filteredIndices) {
+		m.cursor = cursor
+	} else if len(m.filteredIndices) > 0 {
+		m.cursor = len(m.filteredIndices) - 1
+	}
+	m.scrollToCursor()
+}
+
+// reloadServices picks up a changed store: codes are regenerated, the
+// current filter is reapplied and the cursor moves to the named service if
+// it is listed
+func (m *Model) reloadServices(selectName string) {
+	m.services = m.store.Services
+	m.totpCodes = make(map[string]string)
+	m.codeErrors = make(map[string]bool)
+	m.generateAllCodes()
+	m.filterServices()
+
+	for i, index := range m.filteredIndices {
+		if storage.SameName(m.services[index].Name, selectName) {
+			m.cursor = i
+			break
+		}
+	}
+	m.scrollToCursor()
+}
+
+// renderForm renders the service form as a bordered panel. The secret is
+// masked; the focused field shows a cursor.
+func (m Model) renderForm() string {
+	if m.form.migration != nil {
+		return m.renderMigration()
+	}
+
+	var b strings.Builder
+
+	if m.form.editing == "" {
+		b.WriteString("Add service")
+	} else {
+		b.WriteString(fmt.Sprintf("Edit %s", m.form.editing))
+	}
+	b.WriteString("\n")
+
+	for field, label := range formLabels {
+		value := m.form.values[field]
+		if field == fieldSecret {
+			value = strings.Repeat("•", utf8.RuneCountInString(value))
+		}
+
+		marker := "  "
+		if field == m.form.focus {
+			marker = "› "
+			value += "_"
+		}
+		if field == fieldSecret && m.form.editing != "" && m.form.values[field] == "" {
+			value += helpStyle.Render(" (blank keeps the current secret)")
+		}
+		b.WriteString(fmt.Sprintf("\n%s%-12s%s", marker, label+":", value))
+	}
+
+	return borderStyle.Render(b.String())
+}
@@ -0,0 +1,327 @@
+package tui
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// setupFormModel creates a saved store holding GitHub and AWS and a model on it
+func setupFormModel(t *testing.T) (Model, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	store, err := storage.Create(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, service := range []storage.Service{
+		{Name: "GitHub", Identifier: "octocat", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+	} {
+		if err := store.AddService(service); err != nil {
+			t.Fatalf("AddService() error = %v", err)
+		}
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	model := NewModel(store)
+	model.height = 40
+	return model, path
+}
+
+// press sends a key to the model
+func press(t *testing.T, m Model, msg tea.KeyMsg) Model {
+	t.Helper()
+	newModel, _ := m.handleKeyPress(msg)
+	return newModel.(Model)
+}
+
+// typeText types text into the model one rune at a time
+func typeText(t *testing.T, m Model, text string) Model {
+	t.Helper()
+	for _, r := range text {
+		m = press(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m
+}
+
+// loadFormStore loads the saved store at path
+func loadFormStore(t *testing.T, path string) *storage.Store {
+	t.Helper()
+	store, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return store
+}
+
+// TestServiceForm_Add tests adding a service saves it and selects it
+func TestServiceForm_Add(t *testing.T) {
+	model, path := setupFormModel(t)
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if model.form == nil {
+		t.Fatal("'a' should open the form")
+	}
+
+	// 'q' is typed into the name rather than quitting
+	model = typeText(t, model, "Quay")
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+	model = typeText(t, model, "me@example.com")
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyTab})
+	model = typeText(t, model, "gezd gnbv gy3t qojq")
+	if !containsString(model.View(), "•••") || containsString(model.View(), "gezd") {
+		t.Error("The secret should be masked in the form")
+	}
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if model.form != nil {
+		t.Fatalf("Form should close after saving, error = %q", model.form.err)
+	}
+	if model.copyStatus != "✓ Added Quay" {
+		t.Errorf("copyStatus = %q, want %q", model.copyStatus, "✓ Added Quay")
+	}
+	if service, ok := model.selectedService(); !ok || service.Name != "Quay" {
+		t.Errorf("Selected service = %q, want the new service", service.Name)
+	}
+	if model.totpCodes["Quay"] == "" {
+		t.Error("The new service should have a code")
+	}
+
+	service, err := loadFormStore(t, path).GetServiceCopy("Quay")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if service.Identifier != "me@example.com" || service.Secret != "GEZDGNBVGY3TQOJQ" {
+		t.Errorf("Saved service = %+v, want the identifier and normalized secret", service)
+	}
+}
+
+// TestServiceForm_AddInvalid tests invalid input keeps the form open with an
+// error and adds nothing
+func TestServiceForm_AddInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		secret  string
+		wantErr string
+	}{
+		{"Duplicate name", "github", "GEZDGNBVGY3TQOJQ", "service 'github' already exists"},
+		{"Missing secret", "Slack", "", "a secret is required"},
+		{"Invalid secret", "Slack", "not base32!", "invalid secret"},
+		{"Missing name", "", "GEZDGNBVGY3TQOJQ", "service name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, path := setupFormModel(t)
+
+			model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+			model = typeText(t, model, tt.service)
+			model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+			model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+			model = typeText(t, model, tt.secret)
+			model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+
+			if model.form == nil {
+				t.Fatal("Form should stay open on invalid input")
+			}
+			if !containsString(model.form.err, tt.wantErr) {
+				t.Errorf("Form error = %q, want it to mention %q", model.form.err, tt.wantErr)
+			}
+			if !containsString(model.View(), tt.wantErr) {
+				t.Error("View should show the form error")
+			}
+			if count := len(loadFormStore(t, path).Services); count != 2 {
+				t.Errorf("Saved services = %d, want 2", count)
+			}
+
+			model = press(t, model, tea.KeyMsg{Type: tea.KeyEsc})
+			if model.form != nil || len(model.services) != 2 {
+				t.Error("Esc should close the form without adding")
+			}
+		})
+	}
+}
+
+// TestServiceForm_Edit tests renaming keeps the secret when left blank
+func TestServiceForm_Edit(t *testing.T) {
+	model, path := setupFormModel(t)
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if model.form == nil || model.form.values[fieldName] != "GitHub" || model.form.values[fieldIdentifier] != "octocat" {
+		t.Fatalf("'e' should open the form prefilled with the selected service, got %+v", model.form)
+	}
+
+	model = typeText(t, model, " Work")
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyTab})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyCtrlU})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if model.form != nil {
+		t.Fatalf("Form should close after saving, error = %q", model.form.err)
+	}
+	if service, ok := model.selectedService(); !ok || service.Name != "GitHub Work" {
+		t.Errorf("Selected service = %q, want the renamed service", service.Name)
+	}
+
+	store := loadFormStore(t, path)
+	service, err := store.GetServiceCopy("GitHub Work")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if service.Secret != "JBSWY3DPEHPK3PXP" || service.Identifier != "" {
+		t.Errorf("Edited service = %+v, want the secret kept and the identifier cleared", service)
+	}
+	if _, err := store.GetServiceCopy("GitHub"); err == nil {
+		t.Error("The old name should be gone")
+	}
+}
+
+// TestServiceForm_SaveFailure tests a failed save undoes the change
+func TestServiceForm_SaveFailure(t *testing.T) {
+	model, _ := setupFormModel(t)
+
+	oldSave := saveStore
+	saveStore = func(*storage.Store) error { return errors.New("disk full") }
+	defer func() { saveStore = oldSave }()
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyCtrlU})
+	model = typeText(t, model, "Renamed")
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if model.form == nil || model.form.err != "not saved: disk full" {
+		t.Fatalf("Form should stay open with the save error, got %+v", model.form)
+	}
+	if model.store.Services[0].Name != "GitHub" || model.services[0].Name != "GitHub" {
+		t.Error("A failed save should restore the original service")
+	}
+	if _, ok := model.totpCodes["Renamed"]; ok || model.totpCodes["GitHub"] == "" {
+		t.Errorf("Codes = %v, want them regenerated for the restored services", model.totpCodes)
+	}
+	if service, ok := model.selectedService(); !ok || service.Name != "GitHub" {
+		t.Errorf("Selected service = %q, want GitHub", service.Name)
+	}
+
+	// Adding fails the same way and leaves the list as it was
+	model.form = nil
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyDown})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	model = typeText(t, model, "Slack")
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyUp})
+	model = typeText(t, model, "GEZDGNBVGY3TQOJQ")
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if model.form == nil || model.form.err != "not saved: disk full" {
+		t.Fatalf("Form should stay open with the save error, got %+v", model.form)
+	}
+	if len(model.store.Services) != 2 || len(model.services) != 2 || len(model.filteredIndices) != 2 {
+		t.Errorf("Listed %d of %d services, want the 2 originals", len(model.filteredIndices), len(model.services))
+	}
+	if _, ok := model.totpCodes["Slack"]; ok {
+		t.Error("The unsaved service should have no code")
+	}
+	if service, ok := model.selectedService(); !ok || service.Name != "AWS" {
+		t.Errorf("Selected service = %q, want the selection kept on AWS", service.Name)
+	}
+}
+
+// TestServiceForm_DeleteSaveFailure tests a failed save keeps the service
+// listed and selected
+func TestServiceForm_DeleteSaveFailure(t *testing.T) {
+	model, _ := setupFormModel(t)
+
+	oldSave := saveStore
+	saveStore = func(*storage.Store) error { return errors.New("disk full") }
+	defer func() { saveStore = oldSave }()
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyDown})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+
+	if model.copyStatus != "⚠ Not deleted: disk full" {
+		t.Errorf("copyStatus = %q, want the save error", model.copyStatus)
+	}
+	if len(model.store.Services) != 2 || len(model.filteredIndices) != 2 || model.totpCodes["AWS"] == "" {
+		t.Error("A failed save should keep AWS listed with its code")
+	}
+	if service, ok := model.selectedService(); !ok || service.Name != "AWS" {
+		t.Errorf("Selected service = %q, want AWS", service.Name)
+	}
+}
+
+// TestServiceForm_Delete tests 'd' asks first and only 'y' deletes
+func TestServiceForm_Delete(t *testing.T) {
+	model, path := setupFormModel(t)
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if !model.confirmDelete || !containsString(model.View(), "Delete GitHub?") {
+		t.Fatal("'d' should ask to confirm the deletion")
+	}
+
+	// Any other key cancels, and a paste never confirms
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y"), Paste: true})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if model.confirmDelete || len(model.services) != 2 {
+		t.Fatal("Cancelling should keep the service")
+	}
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if model.copyStatus != "✓ Deleted GitHub" {
+		t.Errorf("copyStatus = %q, want %q", model.copyStatus, "✓ Deleted GitHub")
+	}
+	if service, ok := model.selectedService(); !ok || service.Name != "AWS" {
+		t.Errorf("Selected service = %q, want AWS", service.Name)
+	}
+	if names := loadFormStore(t, path).Services; len(names) != 1 || names[0].Name != "AWS" {
+		t.Errorf("Saved services = %+v, want only AWS", names)
+	}
+}
+
+// TestServiceForm_Paste tests pasted text goes into the focused field
+func TestServiceForm_Paste(t *testing.T) {
+	model, _ := setupFormModel(t)
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyDown})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyDown})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("GEZDGNBVGY3TQOJQ\n"), Paste: true})
+
+	if model.searchMode || model.form.values[fieldSecret] != "GEZDGNBVGY3TQOJQ" {
+		t.Errorf("Secret = %q, want the paste without the newline", model.form.values[fieldSecret])
+	}
+}
+
+// TestServiceForm_AddFromEmptyState tests the first service can be added
+// from the empty state
+func TestServiceForm_AddFromEmptyState(t *testing.T) {
+	store, err := storage.Create(filepath.Join(t.TempDir(), "secrets.enc"), "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	model := NewModel(store)
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if !containsString(model.View(), "Add service") {
+		t.Fatal("The form should replace the empty state")
+	}
+	model = typeText(t, model, "GitHub")
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyUp})
+	model = typeText(t, model, "JBSWY3DPEHPK3PXP")
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if len(model.services) != 1 || model.services[0].Name != "GitHub" {
+		t.Errorf("Services = %+v, want GitHub", model.services)
+	}
+}
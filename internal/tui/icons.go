@@ -0,0 +1,41 @@
+package tui
+
+import "strings"
+
+// fallbackGlyph is the Nerd Fonts key icon used for unrecognized services
+const fallbackGlyph = ""
+
+// serviceGlyphs maps lowercase name keywords to Nerd Fonts brand icons.
+// Checked in order; the first keyword contained in the name wins.
+var serviceGlyphs = []struct {
+	keyword string
+	glyph   string
+}{
+	{"github", ""},
+	{"gitlab", ""},
+	{"google", ""},
+	{"gmail", ""},
+	{"aws", ""},
+	{"amazon", ""},
+	{"microsoft", ""},
+	{"apple", ""},
+	{"dropbox", ""},
+	{"slack", ""},
+	{"facebook", ""},
+	{"twitter", ""},
+	{"steam", ""},
+	{"bitbucket", ""},
+	{"digitalocean", ""},
+}
+
+// serviceGlyph infers a brand icon from a service name (the issuer),
+// falling back to a generic key
+func serviceGlyph(name string) string {
+	lower := strings.ToLower(name)
+	for _, entry := range serviceGlyphs {
+		if strings.Contains(lower, entry.keyword) {
+			return entry.glyph
+		}
+	}
+	return fallbackGlyph
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// TestServiceGlyph tests known issuers map to brand glyphs and others fall back
+func TestServiceGlyph(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"GitHub", ""},
+		{"github work", ""},
+		{"Google", ""},
+		{"AWS", ""},
+		{"Amazon", ""},
+		{"GitLab", ""},
+		{"Acme Corp", fallbackGlyph},
+		{"", fallbackGlyph},
+	}
+
+	for _, tt := range tests {
+		if got := serviceGlyph(tt.name); got != tt.want {
+			t.Errorf("serviceGlyph(%q) = %U, want %U", tt.name, []rune(got), []rune(tt.want))
+		}
+	}
+}
+
+// TestView_NerdFonts tests glyphs are only rendered when enabled
+func TestView_NerdFonts(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	model := NewModel(store)
+	model.width, model.height = 100, 30
+	if containsString(model.View(), "") {
+		t.Error("Glyphs should not render without --nerd-fonts")
+	}
+
+	model = model.WithNerdFonts(true)
+	if !containsString(model.View(), " GitHub") {
+		t.Error("Expected the GitHub glyph before the service name")
+	}
+}
@@ -1,14 +1,227 @@
 package tui
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pavanprakash21/totp-manager-go/internal/clipboard"
+	"github.com/pavanprakash21/totp-manager-go/internal/otpauth"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
 )
 
+// expiryWarningSeconds warns when a copied code has this little time left
+const expiryWarningSeconds = 3
+
+// Clipboard backends (overridable in tests)
+var (
+	copyToClipboard   = clipboard.Copy
+	readFromClipboard = clipboard.Read
+	clearClipboard    = clipboard.ClearAfter
+	flushClipboard    = (*clipboard.ScheduledClear).Flush
+)
+
+// saveStore persists the store after a copy, a pin or a form edit (overridable in tests)
+var saveStore = (*storage.Store).Save
+
+// copySelected copies the selected service's code to the clipboard
+func (m *Model) copySelected() {
+	service, ok := m.selectedService()
+	if !ok {
+		return
+	}
+
+	code := m.totpCodes[service.Name]
+	if code == "" {
+		return
+	}
+
+	copiedAt := m.now()
+	if !m.writeClipboard(code, copiedAt) {
+		return
+	}
+
+	if remaining := secondsUntilExpiry(copiedAt, service.EffectivePeriod()); remaining <= expiryWarningSeconds {
+		// Warn so a code that is about to roll over isn't pasted
+		m.copyStatus = fmt.Sprintf("⚠ Copied, but code expires in %ds — may fail", remaining)
+	} else {
+		m.copyStatus = "✓ Copied to clipboard"
+	}
+
+	m.recordUse(service.Name)
+}
+
+// copyPrevious copies the selected service's code from the previous period,
+// for servers whose clock lags behind and reject the current code
+func (m *Model) copyPrevious() {
+	service, ok := m.selectedService()
+	if !ok {
+		return
+	}
+
+	copiedAt := m.now()
+	period := time.Duration(service.EffectivePeriod()) * time.Second
+	code, err := service.Code(copiedAt.Add(-period))
+	if err != nil {
+		m.copyStatus = "⚠ Could not generate the previous code"
+		m.copyStatusTime = copiedAt
+		return
+	}
+
+	if !m.writeClipboard(code, copiedAt) {
+		return
+	}
+	m.copyStatus = "✓ Copied PREVIOUS code to clipboard"
+
+	m.recordUse(service.Name)
+}
+
+// recordUse updates the service's LastUsed timestamp and usage count and
+// saves, unless the session was started with --no-last-used-save
+func (m *Model) recordUse(name string) {
+	if m.noUseSave {
+		return
+	}
+	m.store.RecordUse(name)
+	_ = saveStore(m.store)
+}
+
+// writeClipboard copies code, setting a warning status and returning false
+// if the copy failed or (when verification is on) did not take effect
+func (m *Model) writeClipboard(code string, copiedAt time.Time) bool {
+	m.copyStatusTime = copiedAt
+
+	// T047: Copy to clipboard with visual confirmation
+	if err := copyToClipboard(code); err != nil {
+		// T048: Clipboard error handling with fallback; a failed copy
+		// doesn't count as a use
+		m.copyStatus = "⚠ Clipboard unavailable. Code: " + code
+		return false
+	}
+
+	// Some backends report success without updating the clipboard; OSC 52
+	// cannot be read back, so it goes unverified
+	if m.verifyClipboard {
+		if got, err := readFromClipboard(); !errors.Is(err, clipboard.ErrReadUnsupported) && (err != nil || got != code) {
+			m.copyStatus = "⚠ Clipboard did not update. Code: " + code
+			return false
+		}
+	}
+
+	// Only the latest copy's timer matters
+	if m.pendingClear != nil {
+		m.pendingClear.Stop()
+	}
+	m.pendingClear = clearClipboard(code, m.clipClear)
+
+	return true
+}
+
+// quit exits the TUI. A pending clipboard clear runs first so quitting
+// before the timer fires does not leave the code behind.
+func (m Model) quit() (tea.Model, tea.Cmd) {
+	if m.pendingClear != nil {
+		flushClipboard(m.pendingClear)
+	}
+	return m, tea.Quit
+}
+
+// togglePin pins or unpins the selected service, saves, and moves the
+// cursor with the service to its new position
+func (m *Model) togglePin() {
+	service, ok := m.selectedService()
+	if !ok {
+		return
+	}
+
+	m.copyStatusTime = m.now()
+	pinned, err := m.store.TogglePinned(service.Name)
+	if err != nil {
+		m.copyStatus = "⚠ " + err.Error()
+		return
+	}
+	if err := saveStore(m.store); err != nil {
+		m.copyStatus = "⚠ Pin not saved: " + err.Error()
+	} else if pinned {
+		m.copyStatus = "★ Pinned " + service.Name
+	} else {
+		m.copyStatus = "Unpinned " + service.Name
+	}
+
+	selected := m.filteredIndices[m.cursor]
+	m.orderFiltered()
+	for i, index := range m.filteredIndices {
+		if index == selected {
+			m.cursor = i
+			break
+		}
+	}
+	m.scrollToCursor()
+}
+
+// cycleMatch moves the cursor by step through the listed services, wrapping
+// around at either end (n/N, like search-next in a pager)
+func (m *Model) cycleMatch(step int) {
+	count := len(m.filteredIndices)
+	if count == 0 {
+		return
+	}
+	m.cursor = ((m.cursor+step)%count + count) % count
+	m.scrollToCursor()
+}
+
+// scrollToCursor adjusts the viewport so the cursor row is visible
+func (m *Model) scrollToCursor() {
+	if m.cursor < m.viewportOffset {
+		m.viewportOffset = m.cursor
+	}
+	if maxVisible := m.maxVisibleItems(); m.cursor >= m.viewportOffset+maxVisible {
+		m.viewportOffset = m.cursor - maxVisible + 1
+	}
+}
+
+// secondsUntilExpiry returns whole seconds until the period containing t ends
+func secondsUntilExpiry(t time.Time, period int) int {
+	return period - int(t.Unix()%int64(period))
+}
+
 // handleKeyPress handles all keyboard input
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Pasted text must never trigger single-key shortcuts (e.g. 'q', 'd')
+	if msg.Paste {
+		return m.handlePaste(msg.Runes)
+	}
+
+	// Service form: every key edits the form (ctrl+c still quits)
+	if m.form != nil {
+		return m.handleFormKey(msg)
+	}
+
+	// Delete confirmation: 'y' deletes, any other key cancels
+	if m.confirmDelete {
+		m.confirmDelete = false
+		switch msg.String() {
+		case "ctrl+c":
+			return m.quit()
+		case "y", "Y":
+			m.deleteSelected()
+		}
+		return m, nil
+	}
+
+	// Help overlay: any key closes it (ctrl+c still quits)
+	if m.showHelp {
+		if msg.String() == "ctrl+c" {
+			return m.quit()
+		}
+		m.showHelp = false
+		return m, nil
+	}
+
 	// Search mode handling
 	if m.searchMode {
 		switch msg.Type {
@@ -18,15 +231,16 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case tea.KeyBackspace:
-			// Remove last character from search query
+			// Remove last rune (not byte) so multibyte input stays valid UTF-8
 			if len(m.searchQuery) > 0 {
-				m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
+				_, size := utf8.DecodeLastRuneInString(m.searchQuery)
+				m.searchQuery = m.searchQuery[:len(m.searchQuery)-size]
 				m.filterServices()
 			}
 			return m, nil
 
 		case tea.KeyCtrlC:
-			return m, tea.Quit
+			return m.quit()
 
 		case tea.KeyCtrlU:
 			// Clear search and show all services (vim-style clear line)
@@ -48,33 +262,13 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Allow navigation in search mode
 			if m.cursor < len(m.filteredIndices)-1 {
 				m.cursor++
-				maxVisibleItems := (m.height - 9) / 3
-				if maxVisibleItems < 1 {
-					maxVisibleItems = 1
-				}
-				if m.cursor >= m.viewportOffset+maxVisibleItems {
-					m.viewportOffset = m.cursor - maxVisibleItems + 1
-				}
+				m.scrollToCursor()
 			}
 			return m, nil
 
 		case tea.KeySpace, tea.KeyEnter:
 			// Allow copying in search mode
-			if len(m.filteredIndices) > 0 && m.cursor < len(m.filteredIndices) {
-				serviceIdx := m.filteredIndices[m.cursor]
-				service := m.services[serviceIdx]
-				code := m.totpCodes[service.Name]
-				if code != "" {
-					if err := clipboard.Copy(code); err != nil {
-						m.copyStatus = "⚠ Clipboard unavailable. Code: " + code
-					} else {
-						m.copyStatus = "✓ Copied to clipboard"
-					}
-					m.copyStatusTime = time.Now()
-					m.store.UpdateLastUsed(service.Name)
-					_ = m.store.Save()
-				}
-			}
+			m.copySelected()
 			return m, nil
 
 		case tea.KeyRunes:
@@ -87,12 +281,33 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Details pane handling: only closing, revealing and quitting apply
+	if m.showDetails {
+		switch msg.String() {
+		case "esc", "i":
+			m.showDetails = false
+			m.revealRecovery = false
+		case "r":
+			m.revealRecovery = !m.revealRecovery
+		case "q", "ctrl+c":
+			return m.quit()
+		}
+		return m, nil
+	}
+
 	// Normal mode handling
 	switch msg.String() {
+	// Open the keybinding overlay
+	case "?":
+		m.showHelp = true
+		return m, nil
+
 	// Enter search mode with '/'
 	case "/":
+		// Start a fresh search; refilter so results match the empty query
 		m.searchMode = true
 		m.searchQuery = ""
+		m.filterServices()
 		return m, nil
 
 	// Clear search filter and show all services
@@ -103,7 +318,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	// T051: Exit on 'q' or ESC
 	case "q", "esc", "ctrl+c":
-		return m, tea.Quit
+		return m.quit()
 
 	// T044: Arrow key navigation (↑↓)
 	case "up", "k": // T045: Vim key 'k' for up
@@ -119,36 +334,57 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.cursor < len(m.filteredIndices)-1 {
 			m.cursor++
 			// Scroll viewport down if cursor goes below visible area
-			maxVisibleItems := (m.height - 9) / 3
-			if maxVisibleItems < 1 {
-				maxVisibleItems = 1
-			}
-			if m.cursor >= m.viewportOffset+maxVisibleItems {
-				m.viewportOffset = m.cursor - maxVisibleItems + 1
-			}
+			m.scrollToCursor()
 		}
 
 	// T046: Spacebar to copy code to clipboard
 	case " ", "enter":
-		if len(m.filteredIndices) > 0 && m.cursor < len(m.filteredIndices) {
-			// Get actual service index from filtered list
-			serviceIdx := m.filteredIndices[m.cursor]
-			service := m.services[serviceIdx]
-			code := m.totpCodes[service.Name]
-			if code != "" {
-				// T047: Copy to clipboard with visual confirmation
-				if err := clipboard.Copy(code); err != nil {
-					// T048: Clipboard error handling with fallback
-					m.copyStatus = "⚠ Clipboard unavailable. Code: " + code
-				} else {
-					m.copyStatus = "✓ Copied to clipboard"
-				}
-				m.copyStatusTime = time.Now()
+		m.copySelected()
 
-				// Update LastUsed timestamp
-				m.store.UpdateLastUsed(service.Name)
-				_ = m.store.Save()
-			}
+	// Copy the Nth visible service directly (1-9); out-of-range keys do nothing
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if index := int(msg.Runes[0] - '1'); index < len(m.filteredIndices) {
+			m.cursor = index
+			m.scrollToCursor()
+			m.copySelected()
+		}
+
+	// Copy the previous period's code (for servers running behind)
+	case "p":
+		m.copyPrevious()
+
+	// Cycle through the listed (filtered) services, wrapping around
+	case "n":
+		m.cycleMatch(1)
+
+	case "N":
+		m.cycleMatch(-1)
+
+	// Pin or unpin the selected service (pinned services list first)
+	case "f":
+		m.togglePin()
+
+	// Switch between the countdown and the expiry clock time
+	case "t":
+		m.showExpiresAt = !m.showExpiresAt
+
+	// Add a service, or edit or delete the selected one
+	case "a":
+		m.openAddForm()
+
+	case "e":
+		m.openEditForm()
+
+	case "d":
+		if _, ok := m.selectedService(); ok {
+			m.confirmDelete = true
+		}
+
+	// Open the details pane for the selected service
+	case "i":
+		if _, ok := m.selectedService(); ok {
+			m.showDetails = true
+			m.revealRecovery = false
 		}
 
 	// Home/End keys for quick navigation
@@ -160,15 +396,47 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if len(m.filteredIndices) > 0 {
 			m.cursor = len(m.filteredIndices) - 1
 			// Scroll to show last item
-			maxVisibleItems := (m.height - 9) / 3
-			if maxVisibleItems < 1 {
-				maxVisibleItems = 1
-			}
-			if m.cursor >= maxVisibleItems {
-				m.viewportOffset = m.cursor - maxVisibleItems + 1
-			}
+			m.scrollToCursor()
+		}
+	}
+
+	return m, nil
+}
+
+// handlePaste routes bracketed-paste content into the focused form field,
+// or else the search query. Control characters (e.g. trailing newlines) are
+// dropped.
+func (m Model) handlePaste(runes []rune) (tea.Model, tea.Cmd) {
+	if m.showDetails || m.showHelp || m.confirmDelete {
+		return m, nil
+	}
+
+	var pasted strings.Builder
+	for _, r := range runes {
+		if !unicode.IsControl(r) {
+			pasted.WriteRune(r)
 		}
 	}
 
+	if m.form != nil {
+		form := *m.form
+		m.form = &form
+		switch {
+		case form.migration != nil:
+			// The confirmation list has no input
+		case form.editing == "" && otpauth.IsMigrationURI(pasted.String()):
+			m.openMigrationImport(pasted.String())
+		default:
+			form.values[form.focus] += pasted.String()
+		}
+		return m, nil
+	}
+
+	if !m.searchMode {
+		m.searchMode = true
+		m.searchQuery = ""
+	}
+	m.searchQuery += pasted.String()
+	m.filterServices()
 	return m, nil
 }
@@ -1,10 +1,15 @@
 package tui
 
 import (
+	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanprakash21/totp-manager-go/internal/clipboard"
 	"github.com/pavanprakash21/totp-manager-go/internal/storage"
 )
 
@@ -207,6 +212,44 @@ func TestHandleKeyPress_SearchBackspace(t *testing.T) {
 	}
 }
 
+// TestHandleKeyPress_SearchBackspaceMultibyte tests backspace removes a whole rune
+func TestHandleKeyPress_SearchBackspaceMultibyte(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"git日本", "git日"},
+		{"a🔐", "a"},
+		{"é", ""},
+	}
+
+	for _, tt := range tests {
+		model := NewModel(store)
+		model.searchMode = true
+		model.searchQuery = tt.query
+
+		msg := tea.KeyMsg{Type: tea.KeyBackspace}
+		newModel, _ := model.handleKeyPress(msg)
+		m := newModel.(Model)
+
+		if m.searchQuery != tt.want {
+			t.Errorf("Backspace on %q: expected %q, got %q", tt.query, tt.want, m.searchQuery)
+		}
+		if !utf8.ValidString(m.searchQuery) {
+			t.Errorf("Backspace on %q left invalid UTF-8: %q", tt.query, m.searchQuery)
+		}
+	}
+}
+
 // TestHandleKeyPress_SearchClearFilter tests Ctrl+U to clear search
 func TestHandleKeyPress_SearchClearFilter(t *testing.T) {
 	store := &storage.Store{
@@ -376,3 +419,814 @@ func TestHandleKeyPress_EmptyList(t *testing.T) {
 		t.Errorf("Expected cursor at 0 on empty list, got %d", m.cursor)
 	}
 }
+
+// TestCopySelected_ExpiryWarning tests copying near the period boundary warns about expiry
+func TestCopySelected_ExpiryWarning(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	oldCopy := copyToClipboard
+	copyToClipboard = func(string) error { return nil }
+	defer func() { copyToClipboard = oldCopy }()
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		wantWarn bool
+	}{
+		{"Near boundary", time.Unix(1699999980+28, 0), true},
+		{"Fresh code", time.Unix(1699999980+5, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := NewModel(store)
+			model.generateAllCodes()
+			model.now = func() time.Time { return tt.at }
+
+			newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeySpace})
+			m := newModel.(Model)
+
+			warned := containsString(m.copyStatus, "expires in 2s")
+			if warned != tt.wantWarn {
+				t.Errorf("copyStatus = %q, want expiry warning = %v", m.copyStatus, tt.wantWarn)
+			}
+			if !tt.wantWarn && m.copyStatus != "✓ Copied to clipboard" {
+				t.Errorf("copyStatus = %q, want plain success", m.copyStatus)
+			}
+		})
+	}
+}
+
+// TestCopySelected_ExpiryWarningUsesServicePeriod tests the warning follows
+// the service's own period rather than 30s
+func TestCopySelected_ExpiryWarningUsesServicePeriod(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "Okta", Secret: "JBSWY3DPEHPK3PXP", Period: 60, CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	oldCopy := copyToClipboard
+	copyToClipboard = func(string) error { return nil }
+	defer func() { copyToClipboard = oldCopy }()
+
+	// 1699999980 is a multiple of 60: 28s in leaves 32s of a 60s period
+	model := NewModel(store)
+	model.generateAllCodes()
+	model.now = func() time.Time { return time.Unix(1699999980+28, 0) }
+
+	newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeySpace})
+	if m := newModel.(Model); m.copyStatus != "✓ Copied to clipboard" {
+		t.Errorf("copyStatus = %q, want plain success 32s before a 60s period ends", m.copyStatus)
+	}
+}
+
+// TestCopySelected_IncrementsUseCount tests each copy bumps the service's usage count
+func TestCopySelected_IncrementsUseCount(t *testing.T) {
+	store, err := storage.Create(filepath.Join(t.TempDir(), "secrets.enc"), "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := store.AddService(storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddService() error = %v", err)
+	}
+
+	oldCopy := copyToClipboard
+	copyToClipboard = func(string) error { return nil }
+	defer func() { copyToClipboard = oldCopy }()
+
+	model := NewModel(store)
+	model.generateAllCodes()
+
+	for i := 0; i < 2; i++ {
+		newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+		model = newModel.(Model)
+	}
+
+	if got := store.Services[0].UseCount; got != 2 {
+		t.Errorf("UseCount = %d, want 2", got)
+	}
+	if store.Services[0].LastUsed == nil {
+		t.Error("LastUsed should be set after copying")
+	}
+}
+
+// TestCopySelected_NoLastUsedSave tests copies still work but skip RecordUse
+// and Save when --no-last-used-save is set
+func TestCopySelected_NoLastUsedSave(t *testing.T) {
+	tests := []struct {
+		name      string
+		noSave    bool
+		wantSaves int
+	}{
+		{"default saves each copy", false, 2},
+		{"flag suppresses saves", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := storage.Create(filepath.Join(t.TempDir(), "secrets.enc"), "test-passphrase")
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if err := store.AddService(storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}); err != nil {
+				t.Fatalf("AddService() error = %v", err)
+			}
+
+			var copied string
+			oldCopy, oldSave := copyToClipboard, saveStore
+			copyToClipboard = func(s string) error { copied = s; return nil }
+			saves := 0
+			saveStore = func(*storage.Store) error { saves++; return nil }
+			defer func() { copyToClipboard, saveStore = oldCopy, oldSave }()
+
+			model := NewModel(store).WithNoLastUsedSave(tt.noSave)
+			model.generateAllCodes()
+
+			newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+			model = newModel.(Model)
+			newModel, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+			model = newModel.(Model)
+
+			if copied == "" {
+				t.Error("Code should still be copied")
+			}
+			if saves != tt.wantSaves {
+				t.Errorf("Save calls = %d, want %d", saves, tt.wantSaves)
+			}
+			if used := store.Services[0].LastUsed != nil; used == tt.noSave {
+				t.Errorf("LastUsed set = %v, want %v", used, !tt.noSave)
+			}
+		})
+	}
+}
+
+// TestCopySelected_FailedCopyNotRecorded tests only successful copies update LastUsed
+func TestCopySelected_FailedCopyNotRecorded(t *testing.T) {
+	tests := []struct {
+		name      string
+		copyErr   error
+		wantUsed  bool
+		wantCount int
+	}{
+		{"Clipboard failure", errors.New("no clipboard"), false, 0},
+		{"Clipboard success", nil, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := storage.Create(filepath.Join(t.TempDir(), "secrets.enc"), "test-passphrase")
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if err := store.AddService(storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}); err != nil {
+				t.Fatalf("AddService() error = %v", err)
+			}
+
+			oldCopy := copyToClipboard
+			copyToClipboard = func(string) error { return tt.copyErr }
+			defer func() { copyToClipboard = oldCopy }()
+
+			model := NewModel(store)
+			model.generateAllCodes()
+			model.copySelected()
+
+			used := store.Services[0].LastUsed != nil
+			if used != tt.wantUsed {
+				t.Errorf("LastUsed set = %v, want %v", used, tt.wantUsed)
+			}
+			if store.Services[0].UseCount != tt.wantCount {
+				t.Errorf("UseCount = %d, want %d", store.Services[0].UseCount, tt.wantCount)
+			}
+			if tt.copyErr != nil && !containsString(model.copyStatus, "Clipboard unavailable") {
+				t.Errorf("copyStatus = %q, want clipboard fallback", model.copyStatus)
+			}
+		})
+	}
+}
+
+// TestCopySelected_VerifyClipboard tests the opt-in read-back check
+func TestCopySelected_VerifyClipboard(t *testing.T) {
+	tests := []struct {
+		name       string
+		verify     bool
+		readBack   string
+		readErr    error
+		wantStatus string
+		wantUsed   bool
+	}{
+		{"Verification off ignores mismatch", false, "stale", nil, "✓ Copied to clipboard", true},
+		{"Mismatch warns", true, "stale", nil, "⚠ Clipboard did not update", false},
+		{"Read failure warns", true, "", errors.New("no read permission"), "⚠ Clipboard did not update", false},
+		{"Match succeeds", true, "", nil, "✓ Copied to clipboard", true},
+		{"OSC 52 goes unverified", true, "", clipboard.ErrReadUnsupported, "✓ Copied to clipboard", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := storage.Create(filepath.Join(t.TempDir(), "secrets.enc"), "test-passphrase")
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if err := store.AddService(storage.Service{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}); err != nil {
+				t.Fatalf("AddService() error = %v", err)
+			}
+			store.Settings.VerifyClipboard = tt.verify
+
+			// The stub clipboard always reports a successful copy; an empty
+			// readBack without an error echoes what was copied
+			var copied string
+			oldCopy, oldRead := copyToClipboard, readFromClipboard
+			copyToClipboard = func(text string) error { copied = text; return nil }
+			readFromClipboard = func() (string, error) {
+				if tt.readBack == "" && tt.readErr == nil {
+					return copied, nil
+				}
+				return tt.readBack, tt.readErr
+			}
+			defer func() { copyToClipboard, readFromClipboard = oldCopy, oldRead }()
+
+			model := NewModel(store)
+			model.generateAllCodes()
+			model.now = func() time.Time { return time.Unix(1699999980+5, 0) }
+			model.copySelected()
+
+			if !containsString(model.copyStatus, tt.wantStatus) {
+				t.Errorf("copyStatus = %q, want %q", model.copyStatus, tt.wantStatus)
+			}
+			if used := store.Services[0].LastUsed != nil; used != tt.wantUsed {
+				t.Errorf("LastUsed set = %v, want %v", used, tt.wantUsed)
+			}
+		})
+	}
+}
+
+// TestSecondsUntilExpiry tests remaining seconds within a period
+func TestSecondsUntilExpiry(t *testing.T) {
+	base := time.Unix(1699999980, 0) // period boundary
+	tests := []struct {
+		offset int
+		want   int
+	}{
+		{0, 30},
+		{1, 29},
+		{29, 1},
+	}
+
+	for _, tt := range tests {
+		got := secondsUntilExpiry(base.Add(time.Duration(tt.offset)*time.Second), 30)
+		if got != tt.want {
+			t.Errorf("secondsUntilExpiry(+%ds) = %d, want %d", tt.offset, got, tt.want)
+		}
+	}
+}
+
+// TestHandleKeyPress_PasteDoesNotTriggerShortcuts tests pasted 'q' doesn't quit
+func TestHandleKeyPress_PasteDoesNotTriggerShortcuts(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "Quay", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	model := NewModel(store)
+
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q\n"), Paste: true}
+	newModel, cmd := model.handleKeyPress(msg)
+	m := newModel.(Model)
+
+	if cmd != nil {
+		t.Fatal("Pasting 'q' must not return a command (quit)")
+	}
+	if !m.searchMode {
+		t.Error("Paste should be routed into search mode")
+	}
+	if m.searchQuery != "q" {
+		t.Errorf("Expected search query %q, got %q", "q", m.searchQuery)
+	}
+	if len(m.filteredIndices) != 1 {
+		t.Errorf("Expected 1 filtered service, got %d", len(m.filteredIndices))
+	}
+
+	// Pasting while already searching appends to the query
+	msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ua"), Paste: true}
+	newModel, _ = m.handleKeyPress(msg)
+	m = newModel.(Model)
+	if m.searchQuery != "qua" {
+		t.Errorf("Expected search query %q, got %q", "qua", m.searchQuery)
+	}
+}
+
+// TestHandleKeyPress_DigitCopiesNthService tests digit keys copy the Nth
+// filtered service, ignore out-of-range digits and type into searches
+func TestHandleKeyPress_DigitCopiesNthService(t *testing.T) {
+	store, err := storage.Create(filepath.Join(t.TempDir(), "secrets.enc"), "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, service := range []storage.Service{
+		{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		{Name: "AWS", Secret: "GEZDGNBVGY3TQOJQ", CreatedAt: time.Now()},
+		{Name: "GitLab", Secret: "MFRGGZDFMZTWQ2LK", CreatedAt: time.Now()},
+	} {
+		if err := store.AddService(service); err != nil {
+			t.Fatalf("AddService() error = %v", err)
+		}
+	}
+
+	var copied []string
+	oldCopy := copyToClipboard
+	copyToClipboard = func(code string) error {
+		copied = append(copied, code)
+		return nil
+	}
+	defer func() { copyToClipboard = oldCopy }()
+
+	model := NewModel(store)
+	model.generateAllCodes()
+	model.searchQuery = "git"
+	model.filterServices()
+
+	newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'2'}})
+	model = newModel.(Model)
+	if len(copied) != 1 || copied[0] != model.totpCodes["GitLab"] {
+		t.Fatalf("Copied %v, want GitLab's code %s", copied, model.totpCodes["GitLab"])
+	}
+	if service, _ := model.selectedService(); service.Name != "GitLab" {
+		t.Errorf("Cursor on %q, want GitLab", service.Name)
+	}
+
+	// '9' on a short list is a no-op
+	model.searchQuery = ""
+	model.filterServices()
+	newModel, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'9'}})
+	model = newModel.(Model)
+	if len(copied) != 1 || model.cursor != 0 {
+		t.Errorf("'9' on 3 items: copied %d codes, cursor %d, want 1 and 0", len(copied), model.cursor)
+	}
+
+	// In search mode digits are query input
+	model.searchMode = true
+	newModel, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'1'}})
+	model = newModel.(Model)
+	if len(copied) != 1 || model.searchQuery != "1" {
+		t.Errorf("Search mode: copied %d codes, query %q, want 1 and \"1\"", len(copied), model.searchQuery)
+	}
+}
+
+// TestHandleKeyPress_CopyPrevious tests 'p' copies the code from one period
+// earlier (honouring the service's period) with a distinct status
+func TestHandleKeyPress_CopyPrevious(t *testing.T) {
+	store, err := storage.Create(filepath.Join(t.TempDir(), "secrets.enc"), "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, service := range []storage.Service{
+		{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+		{Name: "Slow", Secret: "GEZDGNBVGY3TQOJQ", Period: 60, CreatedAt: time.Now()},
+	} {
+		if err := store.AddService(service); err != nil {
+			t.Fatalf("AddService() error = %v", err)
+		}
+	}
+
+	var copied string
+	oldCopy := copyToClipboard
+	copyToClipboard = func(code string) error {
+		copied = code
+		return nil
+	}
+	defer func() { copyToClipboard = oldCopy }()
+
+	at := time.Unix(1700000015, 0)
+	model := NewModel(store)
+	model.now = func() time.Time { return at }
+
+	for i, period := range []time.Duration{30 * time.Second, 60 * time.Second} {
+		model.cursor = i
+		newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+		model = newModel.(Model)
+
+		service := store.Services[i]
+		want, err := service.Code(at.Add(-period))
+		if err != nil {
+			t.Fatalf("Code() error = %v", err)
+		}
+		if copied != want {
+			t.Errorf("%s: copied %q, want previous code %q", service.Name, copied, want)
+		}
+		if !containsString(model.copyStatus, "PREVIOUS") {
+			t.Errorf("%s: status %q should say the previous code was copied", service.Name, model.copyStatus)
+		}
+		if store.Services[i].UseCount != 1 {
+			t.Errorf("%s: UseCount = %d, want 1", service.Name, store.Services[i].UseCount)
+		}
+	}
+}
+
+// TestCopySelected_ClipClear tests copies schedule a clear with the
+// configured seconds, and that the default (0) never clears
+func TestCopySelected_ClipClear(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	oldCopy, oldClear := copyToClipboard, clearClipboard
+	defer func() { copyToClipboard, clearClipboard = oldCopy, oldClear }()
+	copyToClipboard = func(string) error { return nil }
+
+	for _, seconds := range []int{0, 30} {
+		var gotCode string
+		gotSeconds := -1
+		clearClipboard = func(code string, n int) *clipboard.ScheduledClear {
+			gotCode, gotSeconds = code, n
+			return clipboard.ClearAfter(code, 0)
+		}
+
+		model := NewModel(store).WithClipClear(seconds)
+		model.generateAllCodes()
+		newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeySpace})
+		m := newModel.(Model)
+
+		if gotSeconds != seconds || gotCode != m.totpCodes["GitHub"] {
+			t.Errorf("WithClipClear(%d): scheduled (%q, %d), want (%q, %d)", seconds, gotCode, gotSeconds, m.totpCodes["GitHub"], seconds)
+		}
+		if m.pendingClear == nil {
+			t.Errorf("WithClipClear(%d): pending clear not tracked", seconds)
+		}
+	}
+}
+
+// TestQuit_FlushesPendingClear tests every quit key clears the copied code
+// right away instead of dropping the scheduled clear
+func TestQuit_FlushesPendingClear(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version:  1,
+			Services: []storage.Service{{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP"}},
+		},
+	}
+
+	oldCopy, oldClear, oldFlush := copyToClipboard, clearClipboard, flushClipboard
+	defer func() { copyToClipboard, clearClipboard, flushClipboard = oldCopy, oldClear, oldFlush }()
+	copyToClipboard = func(string) error { return nil }
+	clearClipboard = func(code string, _ int) *clipboard.ScheduledClear {
+		return clipboard.ClearAfter(code, 0)
+	}
+
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune{'q'}},
+		{Type: tea.KeyEsc},
+		{Type: tea.KeyCtrlC},
+	} {
+		var flushed *clipboard.ScheduledClear
+		flushClipboard = func(c *clipboard.ScheduledClear) { flushed = c }
+
+		model := NewModel(store).WithClipClear(30)
+		model.generateAllCodes()
+		newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeySpace})
+		m := newModel.(Model)
+
+		_, cmd := m.handleKeyPress(key)
+		if cmd == nil {
+			t.Fatalf("%q should quit", key.String())
+		}
+		if flushed == nil || flushed != m.pendingClear {
+			t.Errorf("%q quit without flushing the pending clear", key.String())
+		}
+	}
+
+	// Nothing was copied, so there is nothing to flush
+	flushClipboard = func(*clipboard.ScheduledClear) { t.Error("Flushed without a pending clear") }
+	NewModel(store).handleKeyPress(tea.KeyMsg{Type: tea.KeyCtrlC})
+}
+
+// TestHandleKeyPress_HelpOverlay tests '?' opens the keybinding overlay and
+// any key returns to the list
+func TestHandleKeyPress_HelpOverlay(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	model := NewModel(store)
+	model.generateAllCodes()
+
+	newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	model = newModel.(Model)
+	if !model.showHelp {
+		t.Fatal("'?' should open the help overlay")
+	}
+
+	view := model.View()
+	for _, want := range []string{"Keyboard shortcuts", "Navigation", "copy the selected code", "copy the previous period's code", "start searching", "quit"} {
+		if !containsString(view, want) {
+			t.Errorf("Help overlay missing %q", want)
+		}
+	}
+	if containsString(view, "GitHub") {
+		t.Error("Help overlay should replace the service list")
+	}
+
+	// Any key closes it without acting on the key
+	newModel, cmd := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	model = newModel.(Model)
+	if model.showHelp || cmd != nil {
+		t.Fatalf("Key should only close the overlay: showHelp = %v, cmd = %v", model.showHelp, cmd)
+	}
+	if !containsString(model.View(), "GitHub") {
+		t.Error("Closing the overlay should return to the list")
+	}
+
+	// '?' in search mode is query input
+	model.searchMode = true
+	newModel, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	model = newModel.(Model)
+	if model.showHelp || model.searchQuery != "?" {
+		t.Errorf("Search mode: showHelp = %v, query %q; want false and \"?\"", model.showHelp, model.searchQuery)
+	}
+}
+
+// TestHandleKeyPress_HelpOverlayEmpty tests '?' opens the help overlay when
+// no services exist yet
+func TestHandleKeyPress_HelpOverlayEmpty(t *testing.T) {
+	store := &storage.Store{Storage: &storage.Storage{Version: 1}}
+	model := NewModel(store)
+
+	if !containsString(model.View(), "?: help") {
+		t.Error("Empty state should mention the help key")
+	}
+
+	newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	model = newModel.(Model)
+	view := model.View()
+	if !containsString(view, "Keyboard shortcuts") || containsString(view, "No TOTP services configured yet") {
+		t.Errorf("Help overlay should replace the empty state, got:\n%s", view)
+	}
+
+	newModel, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	model = newModel.(Model)
+	if !containsString(model.View(), "No TOTP services configured yet") {
+		t.Error("Closing the overlay should return to the empty state")
+	}
+}
+
+// TestHandleKeyPress_TogglePin tests 'f' pins the selected service to the top,
+// keeps the cursor on it, and persists the flag
+func TestHandleKeyPress_TogglePin(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.enc")
+	store, err := storage.Create(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, name := range []string{"AWS", "GitHub", "Slack"} {
+		if err := store.AddService(storage.Service{Name: name, Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("AddService() error = %v", err)
+		}
+	}
+
+	model := NewModel(store)
+	model.height = 40
+	model.cursor = 2
+
+	newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	model = newModel.(Model)
+
+	var order []string
+	for _, index := range model.filteredIndices {
+		order = append(order, model.services[index].Name)
+	}
+	if strings.Join(order, ",") != "Slack,AWS,GitHub" {
+		t.Errorf("order = %v, want Slack first", order)
+	}
+	if model.cursor != 0 {
+		t.Errorf("cursor = %d, want 0 (following the pinned service)", model.cursor)
+	}
+	if !containsString(model.View(), pinMarker+" Slack") {
+		t.Error("Pinned row should show the pin marker")
+	}
+
+	loaded, err := storage.Load(path, "test-passphrase")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if service, _ := loaded.GetServiceCopy("Slack"); !service.Pinned {
+		t.Error("Pinned flag should be saved")
+	}
+
+	// A reopened model lists the pinned service first
+	if reopened := NewModel(loaded); reopened.services[reopened.filteredIndices[0]].Name != "Slack" {
+		t.Error("NewModel should list pinned services first")
+	}
+
+	newModel, _ = model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	model = newModel.(Model)
+	if model.services[model.filteredIndices[model.cursor]].Pinned {
+		t.Error("Second 'f' should unpin the service")
+	}
+}
+
+// TestHandleKeyPress_TogglePinSaveFailure tests a failed save reports the
+// error in the status line
+func TestHandleKeyPress_TogglePinSaveFailure(t *testing.T) {
+	store := &storage.Store{Storage: &storage.Storage{Version: 1, Services: []storage.Service{
+		{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP"},
+		{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP"},
+	}}}
+
+	oldSave := saveStore
+	saveStore = func(*storage.Store) error { return errors.New("disk full") }
+	defer func() { saveStore = oldSave }()
+
+	model := NewModel(store)
+	model.height = 40
+	model.cursor = 1
+
+	newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	model = newModel.(Model)
+
+	if model.copyStatus != "⚠ Pin not saved: disk full" {
+		t.Errorf("copyStatus = %q, want %q", model.copyStatus, "⚠ Pin not saved: disk full")
+	}
+}
+
+// TestHandleKeyPress_CycleMatches tests 'n' wraps from the last filtered
+// result to the first and 'N' from the first to the last
+func TestHandleKeyPress_CycleMatches(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub Work", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+				{Name: "AWS", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+				{Name: "GitHub Personal", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+				{Name: "GitLab", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	model := NewModel(store)
+	model.height = 40
+	model.searchQuery = "git"
+	model.filterServices()
+	if len(model.filteredIndices) != 3 {
+		t.Fatalf("Expected 3 matches, got %d", len(model.filteredIndices))
+	}
+
+	press := func(key rune) {
+		newModel, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		model = newModel.(Model)
+	}
+
+	press('n')
+	press('n')
+	if model.cursor != 2 {
+		t.Fatalf("cursor = %d after two 'n', want 2", model.cursor)
+	}
+	press('n')
+	if model.cursor != 0 {
+		t.Errorf("'n' at the last match: cursor = %d, want 0", model.cursor)
+	}
+	press('N')
+	if model.cursor != 2 {
+		t.Errorf("'N' at the first match: cursor = %d, want 2", model.cursor)
+	}
+	if model.searchQuery != "git" {
+		t.Errorf("searchQuery = %q, want the filter kept", model.searchQuery)
+	}
+}
+
+// TestCycleMatch_ScrollsViewport tests wrapping moves the viewport with the cursor
+func TestCycleMatch_ScrollsViewport(t *testing.T) {
+	services := make([]storage.Service, 10)
+	for i := range services {
+		services[i] = storage.Service{Name: string(rune('A' + i)), Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}
+	}
+	model := NewModel(&storage.Store{Storage: &storage.Storage{Version: 1, Services: services}})
+	model.height = 19 // three visible rows with the footer
+
+	model.cycleMatch(-1)
+	if model.cursor != 9 || model.viewportOffset != 7 {
+		t.Errorf("after wrap back: cursor %d offset %d, want 9 and 7", model.cursor, model.viewportOffset)
+	}
+	model.cycleMatch(1)
+	if model.cursor != 0 || model.viewportOffset != 0 {
+		t.Errorf("after wrap forward: cursor %d offset %d, want 0 and 0", model.cursor, model.viewportOffset)
+	}
+}
+
+// TestMaxVisibleItems_MatchesView tests the cursor scrolls by the rows the
+// view actually shows, footer included
+func TestMaxVisibleItems_MatchesView(t *testing.T) {
+	services := make([]storage.Service, 8)
+	for i := range services {
+		services[i] = storage.Service{Name: "Service" + string(rune('A'+i)), Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()}
+	}
+	model := NewModel(&storage.Store{Storage: &storage.Storage{Version: 1, Services: services}})
+
+	tests := []struct {
+		height int
+		want   int
+	}{
+		{24, 4}, // footer shown: (24 - 10) / 3
+		{15, 2}, // no footer: (15 - 9) / 3
+		{5, 1},
+	}
+	for _, tt := range tests {
+		model.height = tt.height
+		if got := model.maxVisibleItems(); got != tt.want {
+			t.Errorf("maxVisibleItems() at height %d = %d, want %d", tt.height, got, tt.want)
+		}
+	}
+
+	// At height 24 the fifth row must scroll the first out of view
+	model.height = 24
+	model.cursor, model.viewportOffset = 0, 0
+	for i := 0; i < 4; i++ {
+		model.cycleMatch(1)
+	}
+	if model.viewportOffset != 1 {
+		t.Errorf("viewportOffset = %d, want 1", model.viewportOffset)
+	}
+	if !containsString(model.View(), "ServiceE") {
+		t.Error("The selected row should be rendered")
+	}
+}
+
+// TestExpiresAt tests the expiry is now rounded up to the next period boundary
+func TestExpiresAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		now    time.Time
+		period int
+		want   time.Time
+	}{
+		{"Mid period", time.Date(2024, 1, 1, 14, 30, 12, 0, time.UTC), 30, time.Date(2024, 1, 1, 14, 30, 30, 0, time.UTC)},
+		{"Just before boundary", time.Date(2024, 1, 1, 14, 30, 29, 999, time.UTC), 30, time.Date(2024, 1, 1, 14, 30, 30, 0, time.UTC)},
+		{"On boundary", time.Date(2024, 1, 1, 14, 30, 30, 0, time.UTC), 30, time.Date(2024, 1, 1, 14, 31, 0, 0, time.UTC)},
+		{"60s period", time.Date(2024, 1, 1, 14, 30, 12, 0, time.UTC), 60, time.Date(2024, 1, 1, 14, 31, 0, 0, time.UTC)},
+		{"Default period", time.Date(2024, 1, 1, 14, 30, 12, 0, time.UTC), 0, time.Date(2024, 1, 1, 14, 30, 30, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expiresAt(tt.now, tt.period); !got.Equal(tt.want) {
+				t.Errorf("expiresAt(%v, %d) = %v, want %v", tt.now, tt.period, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHandleKeyPress_ToggleExpiresAt tests 't' swaps the countdown for the
+// selected service's expiry clock time
+func TestHandleKeyPress_ToggleExpiresAt(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "Slow", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now(), Period: 60},
+			},
+		},
+	}
+
+	model := NewModel(store)
+	model.now = func() time.Time { return time.Date(2024, 1, 1, 14, 30, 12, 0, time.Local) }
+
+	if view := model.View(); !strings.Contains(view, "Refreshing in") {
+		t.Fatalf("View() should show the countdown by default, got:\n%s", view)
+	}
+
+	updated, _ := model.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	m := updated.(Model)
+	if view := m.View(); !strings.Contains(view, "Expires 14:31:00") {
+		t.Errorf("View() should show the 60s service's expiry 14:31:00, got:\n%s", view)
+	}
+
+	updated, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if updated.(Model).showExpiresAt {
+		t.Error("second 't' should switch back to the countdown")
+	}
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanprakash21/totp-manager-go/internal/otpauth"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// migrationImport is the confirmation list shown when a Google
+// Authenticator otpauth-migration:// URI is entered in the add form. All
+// decoded accounts are imported together once confirmed.
+type migrationImport struct {
+	services []storage.Service // decoded TOTP accounts, in payload order
+	skipped  []string          // accounts the parser left out, with the reason
+}
+
+// openMigrationImport decodes uri into the form's import confirmation. An
+// undecodable payload, or one without a usable account, becomes the form
+// error instead.
+func (m *Model) openMigrationImport(uri string) {
+	keys, skipped, err := otpauth.ParseMigration(uri)
+	if err != nil {
+		m.form.err = err.Error()
+		return
+	}
+	if len(keys) == 0 {
+		m.form.err = "no importable accounts in the migration link"
+		return
+	}
+
+	migration := &migrationImport{skipped: skipped}
+	for _, key := range keys {
+		migration.services = append(migration.services, key.Service(m.now()))
+	}
+	m.form.migration = migration
+	m.form.err = ""
+}
+
+// handleMigrationKey handles keys while the import confirmation is open:
+// enter or 'y' imports, esc goes back to the form
+func (m Model) handleMigrationKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc":
+		m.form.migration = nil
+		m.form.err = ""
+	case "enter", "y", "Y":
+		m.importMigration()
+	}
+	return m, nil
+}
+
+// importMigration imports the confirmed accounts through Storage.Import,
+// skipping names that already exist, and saves once. A failed save restores
+// the snapshot and keeps the confirmation open with the error.
+func (m *Model) importMigration() {
+	form := m.form
+	selected, _ := m.selectedService()
+	previous := append([]storage.Service(nil), m.store.Services...)
+
+	var added []string
+	skipped := len(form.migration.skipped)
+	for _, service := range form.migration.services {
+		if result := m.store.Import(service, storage.ConflictSkip); result.Outcome == storage.ImportAdded {
+			added = append(added, result.Name)
+		} else {
+			skipped++
+		}
+	}
+
+	m.copyStatusTime = m.now()
+	if len(added) == 0 {
+		m.form = nil
+		m.copyStatus = fmt.Sprintf("⚠ Nothing imported (%d skipped)", skipped)
+		return
+	}
+
+	if err := saveStore(m.store); err != nil {
+		m.store.Services = previous
+		m.reloadServices(selected.Name)
+		form.err = "not saved: " + err.Error()
+		return
+	}
+
+	m.form = nil
+	m.copyStatus = fmt.Sprintf("✓ Imported %d services", len(added))
+	if skipped > 0 {
+		m.copyStatus += fmt.Sprintf(" (%d skipped)", skipped)
+	}
+	m.reloadServices(added[0])
+}
+
+// renderMigration renders the import confirmation: each decoded account,
+// flagged when its name is taken, then the accounts the parser skipped
+func (m Model) renderMigration() string {
+	migration := m.form.migration
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Import %d accounts from Google Authenticator?\n", len(migration.services)))
+
+	for _, service := range migration.services {
+		line := service.Name
+		if service.Identifier != "" {
+			line += " (" + service.Identifier + ")"
+		}
+		if _, err := m.store.GetServiceCopy(service.Name); err == nil {
+			line += " - already exists, skipped"
+		}
+		b.WriteString("\n  " + line)
+	}
+	for _, reason := range migration.skipped {
+		b.WriteString("\n  ⚠ skipped " + reason)
+	}
+
+	return borderStyle.Render(b.String())
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
+)
+
+// testMigrationURI holds Slack:alice@example.com, Dropbox:bob and
+// GitHub:octocat (TOTP) and an HOTP account named Counter
+const testMigrationURI = "otpauth-migration://offline?data=CiwKCkhlbGxvId6tvu8SEWFsaWNlQGV4YW1wbGUuY29tGgVTbGFjayABKAEwAgogCgpIZWxsbyHerb7vEgNib2IaB0Ryb3Bib3ggASgBMAIKIwoKSGVsbG8h3q2%2B7xIHb2N0b2NhdBoGR2l0SHViIAEoATACChsKCkhlbGxvId6tvu8SB0NvdW50ZXIgASgBMAEQARgB"
+
+// pasteMigration opens the add form and pastes testMigrationURI into it
+func pasteMigration(t *testing.T, m Model) Model {
+	t.Helper()
+	m = press(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	return press(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(testMigrationURI + "\n"), Paste: true})
+}
+
+// TestMigrationImport tests a pasted multi-account migration link lists its
+// accounts and imports the new ones on confirm
+func TestMigrationImport(t *testing.T) {
+	model, path := setupFormModel(t)
+
+	model = pasteMigration(t, model)
+	if model.form == nil || model.form.migration == nil {
+		t.Fatalf("Pasting a migration link should open the confirmation, got %+v", model.form)
+	}
+	if model.form.values[fieldName] != "" {
+		t.Errorf("Name = %q, the link should not be typed into the field", model.form.values[fieldName])
+	}
+	view := model.View()
+	for _, want := range []string{"Import 3 accounts", "Slack (alice@example.com)", "Dropbox (bob)", "GitHub (octocat) - already exists, skipped", `skipped "Counter"`} {
+		if !containsString(view, want) {
+			t.Errorf("View() should list %q, got:\n%s", want, view)
+		}
+	}
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+	if model.form != nil {
+		t.Fatalf("The confirmation should close after importing, error = %q", model.form.err)
+	}
+	if model.copyStatus != "✓ Imported 2 services (2 skipped)" {
+		t.Errorf("copyStatus = %q, want %q", model.copyStatus, "✓ Imported 2 services (2 skipped)")
+	}
+	if service, ok := model.selectedService(); !ok || service.Name != "Slack" {
+		t.Errorf("Selected service = %q, want the first imported service", service.Name)
+	}
+	if model.totpCodes["Slack"] == "" || model.totpCodes["Dropbox"] == "" {
+		t.Error("Imported services should have codes")
+	}
+
+	store := loadFormStore(t, path)
+	if len(store.Services) != 4 {
+		t.Fatalf("Saved %d services, want 4", len(store.Services))
+	}
+	slack, err := store.GetServiceCopy("Slack")
+	if err != nil {
+		t.Fatalf("GetServiceCopy() error = %v", err)
+	}
+	if slack.Identifier != "alice@example.com" || slack.Secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("Slack = %+v, want identifier alice@example.com and secret JBSWY3DPEHPK3PXP", slack)
+	}
+	if github, _ := store.GetServiceCopy("GitHub"); github.Identifier != "octocat" {
+		t.Errorf("GitHub = %+v, the existing service should be kept", github)
+	}
+}
+
+// TestMigrationImport_Cancel tests esc returns to the form without importing
+func TestMigrationImport_Cancel(t *testing.T) {
+	model, path := setupFormModel(t)
+
+	model = pasteMigration(t, model)
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if model.form == nil || model.form.migration == nil {
+		t.Fatal("Other keys should leave the confirmation open")
+	}
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEsc})
+	if model.form == nil || model.form.migration != nil {
+		t.Fatal("Esc should go back to the add form")
+	}
+	if len(model.services) != 2 || len(loadFormStore(t, path).Services) != 2 {
+		t.Error("Cancelling should import nothing")
+	}
+}
+
+// TestMigrationImport_Submitted tests a link typed into a field (no
+// bracketed paste) opens the confirmation on enter, and an invalid link
+// shows the parse error
+func TestMigrationImport_Submitted(t *testing.T) {
+	model, _ := setupFormModel(t)
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyUp})
+	model = typeText(t, model, "otpauth-migration://offline?data=%%%")
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+	if model.form == nil || model.form.migration != nil || model.form.err == "" {
+		t.Fatalf("An invalid link should keep the form open with an error, got %+v", model.form)
+	}
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyCtrlU})
+	model = typeText(t, model, testMigrationURI)
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyEnter})
+	if model.form == nil || model.form.migration == nil || len(model.form.migration.services) != 3 {
+		t.Fatalf("Submitting a migration link should open the confirmation, got %+v", model.form)
+	}
+}
+
+// TestMigrationImport_SaveFailure tests a failed save imports nothing and
+// keeps the confirmation open with the error
+func TestMigrationImport_SaveFailure(t *testing.T) {
+	model, _ := setupFormModel(t)
+
+	oldSave := saveStore
+	saveStore = func(*storage.Store) error { return errors.New("disk full") }
+	defer func() { saveStore = oldSave }()
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyDown})
+	model = pasteMigration(t, model)
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+
+	if model.form == nil || model.form.migration == nil || model.form.err != "not saved: disk full" {
+		t.Fatalf("The confirmation should stay open with the save error, got %+v", model.form)
+	}
+	if len(model.store.Services) != 2 || len(model.filteredIndices) != 2 {
+		t.Errorf("Listed %d of %d services, want the 2 originals", len(model.filteredIndices), len(model.store.Services))
+	}
+	if service, ok := model.selectedService(); !ok || service.Name != "AWS" {
+		t.Errorf("Selected service = %q, want AWS", service.Name)
+	}
+}
+
+// TestMigrationImport_EditIgnoresLinks tests pasting a link while editing
+// goes into the field rather than importing
+func TestMigrationImport_EditIgnoresLinks(t *testing.T) {
+	model, _ := setupFormModel(t)
+
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyCtrlU})
+	model = press(t, model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(testMigrationURI), Paste: true})
+
+	if model.form == nil || model.form.migration != nil || model.form.values[fieldName] != testMigrationURI {
+		t.Errorf("Editing should take the link as text, got %+v", model.form)
+	}
+}
@@ -68,12 +68,23 @@ var (
 				Align(lipgloss.Right).
 				Width(10)
 
+	// Code that failed to generate
+	errorCodeStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(colorWarning).
+			Align(lipgloss.Right).
+			Width(10)
+
 	// Global countdown timer style
 	timerStyle = lipgloss.NewStyle().
 			Foreground(colorWarning).
 			Bold(true).
 			PaddingLeft(2)
 
+	// Countdown progress bar style
+	progressBarStyle = lipgloss.NewStyle().
+				Foreground(colorWarning)
+
 	// Help text style
 	helpStyle = lipgloss.NewStyle().
 			Foreground(colorMuted).
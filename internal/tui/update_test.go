@@ -1,10 +1,13 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/pavanprakash21/totp-manager-go/internal/storage"
 )
 
@@ -55,6 +58,44 @@ func TestUpdate_TickMsg(t *testing.T) {
 	}
 }
 
+// TestUpdate_TickRefreshesOffPeriodCodes tests a service with a 15s period
+// gets a new code when its own period rolls over between 30s refreshes
+func TestUpdate_TickRefreshesOffPeriodCodes(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+				{Name: "Short", Secret: "JBSWY3DPEHPK3PXP", Period: 15, CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	// 1699999980 is a multiple of 30; the tick lands in the second 15s period
+	start := time.Unix(1699999980+2, 0)
+	tick := time.Unix(1699999980+16, 0)
+
+	model := NewModel(store)
+	model.totpCodes = map[string]string{"GitHub": "github-stale", "Short": "short-stale"}
+	model.lastUpdate = start
+	model.remainingTime = 14
+
+	newModel, _ := model.Update(tickMsg(tick))
+	m := newModel.(Model)
+
+	short := store.Services[1]
+	want, err := short.Code(tick)
+	if err != nil {
+		t.Fatalf("Code() error = %v", err)
+	}
+	if m.totpCodes["Short"] != want {
+		t.Errorf("Short code = %q, want %q for its new period", m.totpCodes["Short"], want)
+	}
+	if m.totpCodes["GitHub"] != "github-stale" {
+		t.Errorf("GitHub code = %q, want it left for the 30s refresh", m.totpCodes["GitHub"])
+	}
+}
+
 // TestUpdate_RefreshMsg tests Update with refresh message
 func TestUpdate_RefreshMsg(t *testing.T) {
 	store := &storage.Store{
@@ -107,6 +148,181 @@ func TestUpdate_WindowSizeMsg(t *testing.T) {
 	}
 }
 
+// TestUpdate_FrameMsg tests sub-second frames move the bar without regenerating codes
+func TestUpdate_FrameMsg(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+			Settings: storage.Settings{BarRefreshMillis: 250},
+		},
+	}
+
+	model := NewModel(store)
+	if model.frameInterval != 250*time.Millisecond {
+		t.Fatalf("Expected 250ms frame interval, got %v", model.frameInterval)
+	}
+
+	model.generateAllCodes()
+	lastUpdate := model.lastUpdate
+	code := model.totpCodes["GitHub"]
+
+	// 10.000s, 10.250s and 10.500s into a period
+	start := time.Unix(1699999990, 0)
+	var fractions []float64
+	var m tea.Model = model
+	for i := 0; i < 3; i++ {
+		var cmd tea.Cmd
+		m, cmd = m.Update(frameMsg(start.Add(time.Duration(i) * 250 * time.Millisecond)))
+		if cmd == nil {
+			t.Fatal("frameMsg should schedule the next frame")
+		}
+		fractions = append(fractions, m.(Model).barFraction)
+	}
+
+	for i := 1; i < len(fractions); i++ {
+		if fractions[i] >= fractions[i-1] {
+			t.Errorf("Bar fraction should decrease each frame, got %v", fractions)
+		}
+	}
+
+	want := 20.0 / 30.0
+	if diff := fractions[0] - want; diff > 0.001 || diff < -0.001 {
+		t.Errorf("Fraction 10s into period = %f, want %f", fractions[0], want)
+	}
+
+	final := m.(Model)
+	if !final.lastUpdate.Equal(lastUpdate) {
+		t.Error("frameMsg must not regenerate codes")
+	}
+	if final.totpCodes["GitHub"] != code {
+		t.Error("frameMsg must not change codes")
+	}
+}
+
+// TestUpdate_BlurStopsTicks tests that ticks and frames are not rescheduled
+// while the terminal is unfocused
+func TestUpdate_BlurStopsTicks(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	model := NewModel(store)
+	model.frameInterval = 250 * time.Millisecond
+
+	updated, _ := model.Update(tea.BlurMsg{})
+	m := updated.(Model)
+	if !m.paused {
+		t.Fatal("BlurMsg should pause the model")
+	}
+
+	updated, cmd := m.Update(tickMsg(time.Now()))
+	if cmd != nil {
+		t.Error("tickMsg while paused must not schedule another tick")
+	}
+	updated, cmd = updated.(Model).Update(frameMsg(time.Now()))
+	if cmd != nil {
+		t.Error("frameMsg while paused must not schedule another frame")
+	}
+	m = updated.(Model)
+	if !m.tickIdle || !m.frameIdle {
+		t.Error("both loops should be marked idle after being dropped")
+	}
+}
+
+// TestUpdate_FocusResumesAndRegenerates tests that regaining focus restarts
+// the stopped loops and refreshes codes immediately
+func TestUpdate_FocusResumesAndRegenerates(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "GitHub", Secret: "JBSWY3DPEHPK3PXP", CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	model := NewModel(store)
+	updated, _ := model.Update(tea.BlurMsg{})
+	updated, _ = updated.(Model).Update(tickMsg(time.Now()))
+	m := updated.(Model)
+	m.totpCodes["GitHub"] = "stale"
+	m.remainingTime = 0
+
+	updated, cmd := m.Update(tea.FocusMsg{})
+	m = updated.(Model)
+	if m.paused || m.tickIdle {
+		t.Error("FocusMsg should unpause and restart the tick loop")
+	}
+	if cmd == nil {
+		t.Error("FocusMsg should schedule a tick")
+	}
+	if code := m.totpCodes["GitHub"]; code == "stale" || len(code) != 6 {
+		t.Errorf("FocusMsg should regenerate codes, got %q", code)
+	}
+	if m.remainingTime < 1 || m.remainingTime > totpPeriod {
+		t.Errorf("remainingTime = %d, want 1..%d", m.remainingTime, totpPeriod)
+	}
+}
+
+// TestUpdate_FocusWithTickInFlight tests that resuming before the pending
+// tick arrives doesn't start a second tick loop
+func TestUpdate_FocusWithTickInFlight(t *testing.T) {
+	store := &storage.Store{Storage: &storage.Storage{Version: 1}}
+
+	model := NewModel(store)
+	updated, _ := model.Update(tea.BlurMsg{})
+	_, cmd := updated.(Model).Update(tea.FocusMsg{})
+	if cmd != nil {
+		t.Error("FocusMsg must not start a tick while the previous one is still pending")
+	}
+}
+
+// TestFrameInterval tests clamping and disabling of the frame interval
+func TestFrameInterval(t *testing.T) {
+	tests := []struct {
+		millis int
+		want   time.Duration
+	}{
+		{0, 0},
+		{1000, 0},
+		{250, 250 * time.Millisecond},
+		{10, minFrameInterval},
+	}
+
+	for _, tt := range tests {
+		if got := frameInterval(tt.millis); got != tt.want {
+			t.Errorf("frameInterval(%d) = %v, want %v", tt.millis, got, tt.want)
+		}
+	}
+}
+
+// TestRenderProgressBar tests bar fill levels
+func TestRenderProgressBar(t *testing.T) {
+	tests := []struct {
+		fraction float64
+		want     string
+	}{
+		{1, "██████████"},
+		{0.5, "█████░░░░░"},
+		{0, "░░░░░░░░░░"},
+		{-1, "░░░░░░░░░░"},
+	}
+
+	for _, tt := range tests {
+		if got := renderProgressBar(tt.fraction, 10); got != tt.want {
+			t.Errorf("renderProgressBar(%v) = %q, want %q", tt.fraction, got, tt.want)
+		}
+	}
+}
+
 // TestUpdate_KeyMsg tests Update with key message
 func TestUpdate_KeyMsg(t *testing.T) {
 	store := &storage.Store{
@@ -181,8 +397,8 @@ func TestUpdate_CopyStatusNotTimeout(t *testing.T) {
 	}
 }
 
-// TestRenderServiceLine tests service line rendering
-func TestRenderServiceLine(t *testing.T) {
+// TestRenderServiceRow tests service line rendering
+func TestRenderServiceRow(t *testing.T) {
 	store := &storage.Store{
 		Storage: &storage.Storage{
 			Version:  1,
@@ -193,15 +409,15 @@ func TestRenderServiceLine(t *testing.T) {
 	model := NewModel(store)
 
 	// Test normal line
-	line := model.renderServiceLine("GitHub", "", "123456", false)
+	line := model.renderServiceRow("GitHub", "", "123456", false, false)
 	if line == "" {
-		t.Error("renderServiceLine should return non-empty string")
+		t.Error("renderServiceRow should return non-empty string")
 	}
 
 	// Test selected line
-	selectedLine := model.renderServiceLine("GitHub", "", "123456", true)
+	selectedLine := model.renderServiceRow("GitHub", "", "123456", true, false)
 	if selectedLine == "" {
-		t.Error("renderServiceLine should return non-empty string for selected")
+		t.Error("renderServiceRow should return non-empty string for selected")
 	}
 
 	// Both should contain the service name and code
@@ -213,8 +429,8 @@ func TestRenderServiceLine(t *testing.T) {
 	}
 }
 
-// TestRenderServiceLine_WithIdentifier tests rendering with identifier
-func TestRenderServiceLine_WithIdentifier(t *testing.T) {
+// TestRenderServiceRow_WithIdentifier tests rendering with identifier
+func TestRenderServiceRow_WithIdentifier(t *testing.T) {
 	store := &storage.Store{
 		Storage: &storage.Storage{
 			Version:  1,
@@ -224,14 +440,39 @@ func TestRenderServiceLine_WithIdentifier(t *testing.T) {
 
 	model := NewModel(store)
 
-	line := model.renderServiceLine("GitHub", "user@example.com", "123456", false)
+	line := model.renderServiceRow("GitHub", "user@example.com", "123456", false, false)
 	if line == "" {
-		t.Error("renderServiceLine with identifier should return non-empty string")
+		t.Error("renderServiceRow with identifier should return non-empty string")
+	}
+}
+
+// TestRenderServiceRow_HideIdentifiers tests the setting controls whether
+// list rows include the identifier
+func TestRenderServiceRow_HideIdentifiers(t *testing.T) {
+	for _, hide := range []bool{false, true} {
+		store := &storage.Store{
+			Storage: &storage.Storage{
+				Version:  1,
+				Services: []storage.Service{},
+				Settings: storage.Settings{HideIdentifiers: hide},
+			},
+		}
+		model := NewModel(store)
+
+		for _, selected := range []bool{false, true} {
+			line := model.renderServiceRow("GitHub", "user@example.com", "123456", selected, false)
+			if shown := containsString(line, "user@example.com"); shown == hide {
+				t.Errorf("HideIdentifiers=%v selected=%v: identifier shown = %v", hide, selected, shown)
+			}
+			if !containsString(line, "GitHub") || !containsString(line, "123456") {
+				t.Errorf("HideIdentifiers=%v: row should keep name and code: %q", hide, line)
+			}
+		}
 	}
 }
 
-// TestRenderServiceLine_LongName tests truncation of long service names
-func TestRenderServiceLine_LongName(t *testing.T) {
+// TestRenderServiceRow_LongName tests truncation of long service names
+func TestRenderServiceRow_LongName(t *testing.T) {
 	store := &storage.Store{
 		Storage: &storage.Storage{
 			Version:  1,
@@ -242,10 +483,71 @@ func TestRenderServiceLine_LongName(t *testing.T) {
 	model := NewModel(store)
 
 	longName := "This is a very long service name that should be truncated because it exceeds the maximum allowed length"
-	line := model.renderServiceLine(longName, "", "123456", false)
+	line := model.renderServiceRow(longName, "", "123456", false, false)
 
 	if line == "" {
-		t.Error("renderServiceLine with long name should return non-empty string")
+		t.Error("renderServiceRow with long name should return non-empty string")
+	}
+}
+
+// TestRenderServiceRow_MultibyteNames tests long names with the pin marker
+// or wide characters are truncated to the same width as ASCII ones
+func TestRenderServiceRow_MultibyteNames(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version:  1,
+			Services: []storage.Service{},
+		},
+	}
+	model := NewModel(store)
+
+	want := lipgloss.Width(model.renderServiceRow(strings.Repeat("a", 40), strings.Repeat("b", 40), "123456", false, false))
+	for _, name := range []string{pinMarker + " " + strings.Repeat("é", 40), strings.Repeat("日本語", 10)} {
+		line := model.renderServiceRow(name, "ユーザー@example.com"+strings.Repeat("ü", 30), "123456", false, false)
+		if !utf8.ValidString(line) {
+			t.Errorf("Row for %q is not valid UTF-8", name)
+		}
+		if got := lipgloss.Width(line); got != want {
+			t.Errorf("Row for %q is %d cells wide, want %d", name, got, want)
+		}
+	}
+}
+
+// TestView_SteamPlaceholderAndCode tests Steam services render 5-char placeholders and codes aligned
+func TestView_SteamPlaceholderAndCode(t *testing.T) {
+	store := &storage.Store{
+		Storage: &storage.Storage{
+			Version: 1,
+			Services: []storage.Service{
+				{Name: "Steam", Secret: "JBSWY3DPEHPK3PXP", Type: storage.TypeSteam, CreatedAt: time.Now()},
+			},
+		},
+	}
+
+	model := NewModel(store)
+	model.height = 40
+
+	// Before codes are generated, the placeholder matches the Steam code length
+	view := model.View()
+	if !containsString(view, "-----") || containsString(view, "------") {
+		t.Error("Steam service should render a 5-character placeholder")
+	}
+
+	model.generateAllCodes()
+	code := model.totpCodes["Steam"]
+	if len(code) != 5 {
+		t.Fatalf("Expected 5-character Steam code, got %q", code)
+	}
+
+	// Steam and 6-digit rows must render at the same width (no misalignment)
+	steamLine := model.renderServiceRow("Steam", "", code, false, false)
+	totpLine := model.renderServiceRow("GitHub", "", "123456", false, false)
+	if !containsString(steamLine, code) {
+		t.Errorf("Steam line should contain code %q", code)
+	}
+	if lipgloss.Width(steamLine) != lipgloss.Width(totpLine) {
+		t.Errorf("Steam line width %d differs from TOTP line width %d",
+			lipgloss.Width(steamLine), lipgloss.Width(totpLine))
 	}
 }
 
@@ -2,9 +2,11 @@ package tui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pavanprakash21/totp-manager-go/internal/storage"
 )
 
 // View implements tea.Model interface
@@ -17,6 +19,30 @@ func (m Model) View() string {
 	b.WriteString(header)
 	b.WriteString("\n\n")
 
+	// Service form replaces the list while open
+	if m.form != nil {
+		b.WriteString(m.renderForm())
+		b.WriteString("\n")
+		if m.form.err != "" {
+			b.WriteString(warningStyle.Render("⚠ " + m.form.err))
+			b.WriteString("\n")
+		}
+		if m.form.migration != nil {
+			b.WriteString(helpStyle.Render("enter/y: import • esc: back to the form"))
+		} else {
+			b.WriteString(helpStyle.Render("tab/↓: next field • shift+tab/↑: previous • enter: next/save • esc: cancel"))
+		}
+		return b.String()
+	}
+
+	// Help overlay replaces the list (or the empty state) while open
+	if m.showHelp {
+		b.WriteString(renderHelpOverlay())
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("Press any key to close"))
+		return b.String()
+	}
+
 	// T052: Empty state view with instructions
 	if len(m.services) == 0 {
 		emptyMsg := emptyStateStyle.Render(
@@ -24,30 +50,31 @@ func (m Model) View() string {
 				"To add a service:\n" +
 				"  • Use CLI: totp add --name GitHub --secret YOUR_SECRET\n" +
 				"  • Optional: totp add --name GitHub --identifier user@example.com --secret YOUR_SECRET\n" +
-				"  • Or press 'a' to add via TUI (coming soon)\n",
+				"  • Or press 'a' to add one here\n",
 		)
 		b.WriteString(emptyMsg)
 		b.WriteString("\n\n")
-		b.WriteString(helpStyle.Render("Press 'q' to quit"))
+		b.WriteString(helpStyle.Render("a: add • ?: help • q: quit"))
+		return b.String()
+	}
+
+	// Details pane replaces the list while open
+	if service, ok := m.selectedService(); ok && m.showDetails {
+		b.WriteString(m.renderDetails(service))
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("r: reveal/hide recovery codes • esc/i: back • q: quit"))
 		return b.String()
 	}
 
 	// Global countdown timer at top
-	timerText := timerStyle.Render(fmt.Sprintf("⏱  Refreshing in %ds", m.remainingTime))
+	timerText := timerStyle.Render(m.timerLine())
 	b.WriteString(timerText)
+	b.WriteString("  ")
+	b.WriteString(progressBarStyle.Render(renderProgressBar(m.progressFraction(), progressBarWidth)))
 	b.WriteString("\n")
 
 	// Search mode indicator or filter status
-	if m.searchMode {
-		searchText := searchQueryStyle.Render(fmt.Sprintf("Search: %s_", m.searchQuery))
-		b.WriteString(searchText)
-		b.WriteString(fmt.Sprintf("  (%d results)", len(m.filteredIndices)))
-	} else if m.searchQuery != "" {
-		// Show active filter when not in search mode
-		filterText := searchQueryStyle.Render(fmt.Sprintf("Filter: %s", m.searchQuery))
-		b.WriteString(filterText)
-		b.WriteString(fmt.Sprintf("  (%d/%d services)", len(m.filteredIndices), len(m.services)))
-	}
+	b.WriteString(m.searchStatusLine())
 	b.WriteString("\n")
 
 	// Service list with boxed rows (filtered)
@@ -56,17 +83,9 @@ func (m Model) View() string {
 		b.WriteString(noResultsMsg)
 		b.WriteString("\n")
 	} else {
-		// Calculate how many items can fit on screen
-		// Each item takes 3 lines (top border, content, bottom border)
-		// Reserve space for header (4 lines), timer (2 lines), help (3 lines) = 9 lines
-		maxVisibleItems := (m.height - 9) / 3
-		if maxVisibleItems < 1 {
-			maxVisibleItems = 1
-		}
-
 		// Calculate viewport bounds
 		start := m.viewportOffset
-		end := start + maxVisibleItems
+		end := start + m.maxVisibleItems()
 		if end > len(m.filteredIndices) {
 			end = len(m.filteredIndices)
 		}
@@ -82,12 +101,25 @@ func (m Model) View() string {
 			serviceIdx := m.filteredIndices[i]
 			service := m.services[serviceIdx]
 			isSelected := i == m.cursor
+			failed := m.codeErrors[service.Name]
 			code := m.totpCodes[service.Name]
-			if code == "" {
-				code = "------"
+			switch {
+			case failed:
+				code = m.errorText
+			case code == "":
+				// Not generated yet
+				code = codePlaceholder(service.CodeLength())
 			}
 
-			line := m.renderServiceLine(service.Name, service.Identifier, code, isSelected)
+			name := service.Name
+			if m.nerdFonts {
+				name = serviceGlyph(name) + " " + name
+			}
+			if service.Pinned {
+				name = pinMarker + " " + name
+			}
+
+			line := m.renderServiceRow(name, service.Identifier, code, isSelected, failed)
 			b.WriteString(line)
 			b.WriteString("\n")
 		}
@@ -113,34 +145,292 @@ func (m Model) View() string {
 	// Help text (context-aware)
 	b.WriteString("\n")
 	var helpText string
-	if m.searchMode {
+	if service, ok := m.selectedService(); ok && m.confirmDelete {
+		helpText = warningStyle.Render(fmt.Sprintf("Delete %s? y: delete • any other key: cancel", service.Name))
+	} else if m.searchMode {
 		helpText = helpStyle.Render("j/k/↑/↓: navigate • space/enter: copy • backspace: delete • ctrl+u: clear • esc: done")
 	} else if m.searchQuery != "" {
 		// Filtered view (search done but not in search mode)
 		helpText = helpStyle.Render("/: search • ctrl+u: clear filter • j/k/↑/↓: navigate • space/enter: copy • q: quit")
 	} else {
-		helpText = helpStyle.Render("/: search • ↑/k: up • ↓/j: down • space/enter: copy • q: quit")
+		helpText = helpStyle.Render("/: search • ↑/k: up • ↓/j: down • space/enter: copy • a: add • i: details • ?: help • q: quit")
 	}
 	b.WriteString(helpText)
 
+	if m.showFooter() {
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(m.footerLine()))
+	}
+
 	return b.String()
 }
 
-// renderServiceLine renders a single service line with proper alignment
-func (m Model) renderServiceLine(name, identifier, code string, selected bool) string {
+// helpSection is a titled group of keybindings in the help overlay
+type helpSection struct {
+	title    string
+	bindings [][2]string // key, description
+}
+
+// helpSections lists every keybinding shown by the '?' overlay
+var helpSections = []helpSection{
+	{"Navigation", [][2]string{
+		{"↑/k, ↓/j", "move up / down"},
+		{"g/home, G/end", "jump to first / last"},
+	}},
+	{"Search", [][2]string{
+		{"/", "start searching (type to filter)"},
+		{"esc", "finish searching, keep the filter"},
+		{"ctrl+u", "clear the search filter"},
+		{"n, N", "next / previous match (wraps around)"},
+	}},
+	{"Copy", [][2]string{
+		{"space/enter", "copy the selected code"},
+		{"1-9", "copy the Nth visible code"},
+		{"p", "copy the previous period's code"},
+	}},
+	{"Edit", [][2]string{
+		{"a", "add a service, or import every account in a pasted otpauth-migration:// link"},
+		{"e", "edit the selected service's name, identifier or secret"},
+		{"d", "delete the selected service (asks to confirm)"},
+	}},
+	{"Other", [][2]string{
+		{"f", "pin / unpin (pinned services list first)"},
+		{"t", "show the expiry time instead of the countdown"},
+		{"i", "show details (r reveals recovery codes)"},
+		{"?", "show this help"},
+		{"q/esc, ctrl+c", "quit"},
+	}},
+}
+
+// pinMarker prefixes pinned service names in the list
+const pinMarker = "★"
+
+// helpKeyWidth aligns descriptions in the help overlay
+const helpKeyWidth = 16
+
+// renderHelpOverlay renders all keybindings as a bordered panel
+func renderHelpOverlay() string {
+	var b strings.Builder
+
+	b.WriteString("Keyboard shortcuts")
+	for _, section := range helpSections {
+		b.WriteString("\n\n" + section.title)
+		for _, binding := range section.bindings {
+			b.WriteString(fmt.Sprintf("\n  %-*s%s", helpKeyWidth, binding[0], binding[1]))
+		}
+	}
+
+	return borderStyle.Render(b.String())
+}
+
+// minFooterHeight is the smallest terminal height that still gets the footer;
+// below it every line goes to the service list
+const minFooterHeight = 16
+
+// showFooter reports whether the vault footer fits (height 0 means unknown)
+func (m Model) showFooter() bool {
+	return m.height == 0 || m.height >= minFooterHeight
+}
+
+// maxVisibleItems returns how many service rows fit on screen (at least
+// one). Each row takes 3 lines (top border, content, bottom border); the
+// header (4 lines), timer (2 lines) and help (3 lines) take 9 more, plus
+// one for the footer when shown. The view and all cursor scrolling share
+// it so the selected row never scrolls out of view.
+func (m Model) maxVisibleItems() int {
+	reserved := 9
+	if m.showFooter() {
+		reserved++
+	}
+	if items := (m.height - reserved) / 3; items > 1 {
+		return items
+	}
+	return 1
+}
+
+// footerLine renders the active vault and service counts, e.g.
+// "vault: work • 12 services • 3 shown" (the shown count only while filtered)
+func (m Model) footerLine() string {
+	vault := "-"
+	if m.store != nil && m.store.Path() != "" {
+		vault = strings.TrimSuffix(filepath.Base(m.store.Path()), ".enc")
+	}
+
+	noun := "services"
+	if len(m.services) == 1 {
+		noun = "service"
+	}
+	line := fmt.Sprintf("vault: %s • %d %s", vault, len(m.services), noun)
+	if len(m.filteredIndices) != len(m.services) {
+		line += fmt.Sprintf(" • %d shown", len(m.filteredIndices))
+	}
+	return line
+}
+
+// searchStatusLine renders the search/filter header for the current state:
+//   - search on:  "Search: <query>_" with the result count (query may be empty)
+//   - search off, query set: "Filter: <query>" with filtered/total counts
+//   - search off, no query: empty (all services shown)
+func (m Model) searchStatusLine() string {
+	query := truncateQuery(m.searchQuery, maxQueryDisplayRunes)
+
+	if m.searchMode {
+		searchText := searchQueryStyle.Render(fmt.Sprintf("Search: %s_", query))
+		return searchText + fmt.Sprintf("  (%d results)", len(m.filteredIndices))
+	}
+
+	if m.searchQuery != "" {
+		// Show active filter when not in search mode
+		filterText := searchQueryStyle.Render(fmt.Sprintf("Filter: %s", query))
+		return filterText + fmt.Sprintf("  (%d/%d services)", len(m.filteredIndices), len(m.services))
+	}
+
+	return ""
+}
+
+// maxQueryDisplayRunes keeps the search header on one line for long queries
+const maxQueryDisplayRunes = 40
+
+// truncateQuery shortens a query for display to its last max runes behind an
+// ellipsis, so the end being typed stays visible; matching uses the full query
+func truncateQuery(query string, max int) string {
+	runes := []rune(query)
+	if len(runes) <= max {
+		return query
+	}
+	return "…" + string(runes[len(runes)-(max-1):])
+}
+
+// truncateWidth shortens s to at most width terminal cells, ending in "...".
+// It cuts between runes, so multibyte and wide characters stay intact.
+func truncateWidth(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := lipgloss.Width(string(r))
+		if used+w > width-3 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String() + "..."
+}
+
+// renderDetails renders the details pane for a service.
+// Recovery codes are only listed once explicitly revealed.
+func (m Model) renderDetails(service storage.Service) string {
+	var b strings.Builder
+
+	identifier := service.Identifier
+	if identifier == "" {
+		identifier = "-"
+	}
+
+	loginURL := service.URL
+	if loginURL == "" {
+		loginURL = "-"
+	}
+
+	lastUsed := "never"
+	if service.LastUsed != nil {
+		lastUsed = service.LastUsed.Local().Format("2006-01-02 15:04")
+	}
+
+	b.WriteString(fmt.Sprintf("Name:        %s\n", service.Name))
+	b.WriteString(fmt.Sprintf("Identifier:  %s\n", identifier))
+	b.WriteString(fmt.Sprintf("URL:         %s\n", loginURL))
+	b.WriteString(fmt.Sprintf("Created:     %s\n", service.CreatedAt.Local().Format("2006-01-02 15:04")))
+	b.WriteString(fmt.Sprintf("Last used:   %s\n", lastUsed))
+
+	switch {
+	case len(service.Recovery) == 0:
+		b.WriteString("Recovery:    none")
+	case m.revealRecovery:
+		b.WriteString("Recovery:")
+		for _, code := range service.Recovery {
+			b.WriteString("\n  " + code)
+		}
+	default:
+		b.WriteString(fmt.Sprintf("Recovery:    %d codes (press 'r' to reveal)", len(service.Recovery)))
+	}
+
+	return borderStyle.Render(b.String())
+}
+
+// timerLine renders the countdown, or with showExpiresAt the clock time the
+// selected service's code expires (using its own period)
+func (m Model) timerLine() string {
+	if !m.showExpiresAt {
+		return fmt.Sprintf("⏱  Refreshing in %ds", m.remainingTime)
+	}
+
+	period := totpPeriod
+	if service, ok := m.selectedService(); ok {
+		period = service.EffectivePeriod()
+	}
+	return fmt.Sprintf("⏱  Expires %s", expiresAt(m.now(), period).Format("15:04:05"))
+}
+
+// progressBarWidth is the number of cells in the countdown bar
+const progressBarWidth = 20
+
+// progressFraction returns the fraction of the period remaining for the bar.
+// With sub-second frames it is smooth; otherwise it follows the countdown.
+func (m Model) progressFraction() float64 {
+	if m.frameInterval > 0 {
+		return m.barFraction
+	}
+	return float64(m.remainingTime) / totpPeriod
+}
+
+// renderProgressBar renders a fixed-width bar filled to the given fraction
+func renderProgressBar(fraction float64, width int) string {
+	if fraction < 0 {
+		fraction = 0
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	filled := int(fraction*float64(width) + 0.5)
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// codePlaceholder returns a dash placeholder matching the service's code length
+func codePlaceholder(length int) string {
+	return strings.Repeat("-", length)
+}
+
+// rowCodeStyle returns the code column style; failed codes use the warning
+// color so they stand out from codes not generated yet
+func rowCodeStyle(selected, failed bool) lipgloss.Style {
+	switch {
+	case failed:
+		return errorCodeStyle
+	case selected:
+		return selectedCodeStyle
+	default:
+		return codeStyle
+	}
+}
+
+// renderServiceRow renders a service line, flagging a failed code.
+// With identifiers hidden the name takes the identifier column's space.
+func (m Model) renderServiceRow(name, identifier, code string, selected, failed bool) string {
 	// Column widths
 	nameWidth := 25
 	identifierWidth := 35
-
-	// Truncate name if too long
-	if len(name) > nameWidth {
-		name = name[:nameWidth-3] + "..."
+	if m.hideIdentifiers {
+		nameWidth += identifierWidth + 2
 	}
 
-	// Truncate identifier if too long
-	if len(identifier) > identifierWidth {
-		identifier = identifier[:identifierWidth-3] + "..."
-	}
+	// Truncate name and identifier to their column's display width
+	name = truncateWidth(name, nameWidth)
+	identifier = truncateWidth(identifier, identifierWidth)
 
 	// Format identifier (empty if not set)
 	identifierDisplay := identifier
@@ -148,19 +438,17 @@ func (m Model) renderServiceLine(name, identifier, code string, selected bool) s
 		identifierDisplay = "-"
 	}
 
+	// Selected row: full-width highlight; normal row: colored text in box
+	nameStyle, identifierStyle, rowStyle := serviceNameStyle, lipgloss.NewStyle().Foreground(colorMuted), itemStyle
 	if selected {
-		// Selected row: full-width highlight
-		nameStr := selectedServiceNameStyle.Width(nameWidth).Render(name)
-		identifierStr := selectedServiceNameStyle.Width(identifierWidth).Render(identifierDisplay)
-		codeStr := selectedCodeStyle.Render(code)
-		line := lipgloss.JoinHorizontal(lipgloss.Top, nameStr, "  ", identifierStr, "  ", codeStr)
-		return selectedItemStyle.Render(line)
-	}
-
-	// Normal row: colored text in box
-	nameStr := serviceNameStyle.Width(nameWidth).Render(name)
-	identifierStr := lipgloss.NewStyle().Width(identifierWidth).Foreground(colorMuted).Render(identifierDisplay)
-	codeStr := codeStyle.Render(code)
-	line := lipgloss.JoinHorizontal(lipgloss.Top, nameStr, "  ", identifierStr, "  ", codeStr)
-	return itemStyle.Render(line)
+		nameStyle, identifierStyle, rowStyle = selectedServiceNameStyle, selectedServiceNameStyle, selectedItemStyle
+	}
+
+	columns := []string{nameStyle.Width(nameWidth).Render(name), "  "}
+	if !m.hideIdentifiers {
+		columns = append(columns, identifierStyle.Width(identifierWidth).Render(identifierDisplay), "  ")
+	}
+	columns = append(columns, rowCodeStyle(selected, failed).Render(code))
+
+	return rowStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top, columns...))
 }
@@ -99,9 +99,9 @@ func TestStorageCorrectVsWrongPassphrase(t *testing.T) {
 		t.Errorf("Load() with correct passphrase failed: %v", err)
 	} else {
 		// Verify data integrity
-		svc, err := correctStore.GetService("TestService")
+		svc, err := correctStore.GetServiceCopy("TestService")
 		if err != nil {
-			t.Errorf("GetService() failed: %v", err)
+			t.Errorf("GetServiceCopy() failed: %v", err)
 		}
 		if svc.Secret != "JBSWY3DPEHPK3PXP" {
 			t.Errorf("Expected secret JBSWY3DPEHPK3PXP, got %s", svc.Secret)